@@ -5,8 +5,10 @@ import (
 	"fmt"
 	. "github.com/nfk93/blockchain/crypto"
 	o "github.com/nfk93/blockchain/objects"
+	"github.com/nfk93/blockchain/store"
 	"github.com/nfk93/blockchain/transaction"
 	"log"
+	"runtime"
 	"sort"
 	"strconv"
 	"sync"
@@ -28,25 +30,90 @@ type FinalData struct {
 	totalstake      uint64
 	leadershipNonce string
 	blockHash       string
+	stateHash       string            // block the stake was computed from the state of, the block finalized before
+	bakers          map[string]string // accounts keyed by the hash of their registered baking key
+	hasBakingKey    map[string]bool   // accounts that registered a baking key, so they can't bake themselves
+}
+
+// stakeOf returns the stake key bakes with. A registered baking key bakes with the stake of its account, and an
+// account that registered a baking key doesn't bake with its own key
+func (fd FinalData) stakeOf(key PublicKey) uint64 {
+	if account, registered := fd.bakers[key.Hash()]; registered {
+		return fd.stake[account]
+	}
+	if fd.hasBakingKey[key.Hash()] {
+		return 0
+	}
+	return fd.stake[key.Hash()]
 }
 
 var finalData = make(map[uint64]FinalData)
 var finalLock sync.RWMutex
 
-func runSlot() { //Calls drawLottery every slot and increments the currentSlot after slotLength time.
-	currentSlot = 1
-	offset := time.Since(genesisTime)
-	for {
-		if (currentSlot)%epochLength == 0 {
-			finalizeSlot := int(currentSlot) - int(finalizeGap)
+var syncing bool
+var syncLock sync.RWMutex
+
+// maxBlockLead is how far ahead of ours the clock of another node can be. Its blocks of a slot that hasn't started
+// yet wait in pendingBlocks until it does, while blocks further ahead are dropped
+const maxBlockLead = 10 * time.Minute
+
+var keepStates uint64 // states of finalized blocks kept when pruning, all of them if 0
+var prunedSlot uint64 // slot of the block pruned to last. Blocks of this slot or earlier that we don't have are on pruned forks
+
+// SetPruning makes the node keep only the states of the last keep finalized blocks, instead of the state after every
+// block. Blocks on abandoned forks are pruned either way. Must be called before StartConsensus
+func SetPruning(keep uint64) {
+	keepStates = keep
+}
+
+// SetSyncing pauses the slots while the node catches up with the chain of its peers, so it doesn't finalize an epoch or
+// bake on an outdated head. The slots that passed in the meantime are caught up with when it's set to false again
+func SetSyncing(b bool) {
+	syncLock.Lock()
+	defer syncLock.Unlock()
+	syncing = b
+}
+
+func isSyncing() bool {
+	syncLock.RLock()
+	defer syncLock.RUnlock()
+	return syncing
+}
+
+// runSlot runs the slots from the given one on. Slot n starts n-1 slot durations after the genesis time, so every node
+// is in the same slot, however late it received the genesis block. Slots that have already passed, because the node
+// joined the network or was restarted after they started, are only caught up with: their epochs are finalized and
+// their blocks added, but the node doesn't take part in their lottery. No slot is run while the node syncs with its
+// peers, so the epochs are finalized on the chain it synced
+func runSlot(from uint64) {
+	for slot := from; ; slot++ {
+		for isSyncing() {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if wait := time.Until(slotStart(slot)); wait > 0 {
+			time.Sleep(wait)
+		}
+		func() {
+			slotLock.Lock()
+			defer slotLock.Unlock()
+			currentSlot = slot
+		}()
+		// the slot is stored before the lottery, so a restarted node never bakes in a slot twice
+		if err := db.Put(store.Meta, "slot", []byte(strconv.FormatUint(slot, 10))); err != nil {
+			log.Println("Couldn't store the current slot:", err)
+		}
+		if slot%epochLength == 0 {
+			finalizeSlot := int(slot) - int(finalizeGap)
 			if finalizeSlot > 0 {
-				finalize(currentSlot - (finalizeGap))
+				finalize(slot - (finalizeGap))
 			} else {
 				finalize(0)
 			}
 		}
-		drawLottery(currentSlot)
-		timeSinceGenesis := time.Since(genesisTime) - offset
+		evictTransactions(slot)
+		if slot == slotAt(time.Now()) {
+			drawLottery(slot)
+		}
 		if saveGraphFiles {
 			go func() {
 				blocks.rlock()
@@ -55,7 +122,7 @@ func runSlot() { //Calls drawLottery every slot and increments the currentSlot a
 				for k, v := range blocks.m {
 					copy_[k] = v
 				}
-				err := printBlockTreeGraphToFile(fmt.Sprintf("slot%d", currentSlot), copy_)
+				err := printBlockTreeGraphToFile(fmt.Sprintf("slot%d", slot), copy_)
 				if err != nil {
 					log.Println(fmt.Sprintf("error saving tree: %s", err.Error()))
 				}
@@ -64,14 +131,22 @@ func runSlot() { //Calls drawLottery every slot and increments the currentSlot a
 		func() {
 			checkPendingBlocks()
 		}()
-		sleepyTime := time.Duration(currentSlot)*slotLength - timeSinceGenesis
-		if sleepyTime > 0 {
-			time.Sleep(sleepyTime)
-		}
-		currentSlot += 1
 	}
 }
 
+// slotAt returns the slot running at t, 0 before the genesis time
+func slotAt(t time.Time) uint64 {
+	if t.Before(genesisTime) {
+		return 0
+	}
+	return uint64(t.Sub(genesisTime)/slotLength) + 1
+}
+
+// slotStart returns the time slot starts at
+func slotStart(slot uint64) time.Time {
+	return genesisTime.Add(time.Duration(slot-1) * slotLength)
+}
+
 func getCurrentSlot() uint64 {
 	slotLock.RLock()
 	defer slotLock.RUnlock()
@@ -84,17 +159,104 @@ func getEpoch(slot uint64) uint64 {
 }
 
 func processGenesisData(genesisData o.GenesisData, blockHash string) {
-	// TODO  -  Use GenesisTime when going away from two-phase implementation
+	setGenesisParameters(genesisData)
+	finalData[0] = getFinalData(genesisData.InitialState, genesisData.Nonce, blockHash, blockHash)
+	saveFinalRecord(0, genesisData.Nonce, blockHash, blockHash)
+	go runSlot(1)
+	go transaction.StartTransactionLayer(channels, saveGraphFiles, db)
+}
+
+func setGenesisParameters(genesisData o.GenesisData) {
 	hardness = genesisData.Hardness
 	slotLength = genesisData.SlotDuration
-	genesisFinalData := FinalData{stake: genesisData.InitialState.Ledger, totalstake: genesisData.InitialState.TotalStake,
-		leadershipNonce: genesisData.Nonce, blockHash: blockHash}
-	finalData[0] = genesisFinalData
 	finalizeGap = genesisData.FinalizeGap
 	epochLength = genesisData.EpochLength
 	genesisTime = genesisData.GenesisTime
-	go runSlot()
-	go transaction.StartTransactionLayer(channels, saveGraphFiles)
+}
+
+func saveFinalRecord(epoch uint64, leadershipNonce, blockHash, stateHash string) {
+	record := o.FinalRecord{LeadershipNonce: leadershipNonce, BlockHash: blockHash, StateHash: stateHash}
+	if err := db.Put(store.Final, strconv.FormatUint(epoch, 10), record.Encode()); err != nil {
+		log.Println("Couldn't store the finalized data of epoch", epoch, err)
+	}
+}
+
+// restore rebuilds the block tree, the transactions, the finalized data and the head from db, and continues the
+// protocol from the slot after the last one the node ran. The transaction layer restores its states itself.
+// Returns false if db holds no chain
+func restore() bool {
+	for _, hash := range db.Keys(store.Blocks) {
+		data, _ := db.Get(store.Blocks, hash)
+		b, err := o.DecodeBlock(data)
+		if err != nil {
+			log.Fatal("Couldn't decode stored block ", hash, ": ", err)
+		}
+		blocks.m[hash] = b
+		for _, t := range b.BlockData.Trans {
+			transactions[t.Hash()] = t
+			unusedTransactions[t.Hash()] = true
+		}
+	}
+	var genesis o.Block
+	for _, b := range blocks.m {
+		if b.Slot == 0 {
+			genesis = b
+		}
+	}
+	if len(blocks.m) == 0 {
+		return false
+	} else if genesis.BlockData.GenesisData.EpochLength == 0 {
+		log.Fatal("The data directory holds blocks, but not the genesis block")
+	}
+	setGenesisParameters(genesis.BlockData.GenesisData)
+	go transaction.StartTransactionLayer(channels, saveGraphFiles, db)
+
+	// the epochs are finalized again in order, as the transaction layer forgets contracts when a block is finalized
+	var epochs []uint64
+	for _, key := range db.Keys(store.Final) {
+		epoch, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			log.Fatal("Couldn't parse the epoch of stored finalized data ", key)
+		}
+		epochs = append(epochs, epoch)
+	}
+	sort.Slice(epochs, func(i, j int) bool { return epochs[i] < epochs[j] })
+	for _, epoch := range epochs {
+		data, _ := db.Get(store.Final, strconv.FormatUint(epoch, 10))
+		record, err := o.DecodeFinalRecord(data)
+		if err != nil {
+			log.Fatal("Couldn't decode the finalized data of epoch ", epoch, ": ", err)
+		}
+		channels.FinalizeToTrans <- record.StateHash
+		state := <-channels.StateFromTrans
+		finalData[epoch] = getFinalData(state, record.LeadershipNonce, record.BlockHash, record.StateHash)
+	}
+
+	prunedSlot = blocks.get(finalData[lastFinalizedEpoch()].blockHash).Slot
+
+	head := genesis.CalculateBlockHash()
+	if stored, ok := db.Get(store.Meta, "head"); ok && blocks.contains(string(stored)) {
+		head = string(stored)
+	}
+	currentHead = head
+	headBlock := blocks.get(head)
+	for b := headBlock; b.Slot > 0; b = blocks.get(b.ParentPointer) {
+		for _, t := range b.BlockData.Trans {
+			delete(unusedTransactions, t.Hash())
+		}
+	}
+	if headBlock.Slot > 0 {
+		currentLength = lengthToLastFinal(headBlock)
+	}
+
+	lastSlot := uint64(0)
+	if stored, ok := db.Get(store.Meta, "slot"); ok {
+		lastSlot, _ = strconv.ParseUint(string(stored), 10, 64)
+	}
+	genesisReceived = true
+	log.Printf("Restored %d blocks from the data directory, continuing from slot %d\n", len(blocks.m), lastSlot+1)
+	go runSlot(lastSlot + 1)
+	return true
 }
 
 func finalize(slot uint64) {
@@ -120,13 +282,15 @@ func finalize(slot uint64) {
 					newNonce := newLeadershipNonce(head)
 					channels.FinalizeToTrans <- finalHash
 					state := <-channels.StateFromTrans
-					finData := getFinalData(state, newNonce, head.CalculateBlockHash())
+					finData := getFinalData(state, newNonce, head.CalculateBlockHash(), finalHash)
 					finalData[epoch] = finData
+					saveFinalRecord(epoch, newNonce, head.CalculateBlockHash(), finalHash)
 					break
 				}
 				head = blocks.get(head.ParentPointer)
 			}
 		}()
+		prune()
 	}
 	if isVerbose {
 		log.Println(fmt.Sprintf("Finalized slot %d successfully", slot))
@@ -134,15 +298,150 @@ func finalize(slot uint64) {
 	}
 }
 
-func getFinalData(state o.State, leadershipNonce, blockHash string) FinalData {
-	m := state.Ledger
+// prune forgets the blocks on forks that don't extend the block finalized last, together with their states, as no
+// block of a later slot can build on them. A node that doesn't keep every state also forgets the states of the
+// finalized blocks before the last keepStates, and the finalized data of the epochs no new block is validated with.
+// In verbose mode the memory in use before and after is logged
+func prune() {
+	before := heapInUse()
+	var prunedBlocks, prunedStates, prunedEpochs []string
+	func() {
+		blocks.lock()
+		defer blocks.unlock()
+		finalLock.Lock()
+		defer finalLock.Unlock()
+		finalHash := finalData[lastFinalizedEpoch()].blockHash
+		final := blocks.get(finalHash)
+
+		finalized := make(map[string]bool)
+		for hash, i := finalHash, uint64(0); blocks.contains(hash); hash, i = blocks.get(hash).ParentPointer, i+1 {
+			finalized[hash] = true
+			if keepStates > 0 && i >= keepStates {
+				prunedStates = append(prunedStates, hash)
+			}
+		}
+		for hash, b := range blocks.m {
+			if !finalized[hash] && !extends(b, final) {
+				prunedBlocks = append(prunedBlocks, hash)
+			}
+		}
+		for _, hash := range prunedBlocks {
+			delete(blocks.m, hash)
+		}
+		prunedSlot = final.Slot
+		// blocks at or below the slot pruned to are dropped anyway
+		for hash, slot := range invalidBlocks {
+			if slot <= prunedSlot {
+				delete(invalidBlocks, hash)
+			}
+		}
+
+		if keepStates > 0 {
+			// the finalized data we keep is restored on a restart from the states its stake was computed from, and
+			// the finalized blocks are needed to check the blocks built on them
+			oldest := getFinalDataIndex(prunedSlot + 1)
+			keep := make(map[string]bool)
+			for epoch, fd := range finalData {
+				if epoch < oldest {
+					delete(finalData, epoch)
+					prunedEpochs = append(prunedEpochs, strconv.FormatUint(epoch, 10))
+				} else {
+					keep[fd.blockHash] = true
+					keep[fd.stateHash] = true
+				}
+			}
+			var states []string
+			for _, hash := range prunedStates {
+				if !keep[hash] {
+					states = append(states, hash)
+				}
+			}
+			prunedStates = states
+		}
+	}()
+	func() {
+		pendingBlocksLock.Lock()
+		defer pendingBlocksLock.Unlock()
+		var pending []o.Block
+		for _, b := range pendingBlocks {
+			if b.Slot > prunedSlot {
+				pending = append(pending, b)
+			}
+		}
+		pendingBlocks = pending
+	}()
+
+	channels.PruneToTrans <- append(prunedBlocks, prunedStates...)
+	statesPruned := <-channels.PrunedFromTrans
+	if err := db.Delete(store.Blocks, prunedBlocks...); err != nil {
+		log.Println("Couldn't delete the pruned blocks", err)
+	}
+	if err := db.Delete(store.Final, prunedEpochs...); err != nil {
+		log.Println("Couldn't delete the pruned finalized data", err)
+	}
+	if len(prunedBlocks) == 0 && statesPruned == 0 && len(prunedEpochs) == 0 {
+		return
+	}
+	// the store only rewrites its log once enough of it was deleted
+	if err := db.Compact(); err != nil {
+		log.Println("Couldn't compact the data directory", err)
+	}
+	if isVerbose {
+		log.Printf("Pruned %d blocks and %d states, memory in use went from %.1f MB to %.1f MB\n",
+			len(prunedBlocks), statesPruned, float64(before)/(1<<20), float64(heapInUse())/(1<<20))
+	}
+}
+
+// extends returns whether b is final or one of its descendants. PRECONDITION: blocks is read locked
+func extends(b, final o.Block) bool {
+	for b.Slot > final.Slot {
+		b = blocks.get(b.ParentPointer)
+	}
+	return b.CalculateBlockHash() == final.CalculateBlockHash()
+}
+
+// lastFinalizedEpoch returns the newest epoch we have the finalized data of. PRECONDITION: finalLock is read locked
+func lastFinalizedEpoch() uint64 {
+	last := uint64(0)
+	for epoch := range finalData {
+		if epoch > last {
+			last = epoch
+		}
+	}
+	return last
+}
+
+// heapInUse returns the bytes of the heap that are allocated, including garbage that wasn't collected yet. Only read in
+// verbose mode, as reading the statistics stops the world
+func heapInUse() uint64 {
+	if !isVerbose {
+		return 0
+	}
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+func getFinalData(state o.State, leadershipNonce, blockHash, stateHash string) FinalData {
+	m := state.Ledger()
 	// add contract accounts to owners mining pool
-	for k, v := range state.ConStake {
-		conowner := state.ConOwners[k]
+	for k, v := range state.ContractBalances() {
+		conowner, _ := state.ContractOwner(k)
 		conownerhash := conowner.Hash()
 		m[conownerhash] += v
 	}
-	return FinalData{stake: m, totalstake: state.TotalStake, leadershipNonce: leadershipNonce, blockHash: blockHash}
+	// add delegated stake to the stake of the baker
+	for delegator, baker := range state.Delegations() {
+		m[baker] += m[delegator]
+		delete(m, delegator)
+	}
+	bakers := state.Bakers()
+	hasBakingKey := make(map[string]bool)
+	for _, account := range bakers {
+		hasBakingKey[account] = true
+	}
+	return FinalData{stake: m, totalstake: state.TotalStake, leadershipNonce: leadershipNonce, blockHash: blockHash,
+		stateHash: stateHash, bakers: bakers, hasBakingKey: hasBakingKey}
 }
 
 func newLeadershipNonce(finalBlock o.Block) string {
@@ -178,13 +477,18 @@ func drawLottery(slot uint64) {
 
 //Sends all unused transactions to the transaction layer for the transaction layer to process for the new block
 func generateBlock(draw string, slot uint64, leadershipNonce, lastfinalized, parentHash string) {
+	blockNonce, err := o.CreateNewBlockNonce(leadershipNonce, sk, slot)
+	if err != nil {
+		log.Println("Couldn't create the block nonce:", err)
+		return
+	}
 	blockData := o.CreateBlockData{
 		getUnusedTransactions(),
 		sk,
 		pk,
 		slot,
 		draw,
-		o.CreateNewBlockNonce(leadershipNonce, sk, slot),
+		blockNonce,
 		lastfinalized,
 		parentHash}
 	channels.TransToTrans <- blockData
@@ -202,7 +506,7 @@ func getLotteryPower(pk PublicKey, slot uint64) float64 {
 		log.Println("ERROR: attempted getting lottery power from unfinalized epoch")
 		return 0
 	} else {
-		return float64(fd.stake[pk.Hash()]) / float64(fd.totalstake)
+		return float64(fd.stakeOf(pk)) / float64(fd.totalstake)
 	}
 }
 
@@ -218,6 +522,6 @@ func PrintCurrentStake() {
 	sort.Strings(keyList)
 
 	for _, k := range keyList {
-		log.Printf("Keyhash: %v, Stake: %v\n", k[:10], stake[k])
+		log.Printf("Address: %v, Stake: %v\n", o.AccountAddress(k), stake[k])
 	}
 }
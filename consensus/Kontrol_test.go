@@ -0,0 +1,264 @@
+package consensus
+
+import (
+	o "github.com/nfk93/blockchain/objects"
+	"sort"
+	"testing"
+	"time"
+)
+
+// pruneTestChain sets up the chain
+//
+//	g - a - b - c
+//	     \   \
+//	      x   y
+//
+// where b is finalized in epoch 1 with the stake of the state after a, and g in epoch 0. Returns the hashes of the
+// blocks by name
+func pruneTestChain() map[string]string {
+	g := o.Block{Slot: 0}
+	a := o.Block{Slot: 1, ParentPointer: g.CalculateBlockHash()}
+	b := o.Block{Slot: 2, ParentPointer: a.CalculateBlockHash()}
+	c := o.Block{Slot: 3, ParentPointer: b.CalculateBlockHash()}
+	x := o.Block{Slot: 2, ParentPointer: a.CalculateBlockHash(), Draw: "x"}
+	y := o.Block{Slot: 3, ParentPointer: b.CalculateBlockHash(), Draw: "y"}
+	named := map[string]o.Block{"g": g, "a": a, "b": b, "c": c, "x": x, "y": y}
+
+	db = nil
+	blocks = skov{m: make(map[string]o.Block)}
+	hashes := make(map[string]string)
+	for name, block := range named {
+		blocks.add(block)
+		hashes[name] = block.CalculateBlockHash()
+	}
+	finalizeGap = 1
+	epochLength = 2
+	finalData = map[uint64]FinalData{
+		0: {blockHash: g.CalculateBlockHash(), stateHash: g.CalculateBlockHash()},
+		1: {blockHash: b.CalculateBlockHash(), stateHash: a.CalculateBlockHash()},
+	}
+	prunedSlot = 0
+	pendingBlocks = nil
+	parentRequests = nil
+	invalidBlocks = make(map[string]uint64)
+	return hashes
+}
+
+// fakeTransactionLayer answers the prune requests of consensus, and sends the hashes it was asked to prune on pruned
+func fakeTransactionLayer() (pruned chan []string) {
+	channels = o.CreateChannelStruct()
+	pruned = make(chan []string, 1)
+	go func() {
+		hashes := <-channels.PruneToTrans
+		pruned <- hashes
+		channels.PrunedFromTrans <- len(hashes)
+	}()
+	return pruned
+}
+
+func TestExtends(t *testing.T) {
+	chain := pruneTestChain()
+	for name, want := range map[string]bool{"b": true, "c": true, "y": true, "a": false, "x": false} {
+		if extends(blocks.get(chain[name]), blocks.get(chain["b"])) != want {
+			t.Errorf("extends(%s, b) should be %v", name, want)
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	chain := pruneTestChain()
+	keepStates = 1
+	defer func() { keepStates = 0 }()
+	pending := o.Block{Slot: 5, ParentPointer: "unknown"}
+	pendingBlocks = []o.Block{{Slot: 1, ParentPointer: "unknown"}, pending}
+	pruned := fakeTransactionLayer()
+
+	prune()
+
+	if prunedSlot != blocks.get(chain["b"]).Slot {
+		t.Errorf("Expected to have pruned to slot %d, got %d", blocks.get(chain["b"]).Slot, prunedSlot)
+	}
+	for _, name := range []string{"g", "a", "b", "c", "y"} {
+		if !blocks.contains(chain[name]) {
+			t.Errorf("Block %s should be kept", name)
+		}
+	}
+	if blocks.contains(chain["x"]) {
+		t.Error("Block x is on a fork that doesn't extend the finalized block, so it should be pruned")
+	}
+	if _, exists := finalData[0]; exists {
+		t.Error("The finalized data of epoch 0 isn't needed anymore")
+	}
+	if len(pendingBlocks) != 1 || pendingBlocks[0].CalculateBlockHash() != pending.CalculateBlockHash() {
+		t.Error("Only the pending blocks after the slot pruned to should be kept")
+	}
+
+	// the states of a and b are kept, as the finalized data of epoch 1 is restored from the state of a
+	hashes := <-pruned
+	sort.Strings(hashes)
+	want := []string{chain["g"], chain["x"]}
+	sort.Strings(want)
+	if len(hashes) != len(want) || hashes[0] != want[0] || hashes[1] != want[1] {
+		t.Errorf("Expected the states of g and x to be pruned, got %v", hashes)
+	}
+}
+
+func TestPruneKeepAllStates(t *testing.T) {
+	chain := pruneTestChain()
+	pruned := fakeTransactionLayer()
+
+	prune()
+
+	hashes := <-pruned
+	if len(hashes) != 1 || hashes[0] != chain["x"] {
+		t.Errorf("Only block x and its state should be pruned when keeping every state, got %v", hashes)
+	}
+	if len(finalData) != 2 {
+		t.Error("The finalized data is kept when keeping every state")
+	}
+}
+
+func TestHandleBlockDropsPrunedSlots(t *testing.T) {
+	chain := pruneTestChain()
+	prunedSlot = blocks.get(chain["b"]).Slot
+	genesisReceived = true
+	genesisTime = time.Now().Add(-time.Hour)
+	slotLength = time.Second
+	currentSlot = 10
+	channels = o.CreateChannelStruct()
+	go func(requests chan string) {
+		for range requests {
+		}
+	}(channels.BlockRequest)
+
+	// a block we don't have of a slot we pruned to is on a pruned fork, while a later one waits for its parent
+	old := o.Block{Slot: prunedSlot, ParentPointer: "unknown"}
+	later := o.Block{Slot: prunedSlot + 1, ParentPointer: "unknown"}
+	handleBlock(old)
+	handleBlock(later)
+
+	if blocks.contains(old.CalculateBlockHash()) {
+		t.Error("A block of a pruned slot shouldn't be added")
+	}
+	if len(pendingBlocks) != 1 || pendingBlocks[0].CalculateBlockHash() != later.CalculateBlockHash() {
+		t.Error("Only the block after the pruned slot should wait for its parent")
+	}
+}
+
+func TestHandleBlockFromTheFuture(t *testing.T) {
+	chain := pruneTestChain()
+	genesisReceived = true
+	genesisTime = time.Now().Add(-time.Hour)
+	slotLength = time.Second
+	currentSlot = slotAt(time.Now())
+	channels = o.CreateChannelStruct()
+
+	// a block of a slot that hasn't started yet waits for it, while one too far ahead is dropped and forgotten
+	early := o.Block{Slot: currentSlot + 5, ParentPointer: chain["c"]}
+	late := o.Block{Slot: slotAt(time.Now().Add(2 * maxBlockLead)), ParentPointer: chain["c"]}
+	handleBlock(early)
+	handleBlock(late)
+
+	if len(pendingBlocks) != 1 || pendingBlocks[0].CalculateBlockHash() != early.CalculateBlockHash() {
+		t.Error("A block of a slot that starts soon should wait for its slot")
+	}
+	select {
+	case hash := <-channels.BlockDropped:
+		if hash != late.CalculateBlockHash() {
+			t.Error("The dropped block should be forgotten by the P2P layer")
+		}
+	case <-time.After(time.Second):
+		t.Error("The P2P layer should be told about the dropped block")
+	}
+}
+
+func TestCheckPendingBlocksRequestsParents(t *testing.T) {
+	pruneTestChain()
+	prunedSlot = 2
+	currentSlot = 10
+	slotLength = time.Millisecond
+	channels = o.CreateChannelStruct()
+	requests := make(chan string, 100)
+	go func(blockRequests chan string) {
+		for hash := range blockRequests {
+			requests <- hash
+		}
+	}(channels.BlockRequest)
+
+	orphan := o.Block{Slot: 5, ParentPointer: "missing"}
+	pendingBlocks = []o.Block{{Slot: 2, ParentPointer: "missing"}, orphan}
+	checkPendingBlocks()
+	if len(pendingBlocks) != 1 || pendingBlocks[0].CalculateBlockHash() != orphan.CalculateBlockHash() {
+		t.Error("A pending block at or below the slot pruned to should be dropped")
+	}
+
+	// right after a request the parent isn't asked for again, and after maxParentRequests requests it is given up on
+	checkPendingBlocks()
+	for i := 0; i < 10*maxParentRequests && len(pendingBlocks) > 0; i++ {
+		time.Sleep(slotLength << uint(maxParentRequests))
+		checkPendingBlocks()
+	}
+	if len(pendingBlocks) != 0 {
+		t.Error("A block whose parent can't be fetched should be dropped")
+	}
+	if len(parentRequests) != 0 {
+		t.Error("The request of a parent no block waits for should be forgotten")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if len(requests) != maxParentRequests {
+		t.Errorf("Expected the parent to be asked for %d times, it was asked for %d times", maxParentRequests, len(requests))
+	}
+}
+
+func TestInvalidateBlock(t *testing.T) {
+	chain := pruneTestChain()
+	channels = o.CreateChannelStruct()
+	sent := make(chan string, 10)
+	go func(blockToTrans chan o.Block) {
+		for b := range blockToTrans {
+			sent <- b.CalculateBlockHash()
+		}
+	}(channels.BlockToTrans)
+	setCurrentHead(chain["c"])
+	currentLength = 4
+	trans := o.TransData{Transaction: o.Transaction{Amount: 1}}
+	transactions = map[string]o.TransData{trans.Hash(): trans}
+	unusedTransactions = make(map[string]bool)
+	c := blocks.get(chain["c"])
+	c.BlockData.Trans = []o.TransData{trans}
+	blocks.m[chain["c"]] = c
+	orphan := o.Block{Slot: 5, ParentPointer: "unknown"}
+	pendingBlocks = []o.Block{{Slot: 4, ParentPointer: chain["c"]}, orphan}
+
+	// b is invalid, so c and y building on it are dropped too, and the head moves to x, the longest chain left
+	invalidateBlock(chain["b"])
+
+	for _, name := range []string{"b", "c", "y"} {
+		if blocks.contains(chain[name]) {
+			t.Errorf("Block %s should be dropped", name)
+		}
+		if _, invalid := invalidBlocks[chain[name]]; !invalid {
+			t.Errorf("Block %s should be remembered as invalid", name)
+		}
+	}
+	if getCurrentHead() != chain["x"] {
+		t.Errorf("Expected the head to move to x, it is %s", getCurrentHead())
+	}
+	if !unusedTransactions[trans.Hash()] {
+		t.Error("The transactions of the dropped head should be unused again")
+	}
+	if len(pendingBlocks) != 1 || pendingBlocks[0].CalculateBlockHash() != orphan.CalculateBlockHash() {
+		t.Error("A pending block building on a dropped block should be dropped")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if len(sent) == 0 {
+		t.Error("The transaction layer should be sent the new head")
+	}
+
+	// a block building on a dropped block is never added
+	genesisReceived = true
+	handleBlock(o.Block{Slot: 4, ParentPointer: chain["y"]})
+	if len(pendingBlocks) != 1 || len(blocks.m) != 3 {
+		t.Error("A block building on an invalid block should be dropped")
+	}
+}
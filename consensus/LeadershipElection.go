@@ -2,17 +2,31 @@ package consensus
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
 	. "github.com/nfk93/blockchain/crypto"
+	"log"
 	"math"
 	"math/big"
 	"strconv"
+	"sync"
 )
 
+// drawErrorOnce logs that the key of the node can't draw once, instead of in every slot
+var drawErrorOnce sync.Once
+
+// CalculateDraw computes the draw of pk in slot, which is the proof of the VRF of sk on the leadership nonce and the
+// slot, and checks if it wins
 func CalculateDraw(hardness float64, sk SecretKey, pk PublicKey, slot uint64, fd FinalData) (bool, string) {
-	// Creates the draw signature
-	drawString := getDrawString(slot, fd.leadershipNonce)
-	draw := Sign(drawString, sk)
+	proof, err := sk.VRFProve([]byte(getDrawString(slot, fd.leadershipNonce)))
+	if err != nil {
+		drawErrorOnce.Do(func() {
+			log.Println("This node can't take part in the lottery:", err,
+				"- register an ed25519 baking key with \"baker register\" to bake with the stake of this account")
+		})
+		return false, ""
+	}
+	draw := hex.EncodeToString(proof)
 
 	if CheckIfWinner(draw, slot, pk, hardness, fd) {
 		return true, draw
@@ -22,8 +36,8 @@ func CalculateDraw(hardness float64, sk SecretKey, pk PublicKey, slot uint64, fd
 }
 
 func ValidateDraw(slot uint64, draw string, key PublicKey, fd FinalData, hardness float64) bool {
-	if !validateDrawSignature(key, slot, draw, fd.leadershipNonce) {
-		fmt.Println("Draw signature didn't validate...")
+	if !validateDrawProof(key, slot, draw, fd.leadershipNonce) {
+		fmt.Println("Draw proof didn't validate...")
 		return false
 	}
 
@@ -32,14 +46,15 @@ func ValidateDraw(slot uint64, draw string, key PublicKey, fd FinalData, hardnes
 	return isWinner
 }
 
+// CheckIfWinner checks if the draw of key wins slot. A registered baking key draws with the stake of its account
 func CheckIfWinner(draw string, slot uint64, key PublicKey, hardness float64, fd FinalData) bool {
-	amount := fd.stake[key.Hash()]
+	amount := fd.stakeOf(key)
 	stake := float64(amount) / float64(fd.totalstake)
 	phiFunc := float64(1) - math.Pow(float64(1)-hardness, stake)
 	multFactor := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(256)), nil)
 	threshold := new(big.Int)
 	new(big.Float).Mul(big.NewFloat(float64(phiFunc)), new(big.Float).SetInt(multFactor)).Int(threshold)
-	drawVal := calculateDrawValue(slot, draw, fd.leadershipNonce)
+	drawVal := calculateDrawValue(draw)
 	if drawVal.Cmp(threshold) == -1 {
 		return true
 	}
@@ -54,19 +69,26 @@ func getDrawString(slot uint64, leadershipNonce string) string {
 	return drawBuf.String()
 }
 
-func calculateDrawValue(slot uint64, draw string, leadershipNonce string) *big.Int {
-	// Calculates the draw value
-	var valBuf bytes.Buffer
-	valBuf.WriteString("LEADERSHIP_ELECTION")
-	valBuf.WriteString(leadershipNonce)
-	valBuf.WriteString(strconv.Itoa(int(slot)))
-	valBuf.WriteString(draw)
-	hashString := HashSHA(valBuf.String())
-	hashVal := big.NewInt(0)
-	hashVal.SetString(hashString, 16)
-	return hashVal
+// calculateDrawValue returns the first 256 bits of the VRF output of the draw. The value only depends on the output,
+// as there is only one output for a key and slot but a baker could make several proofs of it.
+// A draw that isn't a proof gets a value higher than any threshold
+func calculateDrawValue(draw string) *big.Int {
+	proof, err := hex.DecodeString(draw)
+	if err != nil {
+		return new(big.Int).Lsh(big.NewInt(1), 256)
+	}
+	output, err := VRFProofToHash(proof)
+	if err != nil {
+		return new(big.Int).Lsh(big.NewInt(1), 256)
+	}
+	return new(big.Int).SetBytes(output[:32])
 }
 
-func validateDrawSignature(key PublicKey, slot uint64, drawSignature, leadershipNonce string) bool {
-	return Verify(getDrawString(slot, leadershipNonce), drawSignature, key)
+func validateDrawProof(key PublicKey, slot uint64, draw, leadershipNonce string) bool {
+	proof, err := hex.DecodeString(draw)
+	if err != nil {
+		return false
+	}
+	_, err = key.VRFVerify([]byte(getDrawString(slot, leadershipNonce)), proof)
+	return err == nil
 }
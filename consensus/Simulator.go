@@ -1,10 +1,13 @@
 package consensus
 
 import (
+	"bytes"
 	"fmt"
+	. "github.com/nfk93/blockchain/crypto"
 	"math"
 	"math/big"
 	"math/rand"
+	"strconv"
 	"time"
 	"unsafe"
 )
@@ -40,8 +43,8 @@ func AdversaryCatchup(p float64, q float64, h float64, n int, nonce string) floa
 	adversaryWon := 0
 
 	for i := 0; i < n; i++ {
-		adversaryDrawVal := calculateDrawValue(uint64(i), adversaryDraw, nonce)
-		honestDrawVal := calculateDrawValue(uint64(i), honestDraw, nonce)
+		adversaryDrawVal := simulatedDrawValue(uint64(i), adversaryDraw, nonce)
+		honestDrawVal := simulatedDrawValue(uint64(i), honestDraw, nonce)
 		if adversaryDrawVal.Cmp(adversaryThreshold) == -1 {
 			adversaryWon += 1
 		}
@@ -59,6 +62,18 @@ func AdversaryCatchup(p float64, q float64, h float64, n int, nonce string) floa
 	}
 }
 
+// simulatedDrawValue stands in for the VRF output of a baker in slot, as the simulation has no keys
+func simulatedDrawValue(slot uint64, draw string, leadershipNonce string) *big.Int {
+	var valBuf bytes.Buffer
+	valBuf.WriteString("LEADERSHIP_ELECTION")
+	valBuf.WriteString(leadershipNonce)
+	valBuf.WriteString(strconv.Itoa(int(slot)))
+	valBuf.WriteString(draw)
+	hashVal := big.NewInt(0)
+	hashVal.SetString(HashSHA(valBuf.String()), 16)
+	return hashVal
+}
+
 func SimulateAdversaryCatchup(p float64, q float64, h float64, n int, simulations int) float64 {
 	honestWins := 0.0
 
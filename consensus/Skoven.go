@@ -4,14 +4,18 @@ import (
 	"fmt"
 	"github.com/nfk93/blockchain/crypto"
 	o "github.com/nfk93/blockchain/objects"
+	"github.com/nfk93/blockchain/store"
 	"github.com/nfk93/blockchain/transaction"
 	"io/ioutil"
 	"log"
+	"sort"
 	"sync"
+	"time"
 )
 
 var unusedTransactions map[string]bool
 var transactions map[string]o.TransData
+var evictedSenders map[string]bool // accounts that had transactions evicted since they last asked, see TakeEvicted
 var tLock sync.RWMutex
 var blocks skov
 var currentHead string
@@ -22,9 +26,14 @@ var isVerbose bool
 var saveGraphFiles bool
 var pendingBlocks []o.Block
 var pendingBlocksLock sync.Mutex
+var parentRequests map[string]*parentRequest // missing parents of pending blocks, guarded by pendingBlocksLock
+var invalidBlocks map[string]uint64          // slots of the blocks found to be invalid, guarded by blocks
 var genesisReceived = false
+var db *store.Store
 
-func StartConsensus(channelStruct o.ChannelStruct, pkey crypto.PublicKey, skey crypto.SecretKey, verbose, saveGraphsToFile bool) {
+// StartConsensus starts the consensus layer. If db holds a chain, the node continues from it instead of waiting for
+// the genesis block, and true is returned. db is nil if the node keeps nothing on disk
+func StartConsensus(channelStruct o.ChannelStruct, pkey crypto.PublicKey, skey crypto.SecretKey, verbose, saveGraphsToFile bool, db_ *store.Store) bool {
 	pk = pkey
 	sk = skey
 	isVerbose = verbose
@@ -32,8 +41,13 @@ func StartConsensus(channelStruct o.ChannelStruct, pkey crypto.PublicKey, skey c
 	channels = channelStruct
 	unusedTransactions = make(map[string]bool)
 	transactions = make(map[string]o.TransData)
+	evictedSenders = make(map[string]bool)
 	pendingBlocks = make([]o.Block, 0)
+	parentRequests = make(map[string]*parentRequest)
+	invalidBlocks = make(map[string]uint64)
 	blocks.m = make(map[string]o.Block)
+	db = db_
+	restored := restore()
 
 	// Start processing blocks on one thread, non-concurrently
 	go func() {
@@ -42,54 +56,82 @@ func StartConsensus(channelStruct o.ChannelStruct, pkey crypto.PublicKey, skey c
 			handleBlock(block)
 		}
 	}()
+	// The transaction layer tells us about the blocks whose state turned out to be invalid
+	go func() {
+		for {
+			hash := <-channels.InvalidFromTrans
+			invalidateBlock(hash)
+		}
+	}()
 	// Start processing transactions on one thread, concurrently
 	go func() {
 		for {
 			trans := <-channels.TransFromP2P
-			if isVerbose {
-				if trans.GetType() == o.TRANSACTION {
-					log.Printf("received transaction: %d from %s to %s.\n", trans.Transaction.Amount, trans.Transaction.From.Hash()[0:6], trans.Transaction.To.Hash()[0:6])
-				}
-				if trans.GetType() == o.CONTRACTCALL {
-					log.Printf("received contractcall with signature %s\n", trans.ContractCall.Signature[:6]+"...")
-				}
-				if trans.GetType() == o.CONTRACTINIT {
-					log.Printf("received contractinit with signature %s\n", trans.ContractInit.Signature[:6]+"...")
-				}
-			}
 			go handleTransData(trans)
 		}
 	}()
+	return restored
 }
 
+// checkPendingBlocks adds the pending blocks that can be added now. Blocks at or below the slot we pruned to are on
+// pruned forks, and blocks whose parent couldn't be fetched are given up on, so they don't stay pending forever
 func checkPendingBlocks() {
 	foundBlockToAdd := false
 	func() {
 		pendingBlocksLock.Lock()
 		defer pendingBlocksLock.Unlock()
+		blocks.rlock()
+		pruned := prunedSlot
+		blocks.runlock()
+		var waiting []o.Block
 		for i, block := range pendingBlocks {
+			if block.Slot <= pruned {
+				continue
+			}
+			if !blocks.contains(block.ParentPointer) {
+				if requestParent(block.ParentPointer) {
+					waiting = append(waiting, block)
+				} else if isVerbose {
+					log.Println(fmt.Sprintf("dropping block %s, its parent %s couldn't be fetched",
+						shortHash(block.CalculateBlockHash()), shortHash(block.ParentPointer)))
+				}
+				continue
+			}
 			finalexists := false
 			func() {
 				finalLock.RLock()
 				defer finalLock.RUnlock()
 				_, finalexists = finalData[getFinalDataIndex(block.Slot)]
 			}()
-			if block.Slot <= getCurrentSlot() && blocks.contains(block.ParentPointer) && finalexists {
-				if !ValidateBlock(block) {
-					pendingBlocks = append(pendingBlocks[:i], pendingBlocks[i+1:]...)
-					if isVerbose {
-						log.Println("Consensus could not validate block:", block.CalculateBlockHash())
-					}
-				} else {
-					func() {
-						blocks.lock()
-						defer blocks.unlock()
-						addBlock(block)
-					}()
-					foundBlockToAdd = true
-					pendingBlocks = append(pendingBlocks[:i], pendingBlocks[i+1:]...)
-					break
+			if block.Slot > getCurrentSlot() || !finalexists {
+				waiting = append(waiting, block)
+				continue
+			}
+			if !ValidateBlock(block) {
+				if isVerbose {
+					log.Println("Consensus could not validate block:", block.CalculateBlockHash())
 				}
+				continue
+			}
+			func() {
+				blocks.lock()
+				defer blocks.unlock()
+				addBlock(block)
+			}()
+			foundBlockToAdd = true
+			waiting = append(waiting, pendingBlocks[i+1:]...)
+			break
+		}
+		pendingBlocks = waiting
+
+		// the requests of parents no pending block waits for anymore are forgotten
+		awaited := make(map[string]bool)
+		for _, block := range pendingBlocks {
+			awaited[block.ParentPointer] = true
+		}
+		for hash := range parentRequests {
+			if !awaited[hash] {
+				delete(parentRequests, hash)
 			}
 		}
 	}()
@@ -118,6 +160,16 @@ func ValidateBlock(b o.Block) bool {
 		return false
 	}
 
+	// validate that no transaction has expired before the slot of the block
+	for _, t := range b.BlockData.Trans {
+		if t.Expired(b.Slot) {
+			if isVerbose {
+				log.Println("block includes an expired transaction ", b.CalculateBlockHash())
+			}
+			return false
+		}
+	}
+
 	// get relevant finaldata
 	finalEpoch := getFinalDataIndex(b.Slot)
 	finalLock.RLock()
@@ -200,9 +252,22 @@ func checkLastFinalizedValidity(block o.Block, finalEpoch uint64, data FinalData
 func handleTransData(t o.TransData) {
 	tLock.Lock()
 	defer tLock.Unlock()
-	if t.Verify() != true {
+	if t.Verify() != true || t.Expired(getCurrentSlot()) {
 		return
 	}
+	// logged once verified, but the fields are still whatever the sender signed
+	if isVerbose {
+		if t.GetType() == o.TRANSACTION {
+			log.Printf("received transaction: %d from %s to %s.\n", t.Transaction.Amount,
+				shortHash(t.Transaction.From.Hash()), shortHash(t.Transaction.To))
+		}
+		if t.GetType() == o.CONTRACTCALL {
+			log.Printf("received contractcall with signature %s\n", shortHash(t.ContractCall.Signature)+"...")
+		}
+		if t.GetType() == o.CONTRACTINIT {
+			log.Printf("received contractinit with signature %s\n", shortHash(t.ContractInit.Signature)+"...")
+		}
+	}
 	transhash := t.Hash()
 	_, alreadyReceived := transactions[transhash]
 	if !alreadyReceived {
@@ -211,6 +276,14 @@ func handleTransData(t o.TransData) {
 	}
 }
 
+// shortHash returns the first 6 characters of a hash for logging, or all of it if it is shorter
+func shortHash(hash string) string {
+	if len(hash) < 6 {
+		return hash
+	}
+	return hash[:6]
+}
+
 //Verifies the block signature and the draw value of a block, and calls addBlock if successful.
 func handleBlock(b o.Block) {
 	if isVerbose {
@@ -236,6 +309,36 @@ func handleBlock(b o.Block) {
 	func() {
 		blocks.rlock()
 		defer blocks.runlock()
+		// a restarted node can receive blocks it stored before it was stopped
+		if blocks.contains(b.CalculateBlockHash()) {
+			done = true
+			return
+		}
+		// we have every block up to the one we pruned to, so an older block we don't have is on a pruned fork
+		if b.Slot <= prunedSlot {
+			done = true
+			return
+		}
+		// a block found to be invalid is never added again, and neither is a block building on one
+		_, invalid := invalidBlocks[b.CalculateBlockHash()]
+		_, parentInvalid := invalidBlocks[b.ParentPointer]
+		if invalid || parentInvalid {
+			if isVerbose {
+				log.Println(fmt.Sprintf("dropping block %s, it is or builds on an invalid block",
+					shortHash(b.CalculateBlockHash())))
+			}
+			done = true
+			return
+		}
+		if b.Slot > slotAt(time.Now().Add(maxBlockLead)) {
+			if isVerbose {
+				log.Println(fmt.Sprintf("dropping block %s, its slot (%d) is too far in the future",
+					b.CalculateBlockHash()[:6]+"...", b.Slot))
+			}
+			dropBlock(b.CalculateBlockHash())
+			done = true
+			return
+		}
 		finalLock.RLock()
 		defer finalLock.RUnlock()
 		// check if the parent of a block exists, and if it doesn't it adds it to pendingblocks
@@ -245,6 +348,9 @@ func handleBlock(b o.Block) {
 				pendingBlocksLock.Lock()
 				defer pendingBlocksLock.Unlock()
 				pendingBlocks = append(pendingBlocks, b)
+				if !parentExists {
+					requestParent(b.ParentPointer)
+				}
 			}()
 			if isVerbose {
 				if !parentExists {
@@ -319,12 +425,8 @@ func pathIsLongerThanCurrentHead(b o.Block) bool {
 // Compares the draw value of the first block to the second. Returns true if it is higher, false otherwise.
 // Precondition: b1 and b2 have same lastfinalized
 func HasHigherDrawVal(b1, b2 o.Block) bool {
-	finalLock.RLock()
-	defer finalLock.RUnlock()
-	fd := finalData[getFinalDataIndex(b1.Slot)]
-
-	draw1 := calculateDrawValue(b1.Slot, b1.Draw, fd.leadershipNonce)
-	draw2 := calculateDrawValue(b2.Slot, b2.Draw, fd.leadershipNonce)
+	draw1 := calculateDrawValue(b1.Draw)
+	draw2 := calculateDrawValue(b2.Draw)
 	if draw2.Cmp(draw1) == -1 {
 		return true
 	}
@@ -371,7 +473,7 @@ func rollback(newHead o.Block) bool {
 			return false
 		}
 	}
-	currentHead = newHead.CalculateBlockHash()
+	setCurrentHead(newHead.CalculateBlockHash())
 	currentLength = len(newBranch)
 	sendBranchToTL(newBranch)
 	return true
@@ -446,6 +548,94 @@ func updateHead(b o.Block) {
 	}
 }
 
+// invalidateBlock drops the block with the given hash, which the transaction layer found to be invalid, together
+// with the blocks building on it, so none of them is ever extended. If the head was one of them, its transactions
+// are unused again and the head moves back to the parent of the invalid block, or to a longer chain we have
+func invalidateBlock(hash string) {
+	func() {
+		blocks.lock()
+		defer blocks.unlock()
+		if !blocks.contains(hash) {
+			return
+		}
+		invalid := blocks.get(hash)
+		dropped := map[string]bool{hash: true}
+		for found := true; found; {
+			found = false
+			for h, b := range blocks.m {
+				if !dropped[h] && dropped[b.ParentPointer] {
+					dropped[h] = true
+					found = true
+				}
+			}
+		}
+		log.Println(fmt.Sprintf("dropping invalid block %s and the %d blocks building on it", shortHash(hash),
+			len(dropped)-1))
+
+		headDropped := dropped[getCurrentHead()]
+		if headDropped {
+			tLock.Lock()
+			for b := blocks.get(getCurrentHead()); dropped[b.CalculateBlockHash()]; b = blocks.get(b.ParentPointer) {
+				markTransactionsAsUnused(b.BlockData.Trans)
+			}
+			tLock.Unlock()
+		}
+		var hashes []string
+		for h := range dropped {
+			invalidBlocks[h] = blocks.get(h).Slot
+			delete(blocks.m, h)
+			hashes = append(hashes, h)
+		}
+		if err := db.Delete(store.Blocks, hashes...); err != nil {
+			log.Println("Couldn't delete the invalid blocks", err)
+		}
+		if !headDropped {
+			return
+		}
+
+		parent := blocks.get(invalid.ParentPointer)
+		setCurrentHead(invalid.ParentPointer)
+		currentLength = 0
+		if parent.Slot > 0 {
+			currentLength = lengthToLastFinal(parent)
+		}
+		sendBlockToTL(parent)
+		// the chains that lost to the dropped blocks are considered again
+		for h, b := range blocks.m {
+			if b.LastFinalized == parent.LastFinalized && h != invalid.ParentPointer && !hasChild(h) &&
+				pathIsLongerThanCurrentHead(b) {
+				rollback(b)
+			}
+		}
+		if isVerbose {
+			log.Println("head of tree is block", getCurrentHead())
+		}
+	}()
+
+	// the pending blocks building on a dropped block are invalid too
+	pendingBlocksLock.Lock()
+	defer pendingBlocksLock.Unlock()
+	blocks.rlock()
+	defer blocks.runlock()
+	var pending []o.Block
+	for _, b := range pendingBlocks {
+		if _, invalid := invalidBlocks[b.ParentPointer]; !invalid {
+			pending = append(pending, b)
+		}
+	}
+	pendingBlocks = pending
+}
+
+// hasChild returns whether a block builds on the block with the given hash. PRECONDITION: blocks is read locked
+func hasChild(hash string) bool {
+	for _, b := range blocks.m {
+		if b.ParentPointer == hash {
+			return true
+		}
+	}
+	return false
+}
+
 // PRECONDITION: blocks is at least read locked and blocks lastfinal IS on its own branch
 func lengthToLastFinal(block_ o.Block) int {
 	block := block_
@@ -484,6 +674,83 @@ func getUnusedTransactions() []o.TransData {
 	return trans
 }
 
+// evictTransactions forgets the unused transactions that can't be included in a block of slot or any later slot,
+// and the ones with a nonce the head has already used. A block including one of them still stores it again when
+// it's added, and a rollback puts the transaction that used the nonce back in the pool
+func evictTransactions(slot uint64) {
+	head := getCurrentHead()
+	tLock.Lock()
+	defer tLock.Unlock()
+	for k := range unusedTransactions {
+		t := transactions[k]
+		if t.Expired(slot) {
+			evictedSenders[t.Sender().Hash()] = true
+		} else if t.GetNonce() >= transaction.GetNextNonceAt(head, t.Sender().Hash()) {
+			continue
+		}
+		delete(unusedTransactions, k)
+		delete(transactions, k)
+	}
+}
+
+// TakeEvicted returns whether transactions of account expired before a block included them, since it was last asked.
+// Their nonces are then never used, so the transactions with the nonces after them can't be included either
+func TakeEvicted(account string) bool {
+	tLock.Lock()
+	defer tLock.Unlock()
+	evicted := evictedSenders[account]
+	delete(evictedSenders, account)
+	return evicted
+}
+
+// maxParentRequests is how many times the missing parent of pending blocks is asked for, before the blocks are dropped
+const maxParentRequests = 6
+
+// parentRequest is a missing parent of pending blocks that was asked for. It is asked for again with a doubling wait
+type parentRequest struct {
+	attempts int
+	next     time.Time
+}
+
+// requestParent asks the P2P layer for the missing parent with the given hash, unless it was asked for recently.
+// Returns false once it was asked for maxParentRequests times without arriving. PRECONDITION: pendingBlocksLock is
+// locked
+func requestParent(hash string) bool {
+	if parentRequests == nil {
+		parentRequests = make(map[string]*parentRequest)
+	}
+	request, exists := parentRequests[hash]
+	if !exists {
+		request = &parentRequest{}
+		parentRequests[hash] = request
+	}
+	if time.Now().Before(request.next) {
+		return true
+	}
+	if request.attempts >= maxParentRequests {
+		return false
+	}
+	request.attempts++
+	request.next = time.Now().Add(slotLength << uint(request.attempts-1))
+	requestBlock(hash)
+	return true
+}
+
+// requestBlock asks the P2P layer to fetch a block we are missing, so the blocks building on it don't stay pending
+func requestBlock(hash string) {
+	go func(c chan string) {
+		c <- hash
+	}(channels.BlockRequest)
+}
+
+// dropBlock tells the P2P layer we dropped the block with the given hash, so it is accepted again if it is sent or
+// fetched later
+func dropBlock(hash string) {
+	go func(c chan string) {
+		c <- hash
+	}(channels.BlockDropped)
+}
+
 type skov struct {
 	m map[string]o.Block
 	l sync.RWMutex
@@ -492,6 +759,9 @@ type skov struct {
 func (s *skov) add(block o.Block) {
 	hash := block.CalculateBlockHash()
 	s.m[hash] = block
+	if err := db.Put(store.Blocks, hash, block.Encode()); err != nil {
+		log.Println("Couldn't store block", hash, err)
+	}
 }
 
 func (s *skov) get(blockHash string) o.Block {
@@ -544,6 +814,9 @@ func setCurrentHead(headHash string) {
 	currentHeadLock.Lock()
 	defer currentHeadLock.Unlock()
 	currentHead = headHash
+	if err := db.Put(store.Meta, "head", []byte(headHash)); err != nil {
+		log.Println("Couldn't store the head", err)
+	}
 }
 
 func getDotString(blocks map[string]o.Block) []byte {
@@ -566,3 +839,61 @@ func getDotString(blocks map[string]o.Block) []byte {
 	logstring += "\n}"
 	return []byte(logstring)
 }
+
+// Checkpoint is the last block finalized by a node, which a node catching up with the chain syncs from
+type Checkpoint struct {
+	Epoch     uint64
+	BlockHash string
+	Slot      uint64
+	HeadSlot  uint64 // slot of the head of the chain of the node
+}
+
+// GetBlock returns the block with the given hash, if we have it
+func GetBlock(hash string) (o.Block, bool) {
+	blocks.rlock()
+	defer blocks.runlock()
+	b, exists := blocks.m[hash]
+	return b, exists
+}
+
+// GetBlocks returns the blocks with a slot from from to to, both included, sorted by slot
+func GetBlocks(from, to uint64) []o.Block {
+	blocks.rlock()
+	defer blocks.runlock()
+	var result []o.Block
+	for _, b := range blocks.m {
+		if b.Slot >= from && b.Slot <= to {
+			result = append(result, b)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Slot != result[j].Slot {
+			return result[i].Slot < result[j].Slot
+		}
+		return result[i].CalculateBlockHash() < result[j].CalculateBlockHash()
+	})
+	return result
+}
+
+// GetCheckpoint returns the last block we finalized. The block hash is empty if we have no chain yet
+func GetCheckpoint() Checkpoint {
+	var checkpoint Checkpoint
+	func() {
+		finalLock.RLock()
+		defer finalLock.RUnlock()
+		for epoch, fd := range finalData {
+			if checkpoint.BlockHash == "" || epoch > checkpoint.Epoch {
+				checkpoint.Epoch = epoch
+				checkpoint.BlockHash = fd.blockHash
+			}
+		}
+	}()
+	if checkpoint.BlockHash == "" {
+		return Checkpoint{}
+	}
+	blocks.rlock()
+	defer blocks.runlock()
+	checkpoint.Slot = blocks.get(checkpoint.BlockHash).Slot
+	checkpoint.HeadSlot = blocks.get(getCurrentHead()).Slot
+	return checkpoint
+}
@@ -3,110 +3,128 @@ package crypto
 import (
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"math/big"
+	"strings"
 )
 
-type SecretKey struct {
-	N *big.Int
-	D *big.Int
+// Scheme is the signature scheme a key belongs to. Keys and signatures are tagged with their scheme, so several
+// schemes can be used on the same chain
+type Scheme string
+
+const (
+	Ed25519 Scheme = "ed25519"
+	// RSA is textbook RSA with e = 3 and no padding. It is only kept so old keys can still be used
+	RSA Scheme = "rsa"
+	// Multisig keys stand for accounts controlled by several keys, see NewMultisigKey
+	Multisig Scheme = "multisig"
+)
+
+// Signer signs messages, such that the signatures can be verified with its public key
+type Signer interface {
+	Sign(m string) string
+	PublicKey() PublicKey
 }
 
-type PublicKey struct {
-	N *big.Int
-	E *big.Int
+// Verifier verifies signatures made by a Signer
+type Verifier interface {
+	Verify(m string, signature string) bool
 }
 
-func (t *PublicKey) String() string {
-	return "{n: " + t.N.String() + ", e: " + t.E.String() + "}"
+// scheme is the implementation of a signature scheme. Keys and signatures are passed as raw bytes
+type scheme interface {
+	sign(sk SecretKey, m []byte) []byte
+	verify(pk PublicKey, m []byte, signature []byte) bool
 }
 
-func (t *PublicKey) Hash() string {
-	bytes := sha256.Sum256([]byte(t.N.String()))
-	return fmt.Sprintf("%x", bytes)
+var schemes = map[Scheme]scheme{
+	Ed25519:  ed25519Scheme{},
+	RSA:      rsaScheme{},
+	Multisig: multisigScheme{},
 }
 
-func makePublicKey(n *big.Int, e *big.Int) *PublicKey {
-	pk := new(PublicKey)
-	pk.E = e
-	pk.N = n
-	return pk
+// PublicKey is a public key of some scheme. The raw key is kept as a string, so keys can be compared and used as
+// map keys
+type PublicKey struct {
+	Scheme Scheme
+	Key    string
 }
 
-func makePrivateKey(n *big.Int, d *big.Int) *SecretKey {
-	sk := new(SecretKey)
-	sk.N = n
-	sk.D = d
-	return sk
+// SecretKey is a secret key of some scheme, together with its public key
+type SecretKey struct {
+	Scheme Scheme
+	Key    string
+	Pk     PublicKey
 }
 
-/* Returns a key of the length specified in the argument.
- */
-func KeyGen(k int) (SecretKey, PublicKey) {
-	k = k / 2
-	p := big.NewInt(0)
-	q := big.NewInt(0)
-	n := big.NewInt(0)
-	r := big.NewInt(0)
-
-	determiningPrimes := true
-	for determiningPrimes {
-		//Primes p,q with the length of maxBitLengthOfPrimes
-		p, _ = rand.Prime(rand.Reader, k)
-		q, _ = rand.Prime(rand.Reader, k)
-
-		// Ensure that p != q
-		for p.Cmp(q) == 0 {
-			q, _ = rand.Prime(rand.Reader, k)
-		}
-		n.Mul(p, q)
-		// Checks if gcd((p-1)(q-1),3) = 1
-		p.Sub(p, big.NewInt(1))
-		q.Sub(q, big.NewInt(1))
-		r = big.NewInt(0).Mul(p, q)
-		if big.NewInt(0).GCD(big.NewInt(0), big.NewInt(0), r, big.NewInt(3)).Cmp(big.NewInt(1)) == 0 {
-			determiningPrimes = false
-		}
+func (t PublicKey) String() string {
+	return string(t.Scheme) + ":" + hex.EncodeToString([]byte(t.Key))
+}
+
+// ParsePublicKey reads a public key written by PublicKey.String
+func ParsePublicKey(s string) (PublicKey, error) {
+	i := strings.Index(s, ":")
+	if i < 0 {
+		return PublicKey{}, fmt.Errorf("public keys have the form scheme:key")
+	}
+	key, err := hex.DecodeString(s[i+1:])
+	if err != nil {
+		return PublicKey{}, err
 	}
+	return PublicKey{Scheme(s[:i]), string(key)}, nil
+}
+
+func (t PublicKey) Hash() string {
+	bytes := sha256.Sum256([]byte(string(t.Scheme) + t.Key))
+	return fmt.Sprintf("%x", bytes)
+}
 
-	pk := makePublicKey(n, big.NewInt(3))
-	sk := makePrivateKey(n, big.NewInt(0).ModInverse(big.NewInt(3), r))
+// Verify returns whether signature is a signature of m made with the secret key of t. Signatures of another scheme
+// than the key are never valid
+func (t PublicKey) Verify(m string, signature string) bool {
+	impl, exists := schemes[t.Scheme]
+	if !exists {
+		return false
+	}
+	tag := string(t.Scheme) + ":"
+	if !strings.HasPrefix(signature, tag) {
+		return false
+	}
+	sig, err := hex.DecodeString(signature[len(tag):])
+	if err != nil {
+		return false
+	}
+	return impl.verify(t, []byte(m), sig)
+}
 
-	return *sk, *pk
+// Sign signs m, and returns the signature tagged with the scheme of the key
+func (sk SecretKey) Sign(m string) string {
+	impl, exists := schemes[sk.Scheme]
+	if !exists {
+		panic(fmt.Sprintf("unknown signature scheme %s", sk.Scheme))
+	}
+	return string(sk.Scheme) + ":" + hex.EncodeToString(impl.sign(sk, []byte(m)))
 }
 
-func Sign(m string, sk SecretKey) string {
-	hash := sha256.Sum256([]byte(m))
-	z := big.NewInt(0)
-	z.SetBytes(hash[:])
-	return z.Exp(z, sk.D, sk.N).String()
+func (sk SecretKey) PublicKey() PublicKey {
+	return sk.Pk
 }
 
-/* func HashSHA(m string) string {
-	hash := sha256.Sum256([]byte(m))
-	z := big.NewInt(0)
-	return z.SetBytes(hash[:]).String()
-} */
+/* Returns a new Ed25519 key pair
+ */
+func KeyGen() (SecretKey, PublicKey) {
+	sk, err := Ed25519KeyGen()
+	if err != nil {
+		panic(err.Error())
+	}
+	return sk, sk.Pk
+}
 
 func HashSHA(m string) string {
 	hash := sha256.Sum256([]byte(m))
 	return fmt.Sprintf("%x", hash)
 }
 
-func Verify(m string, s string, pk PublicKey) bool {
-	sInt := big.NewInt(0)
-	sInt.SetString(s, 10)
-	hash := sha256.Sum256([]byte(m))
-	hashedMessage := big.NewInt(0)
-	hashedMessage.SetBytes(hash[:])
-	toVerify := sInt.Exp(sInt, pk.E, pk.N)
-
-	if hashedMessage.Cmp(toVerify) == 0 {
-		return true
-	}
-	return false
-}
-
 func GenerateRandomBytes(n int) ([]byte, error) {
 	b := make([]byte, n)
 	_, err := rand.Read(b)
@@ -0,0 +1,51 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	sk, pk := KeyGen()
+	rsaSk, rsaPk := RSAKeyGen(512)
+	for _, signer := range []Signer{sk, rsaSk} {
+		signature := signer.Sign("message")
+		if !signer.PublicKey().Verify("message", signature) {
+			t.Errorf("%s signature should verify", signer.PublicKey().Scheme)
+		}
+		if signer.PublicKey().Verify("other message", signature) {
+			t.Errorf("%s signature of another message should not verify", signer.PublicKey().Scheme)
+		}
+	}
+	_, pk2 := KeyGen()
+	if pk2.Verify("message", sk.Sign("message")) {
+		t.Errorf("signature should not verify with another key")
+	}
+	if rsaPk.Verify("message", sk.Sign("message")) || pk.Verify("message", rsaSk.Sign("message")) {
+		t.Errorf("signature should not verify with a key of another scheme")
+	}
+}
+
+func TestSignatureIsTagged(t *testing.T) {
+	sk, pk := KeyGen()
+	signature := sk.Sign("message")
+	if !strings.HasPrefix(signature, "ed25519:") {
+		t.Errorf("signature %s should be tagged with its scheme", signature)
+	}
+	untagged := strings.TrimPrefix(signature, "ed25519:")
+	if pk.Verify("message", untagged) || pk.Verify("message", "rsa:"+untagged) {
+		t.Errorf("signature with a wrong tag should not verify")
+	}
+}
+
+func TestKeyFromSeed(t *testing.T) {
+	seed := make([]byte, 32)
+	sk1, sk2 := Ed25519KeyFromSeed(seed), Ed25519KeyFromSeed(seed)
+	if sk1.Pk != sk2.Pk || sk1.Pk.Hash() != sk2.Pk.Hash() {
+		t.Errorf("keys from the same seed should be equal")
+	}
+	seed[0] = 1
+	if Ed25519KeyFromSeed(seed).Pk == sk1.Pk {
+		t.Errorf("keys from different seeds should differ")
+	}
+}
@@ -0,0 +1,127 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Addresses are written as a prefix telling what they address, followed by the base58 encoding of a version byte,
+// the 32 byte hash and a 4 byte checksum. The checksum covers the prefix as well, so an account address can't be
+// mistaken for a contract address. Ledgers and contract states are still keyed by the hex encoded hashes.
+const (
+	AccountPrefix  = "kn1"
+	ContractPrefix = "kn2"
+
+	addressVersion  = 1
+	addressHashLen  = 32
+	addressCheckLen = 4
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Address returns the address of the account of t
+func (t PublicKey) Address() string {
+	address, _ := EncodeAccount(t.Hash())
+	return address
+}
+
+// EncodeAccount returns the address of the account with the given hex encoded key hash
+func EncodeAccount(keyHash string) (string, error) {
+	return encodeAddress(AccountPrefix, keyHash)
+}
+
+// EncodeContract returns the address of the contract with the given hex encoded hash
+func EncodeContract(contractHash string) (string, error) {
+	return encodeAddress(ContractPrefix, contractHash)
+}
+
+// DecodeAccount returns the hex encoded key hash of an account address, or an error if the address is malformed or
+// its checksum doesn't match
+func DecodeAccount(address string) (string, error) {
+	return decodeAddress(AccountPrefix, address)
+}
+
+// DecodeContract returns the hex encoded hash of a contract address, or an error if the address is malformed or its
+// checksum doesn't match
+func DecodeContract(address string) (string, error) {
+	return decodeAddress(ContractPrefix, address)
+}
+
+func encodeAddress(prefix, hash string) (string, error) {
+	payload, err := hex.DecodeString(hash)
+	if err != nil || len(payload) != addressHashLen {
+		return "", fmt.Errorf("%s is not a hash of %d bytes", hash, addressHashLen)
+	}
+	data := append([]byte{addressVersion}, payload...)
+	data = append(data, addressChecksum(prefix, data)...)
+	return prefix + base58Encode(data), nil
+}
+
+func decodeAddress(prefix, address string) (string, error) {
+	if !strings.HasPrefix(address, prefix) {
+		return "", fmt.Errorf("%s does not start with %s", address, prefix)
+	}
+	data, err := base58Decode(address[len(prefix):])
+	if err != nil {
+		return "", fmt.Errorf("%s is not a valid address: %s", address, err.Error())
+	}
+	if len(data) != 1+addressHashLen+addressCheckLen {
+		return "", fmt.Errorf("%s is not a valid address: wrong length", address)
+	}
+	if data[0] != addressVersion {
+		return "", fmt.Errorf("%s has unknown address version %d", address, data[0])
+	}
+	checked, checksum := data[:1+addressHashLen], data[1+addressHashLen:]
+	if !bytes.Equal(addressChecksum(prefix, checked), checksum) {
+		return "", fmt.Errorf("%s has a wrong checksum, check it for typos", address)
+	}
+	return hex.EncodeToString(checked[1:]), nil
+}
+
+func addressChecksum(prefix string, data []byte) []byte {
+	first := sha256.Sum256(append([]byte(prefix), data...))
+	second := sha256.Sum256(first[:])
+	return second[:addressCheckLen]
+}
+
+func base58Encode(data []byte) string {
+	x := new(big.Int).SetBytes(data)
+	base, mod := big.NewInt(58), new(big.Int)
+	encoded := make([]byte, 0)
+	for x.Sign() > 0 {
+		x.DivMod(x, base, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+func base58Decode(s string) ([]byte, error) {
+	x, base := new(big.Int), big.NewInt(58)
+	for _, c := range s {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit < 0 {
+			return nil, fmt.Errorf("%c is not a base58 character", c)
+		}
+		x.Mul(x, base)
+		x.Add(x, big.NewInt(int64(digit)))
+	}
+	decoded := x.Bytes()
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), decoded...), nil
+}
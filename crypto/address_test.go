@@ -0,0 +1,47 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddressRoundtrip(t *testing.T) {
+	_, pk := KeyGen()
+	address := pk.Address()
+	if !strings.HasPrefix(address, AccountPrefix) {
+		t.Errorf("account address %s should start with %s", address, AccountPrefix)
+	}
+	keyHash, err := DecodeAccount(address)
+	if err != nil || keyHash != pk.Hash() {
+		t.Errorf("address should decode to the key hash")
+	}
+	contract, _ := EncodeContract(pk.Hash())
+	if hash, err := DecodeContract(contract); err != nil || hash != pk.Hash() {
+		t.Errorf("contract address should decode to the contract hash")
+	}
+	if _, err := EncodeAccount("abc"); err == nil {
+		t.Errorf("encoding something else than a hash should fail")
+	}
+}
+
+func TestAddressRejectsTypos(t *testing.T) {
+	_, pk := KeyGen()
+	address := pk.Address()
+	for i := len(AccountPrefix); i < len(address); i++ {
+		for _, c := range base58Alphabet {
+			if byte(c) == address[i] {
+				continue
+			}
+			typo := address[:i] + string(c) + address[i+1:]
+			if _, err := DecodeAccount(typo); err == nil {
+				t.Fatalf("%s with a typo at %d should be rejected", typo, i)
+			}
+		}
+	}
+	if _, err := DecodeAccount(address[:len(address)-1]); err == nil {
+		t.Errorf("truncated address should be rejected")
+	}
+	if _, err := DecodeContract(ContractPrefix + address[len(AccountPrefix):]); err == nil {
+		t.Errorf("account address should not be accepted as a contract address")
+	}
+}
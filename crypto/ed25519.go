@@ -0,0 +1,38 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+)
+
+type ed25519Scheme struct{}
+
+// Ed25519KeyGen returns a new Ed25519 key
+func Ed25519KeyGen() (SecretKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return SecretKey{}, err
+	}
+	return ed25519SecretKey(key), nil
+}
+
+// Ed25519KeyFromSeed returns the Ed25519 key derived from a 32 byte seed
+func Ed25519KeyFromSeed(seed []byte) SecretKey {
+	return ed25519SecretKey(ed25519.NewKeyFromSeed(seed))
+}
+
+func ed25519SecretKey(key ed25519.PrivateKey) SecretKey {
+	pk := PublicKey{Ed25519, string(key.Public().(ed25519.PublicKey))}
+	return SecretKey{Ed25519, string(key), pk}
+}
+
+func (ed25519Scheme) sign(sk SecretKey, m []byte) []byte {
+	return ed25519.Sign(ed25519.PrivateKey(sk.Key), m)
+}
+
+func (ed25519Scheme) verify(pk PublicKey, m []byte, signature []byte) bool {
+	if len(pk.Key) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(ed25519.PublicKey(pk.Key), m, signature)
+}
@@ -0,0 +1,86 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HDKey is a node in a tree of Ed25519 keys derived from a seed, as in SLIP-0010. Every node has a key and a chain
+// code, and the children of a node are derived from both, so the whole tree can be regenerated from the seed.
+// Ed25519 only has hardened children, whose keys can't be derived from the public key of their parent
+type HDKey struct {
+	key       []byte
+	chainCode []byte
+}
+
+// HardenedIndex is added to the index of every child, as all children are hardened
+const HardenedIndex = uint32(1) << 31
+
+// CoinType is the coin of the paths of accounts, m/44'/CoinType'/account'/0'
+const CoinType = 5893
+
+// NewMasterKey returns the root of the tree of keys derived from seed
+func NewMasterKey(seed []byte) HDKey {
+	return hdKey([]byte("ed25519 seed"), seed)
+}
+
+// Child returns the child with the given index. The index is always hardened
+func (k HDKey) Child(index uint32) HDKey {
+	var i [4]byte
+	binary.BigEndian.PutUint32(i[:], index|HardenedIndex)
+	data := append(append([]byte{0}, k.key...), i[:]...)
+	return hdKey(k.chainCode, data)
+}
+
+// Derive returns the key at path below k, such as m/44'/5893'/0'/0'. Every index has to be hardened
+func (k HDKey) Derive(path string) (HDKey, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return HDKey{}, fmt.Errorf("a derivation path starts with m")
+	}
+	for _, part := range parts[1:] {
+		if !strings.HasSuffix(part, "'") {
+			return HDKey{}, fmt.Errorf("index %s is not hardened, Ed25519 keys only have hardened children", part)
+		}
+		index, err := strconv.ParseUint(strings.TrimSuffix(part, "'"), 10, 31)
+		if err != nil {
+			return HDKey{}, fmt.Errorf("bad index %s in derivation path", part)
+		}
+		k = k.Child(uint32(index))
+	}
+	return k, nil
+}
+
+// SecretKey returns the Ed25519 key of the node
+func (k HDKey) SecretKey() SecretKey {
+	return Ed25519KeyFromSeed(k.key)
+}
+
+// AccountPath returns the derivation path of account n
+func AccountPath(n uint32) string {
+	return fmt.Sprintf("m/44'/%d'/%d'/0'", CoinType, n)
+}
+
+// AccountKey returns the key of account n derived from the mnemonic and its passphrase
+func AccountKey(mnemonic string, passphrase string, n uint32) (SecretKey, error) {
+	seed, err := MnemonicSeed(mnemonic, passphrase)
+	if err != nil {
+		return SecretKey{}, err
+	}
+	k, err := NewMasterKey(seed).Derive(AccountPath(n))
+	if err != nil {
+		return SecretKey{}, err
+	}
+	return k.SecretKey(), nil
+}
+
+func hdKey(hmacKey []byte, data []byte) HDKey {
+	mac := hmac.New(sha512.New, hmacKey)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return HDKey{sum[:32], sum[32:]}
+}
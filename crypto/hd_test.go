@@ -0,0 +1,53 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Test vector 1 for Ed25519 of SLIP-0010
+func TestHDKeyVector(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master := NewMasterKey(seed)
+	if hex.EncodeToString(master.key) != "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7" ||
+		hex.EncodeToString(master.chainCode) != "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb" {
+		t.Errorf("wrong master key %x, chain code %x", master.key, master.chainCode)
+	}
+	child, err := master.Derive("m/0'")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if hex.EncodeToString(child.key) != "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3" ||
+		hex.EncodeToString(child.chainCode) != "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69" {
+		t.Errorf("wrong child key %x, chain code %x", child.key, child.chainCode)
+	}
+}
+
+func TestAccountKey(t *testing.T) {
+	mnemonic := "legal winner thank year wave sausage worth useful legal winner thank yellow"
+	sk1, err := AccountKey(mnemonic, "", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	sk2, _ := AccountKey(mnemonic, "", 0)
+	if sk1.Pk != sk2.Pk {
+		t.Error("the same account should give the same key")
+	}
+	other, _ := AccountKey(mnemonic, "", 1)
+	withPassphrase, _ := AccountKey(mnemonic, "passphrase", 0)
+	if other.Pk == sk1.Pk || withPassphrase.Pk == sk1.Pk {
+		t.Error("other accounts and passphrases should give other keys")
+	}
+	if !sk1.Pk.Verify("message", sk1.Sign("message")) {
+		t.Error("derived key should sign")
+	}
+}
+
+func TestDeriveFAIL(t *testing.T) {
+	master := NewMasterKey(make([]byte, 64))
+	for _, path := range []string{"44'/0'", "m/44", "m/x'", "m/2147483648'"} {
+		if _, err := master.Derive(path); err == nil {
+			t.Errorf("%s: should have failed", path)
+		}
+	}
+}
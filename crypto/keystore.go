@@ -0,0 +1,218 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/crypto/scrypt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Parameters of scrypt, which derives the encryption key of an account from its passphrase
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// Keystore is a wallet file holding named accounts. The secret key of every account is encrypted with AES-GCM, using
+// a key derived from a passphrase with scrypt. The public keys are stored in the clear, so accounts can be listed
+// without a passphrase.
+type Keystore struct {
+	path     string
+	accounts map[string]encryptedKey
+}
+
+type encryptedKey struct {
+	PublicKey  string
+	Salt       string
+	Nonce      string
+	Ciphertext string
+	N          int
+	R          int
+	P          int
+}
+
+// OpenKeystore reads the wallet file at path. If the file doesn't exist, an empty keystore is returned, and the
+// file is created when the first account is added
+func OpenKeystore(path string) (*Keystore, error) {
+	ks := &Keystore{path, make(map[string]encryptedKey)}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ks, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &ks.accounts); err != nil {
+		return nil, fmt.Errorf("%s is not a keyfile: %s", path, err.Error())
+	}
+	return ks, nil
+}
+
+// Names returns the names of all accounts in sorted order
+func (ks *Keystore) Names() []string {
+	names := make([]string, 0, len(ks.accounts))
+	for name := range ks.accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PublicKey returns the public key of the account with the given name
+func (ks *Keystore) PublicKey(name string) (PublicKey, bool) {
+	account, exists := ks.accounts[name]
+	if !exists {
+		return PublicKey{}, false
+	}
+	pk, err := ParsePublicKey(account.PublicKey)
+	return pk, err == nil
+}
+
+// Add encrypts sk with passphrase, adds it as the account name and writes the keystore to its file
+func (ks *Keystore) Add(name string, sk SecretKey, passphrase string) error {
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return fmt.Errorf("account names can't be empty or contain whitespace")
+	}
+	if _, exists := ks.accounts[name]; exists {
+		return fmt.Errorf("account %s already exists", name)
+	}
+	salt, err := GenerateRandomBytes(16)
+	if err != nil {
+		return err
+	}
+	gcm, err := keystoreCipher(passphrase, salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return err
+	}
+	nonce, err := GenerateRandomBytes(gcm.NonceSize())
+	if err != nil {
+		return err
+	}
+	publicKey := sk.Pk.String()
+	ciphertext := gcm.Seal(nil, nonce, []byte(ExportKey(sk)), []byte(publicKey))
+	ks.accounts[name] = encryptedKey{publicKey, hex.EncodeToString(salt), hex.EncodeToString(nonce),
+		hex.EncodeToString(ciphertext), scryptN, scryptR, scryptP}
+	if err := ks.save(); err != nil {
+		delete(ks.accounts, name)
+		return err
+	}
+	return nil
+}
+
+// Unlock decrypts the secret key of the account with the given name
+func (ks *Keystore) Unlock(name string, passphrase string) (SecretKey, error) {
+	account, exists := ks.accounts[name]
+	if !exists {
+		return SecretKey{}, fmt.Errorf("account %s does not exist", name)
+	}
+	salt, err1 := hex.DecodeString(account.Salt)
+	nonce, err2 := hex.DecodeString(account.Nonce)
+	ciphertext, err3 := hex.DecodeString(account.Ciphertext)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return SecretKey{}, fmt.Errorf("account %s is corrupted", name)
+	}
+	gcm, err := keystoreCipher(passphrase, salt, account.N, account.R, account.P)
+	if err != nil {
+		return SecretKey{}, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return SecretKey{}, fmt.Errorf("account %s is corrupted", name)
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(account.PublicKey))
+	if err != nil {
+		return SecretKey{}, fmt.Errorf("wrong passphrase for account %s", name)
+	}
+	sk, err := ImportKey(string(plaintext))
+	if err != nil || sk.Pk.String() != account.PublicKey {
+		return SecretKey{}, fmt.Errorf("account %s is corrupted", name)
+	}
+	return sk, nil
+}
+
+// save writes the keystore to a temporary file first, so the wallet file is never left half written
+func (ks *Keystore) save() error {
+	data, err := json.MarshalIndent(ks.accounts, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ks.path), 0700); err != nil {
+		return err
+	}
+	tmp := ks.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, ks.path)
+}
+
+func keystoreCipher(passphrase string, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, n, r, p, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ExportKey writes sk as text of the form scheme:secret:public, with both keys hex encoded
+func ExportKey(sk SecretKey) string {
+	return string(sk.Scheme) + ":" + hex.EncodeToString([]byte(sk.Key)) + ":" + hex.EncodeToString([]byte(sk.Pk.Key))
+}
+
+// ImportKey reads a secret key written by ExportKey. Ed25519 keys can also be given as scheme:seed
+func ImportKey(s string) (SecretKey, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return SecretKey{}, fmt.Errorf("keys have the form scheme:secret:public")
+	}
+	secret, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return SecretKey{}, fmt.Errorf("secret key is not hex encoded")
+	}
+	var public []byte
+	if len(parts) == 3 {
+		if public, err = hex.DecodeString(parts[2]); err != nil {
+			return SecretKey{}, fmt.Errorf("public key is not hex encoded")
+		}
+	}
+
+	switch Scheme(parts[0]) {
+	case Ed25519:
+		var sk SecretKey
+		switch len(secret) {
+		case ed25519.SeedSize:
+			sk = Ed25519KeyFromSeed(secret)
+		case ed25519.PrivateKeySize:
+			sk = Ed25519KeyFromSeed(secret[:ed25519.SeedSize])
+		default:
+			return SecretKey{}, fmt.Errorf("ed25519 secret keys have %d bytes", ed25519.PrivateKeySize)
+		}
+		if public != nil && sk.Pk.Key != string(public) {
+			return SecretKey{}, fmt.Errorf("public key does not match the secret key")
+		}
+		return sk, nil
+	case RSA:
+		if public == nil {
+			return SecretKey{}, fmt.Errorf("rsa keys need the public key")
+		}
+		sk := SecretKey{RSA, string(secret), PublicKey{RSA, string(public)}}
+		if !sk.Pk.Verify("import", sk.Sign("import")) {
+			return SecretKey{}, fmt.Errorf("public key does not match the secret key")
+		}
+		return sk, nil
+	default:
+		return SecretKey{}, fmt.Errorf("unknown signature scheme %s", parts[0])
+	}
+}
@@ -0,0 +1,73 @@
+package crypto
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeystoreRoundtrip(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "keystore")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "wallet")
+
+	ks, err := OpenKeystore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	sk, pk := KeyGen()
+	rsaSk, _ := RSAKeyGen(512)
+	if err := ks.Add("alice", sk, "secret"); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := ks.Add("bob", rsaSk, "other"); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if err := ks.Add("alice", rsaSk, "secret"); err == nil {
+		t.Errorf("adding an existing account should fail")
+	}
+
+	ks, err = OpenKeystore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if names := ks.Names(); len(names) != 2 || names[0] != "alice" || names[1] != "bob" {
+		t.Errorf("accounts should be alice and bob but were %v", names)
+	}
+	if stored, _ := ks.PublicKey("alice"); stored != pk {
+		t.Errorf("public key should be readable without passphrase")
+	}
+	unlocked, err := ks.Unlock("alice", "secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if unlocked != sk {
+		t.Errorf("unlocked key differs from the stored key")
+	}
+	unlocked, err = ks.Unlock("bob", "other")
+	if err != nil || unlocked != rsaSk {
+		t.Errorf("rsa key should be unlocked")
+	}
+	if _, err := ks.Unlock("alice", "wrong"); err == nil {
+		t.Errorf("unlocking with a wrong passphrase should fail")
+	}
+	if _, err := ks.Unlock("carol", "secret"); err == nil {
+		t.Errorf("unlocking a missing account should fail")
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	sk, _ := KeyGen()
+	imported, err := ImportKey(ExportKey(sk))
+	if err != nil || imported != sk {
+		t.Errorf("imported key differs from the exported key")
+	}
+	_, other := KeyGen()
+	if _, err := ImportKey(string(sk.Scheme) + ":" + ExportKey(sk)[8:136] + ":" + other.String()[8:]); err == nil {
+		t.Errorf("import with a wrong public key should fail")
+	}
+	if _, err := ImportKey("ed25519:xyz"); err == nil {
+		t.Errorf("import of a malformed key should fail")
+	}
+}
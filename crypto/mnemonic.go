@@ -0,0 +1,95 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"math/big"
+	"strings"
+)
+
+// A mnemonic is a phrase of words from the BIP-39 wordlist encoding random entropy, from which a seed is derived.
+// Every word encodes 11 bits. The last bits of the phrase are a checksum of the entropy, so most typos are caught.
+// The seed is derived as in BIP-39, so a phrase gives the same seed here as in other wallets. The words and the
+// passphrase are not unicode normalized, which only matters for passphrases outside ASCII
+
+var wordlist = strings.Fields(englishWords)
+
+var wordIndex = func() map[string]int {
+	index := make(map[string]int, len(wordlist))
+	for i, w := range wordlist {
+		index[w] = i
+	}
+	return index
+}()
+
+// NewMnemonic returns a new random mnemonic of the given number of words, which is 12, 15, 18, 21 or 24
+func NewMnemonic(words int) (string, error) {
+	if words < 12 || words > 24 || words%3 != 0 {
+		return "", fmt.Errorf("a mnemonic has 12, 15, 18, 21 or 24 words")
+	}
+	entropy, err := GenerateRandomBytes(words * 4 / 3)
+	if err != nil {
+		return "", err
+	}
+	return MnemonicFromEntropy(entropy)
+}
+
+// MnemonicFromEntropy returns the mnemonic encoding entropy, which is 16, 20, 24, 28 or 32 bytes
+func MnemonicFromEntropy(entropy []byte) (string, error) {
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		return "", fmt.Errorf("entropy of a mnemonic is 16, 20, 24, 28 or 32 bytes")
+	}
+	checksumBits := uint(len(entropy) / 4)
+	checksum := sha256.Sum256(entropy)
+	bits := new(big.Int).SetBytes(entropy)
+	bits.Lsh(bits, checksumBits)
+	bits.Or(bits, big.NewInt(int64(checksum[0]>>(8-checksumBits))))
+
+	words := make([]string, (uint(len(entropy))*8+checksumBits)/11)
+	mask := big.NewInt(1<<11 - 1)
+	for i := len(words) - 1; i >= 0; i-- {
+		words[i] = wordlist[new(big.Int).And(bits, mask).Int64()]
+		bits.Rsh(bits, 11)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicEntropy returns the entropy encoded by mnemonic, or an error if a word is unknown or the checksum is wrong
+func MnemonicEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, fmt.Errorf("a mnemonic has 12, 15, 18, 21 or 24 words")
+	}
+	bits := new(big.Int)
+	for _, w := range words {
+		index, exists := wordIndex[w]
+		if !exists {
+			return nil, fmt.Errorf("%s is not a mnemonic word", w)
+		}
+		bits.Lsh(bits, 11)
+		bits.Or(bits, big.NewInt(int64(index)))
+	}
+	checksumBits := uint(len(words) / 3)
+	checksum := new(big.Int).And(bits, big.NewInt(1<<checksumBits-1)).Int64()
+	bits.Rsh(bits, checksumBits)
+
+	entropy := make([]byte, len(words)*4/3)
+	bits.FillBytes(entropy)
+	expected := sha256.Sum256(entropy)
+	if int64(expected[0]>>(8-checksumBits)) != checksum {
+		return nil, fmt.Errorf("wrong checksum, a word of the mnemonic is wrong")
+	}
+	return entropy, nil
+}
+
+// MnemonicSeed returns the 64 byte seed of mnemonic. The passphrase is optional, and a different passphrase gives a
+// different seed
+func MnemonicSeed(mnemonic string, passphrase string) ([]byte, error) {
+	if _, err := MnemonicEntropy(mnemonic); err != nil {
+		return nil, err
+	}
+	normalized := strings.Join(strings.Fields(mnemonic), " ")
+	return pbkdf2.Key([]byte(normalized), []byte("mnemonic"+passphrase), 2048, 64, sha512.New), nil
+}
@@ -0,0 +1,85 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestWordlist(t *testing.T) {
+	if len(wordlist) != 2048 {
+		t.Fatalf("wordlist has %d words", len(wordlist))
+	}
+	hash := sha256.Sum256([]byte(strings.Join(wordlist, "\n") + "\n"))
+	if hex.EncodeToString(hash[:]) != "2f5eed53a4727b4bf8880d8f3f199efc90e58503646d9ff8eff3a2ed3b24dbda" {
+		t.Error("wordlist differs from the BIP-39 English wordlist")
+	}
+}
+
+// Test vectors of BIP-39
+func TestMnemonicVectors(t *testing.T) {
+	tests := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{"00000000000000000000000000000000",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"},
+		{"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607"},
+		{"80808080808080808080808080808080",
+			"letter advice cage absurd amount doctor acoustic avoid letter advice cage above", ""},
+		{"ffffffffffffffffffffffffffffffff", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong", ""},
+	}
+	for _, test := range tests {
+		entropy, _ := hex.DecodeString(test.entropy)
+		mnemonic, err := MnemonicFromEntropy(entropy)
+		if err != nil || mnemonic != test.mnemonic {
+			t.Errorf("%s encoded to %s, expected %s", test.entropy, mnemonic, test.mnemonic)
+		}
+		decoded, err := MnemonicEntropy(test.mnemonic)
+		if err != nil || hex.EncodeToString(decoded) != test.entropy {
+			t.Errorf("%s decoded to %x, expected %s", test.mnemonic, decoded, test.entropy)
+		}
+		if test.seed == "" {
+			continue
+		}
+		seed, err := MnemonicSeed(test.mnemonic, "TREZOR")
+		if err != nil || hex.EncodeToString(seed) != test.seed {
+			t.Errorf("seed of %s is %x, expected %s", test.mnemonic, seed, test.seed)
+		}
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	for _, words := range []int{12, 24} {
+		mnemonic, err := NewMnemonic(words)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if len(strings.Fields(mnemonic)) != words {
+			t.Errorf("mnemonic %s should have %d words", mnemonic, words)
+		}
+		if _, err := MnemonicEntropy(mnemonic); err != nil {
+			t.Errorf("new mnemonic should be valid: %s", err.Error())
+		}
+	}
+	if _, err := NewMnemonic(13); err == nil {
+		t.Error("Should have failed on a wrong number of words")
+	}
+}
+
+func TestMnemonicFAIL(t *testing.T) {
+	if _, err := MnemonicEntropy("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon"); err == nil {
+		t.Error("Should have failed on a wrong checksum")
+	}
+	if _, err := MnemonicEntropy("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abut"); err == nil {
+		t.Error("Should have failed on an unknown word")
+	}
+	if _, err := MnemonicSeed("abandon about", ""); err == nil {
+		t.Error("Should have failed on a wrong number of words")
+	}
+}
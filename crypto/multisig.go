@@ -0,0 +1,187 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// MaxMultisigKeys is the largest number of keys a multisig account can have
+const MaxMultisigKeys = 16
+
+// A multisig key stands for an account controlled by a set of keys, of which a threshold have to sign. The raw key
+// is the encoding of the threshold and the keys, so the hash of a multisig key is the address of the account like
+// for any other key. There is no secret key, a signature is made by combining the signatures of the keys with
+// CombineSignatures.
+//
+// The threshold and the number of keys are written as uvarints, followed by the scheme and the raw key of every key,
+// each prefixed by their length as a uvarint. The keys are sorted, so the same set of keys always gives the same
+// account. A signature is a list of the indices of the keys that signed, in increasing order, each followed by the
+// signature of that key, written the same way
+type multisigScheme struct{}
+
+func (multisigScheme) sign(sk SecretKey, m []byte) []byte {
+	panic("multisig keys have no secret key, combine the signatures of their keys with CombineSignatures")
+}
+
+func (multisigScheme) verify(pk PublicKey, m []byte, signature []byte) bool {
+	threshold, keys, err := pk.MultisigKeys()
+	if err != nil {
+		return false
+	}
+	r := bytes.NewReader(signature)
+	n, err := readMultisigLength(r)
+	if err != nil || n < uint64(threshold) {
+		return false
+	}
+	previous := -1
+	for i := uint64(0); i < n; i++ {
+		index, err := readMultisigLength(r)
+		if err != nil || index >= uint64(len(keys)) || int(index) <= previous {
+			return false
+		}
+		previous = int(index)
+		sig, err := readMultisigString(r)
+		if err != nil || !keys[index].Verify(string(m), sig) {
+			return false
+		}
+	}
+	return r.Len() == 0
+}
+
+// NewMultisigKey returns the key of the account controlled by keys, of which threshold have to sign. The order of
+// the keys doesn't matter
+func NewMultisigKey(threshold int, keys []PublicKey) (PublicKey, error) {
+	if len(keys) == 0 || len(keys) > MaxMultisigKeys {
+		return PublicKey{}, fmt.Errorf("a multisig account has between 1 and %d keys", MaxMultisigKeys)
+	}
+	if threshold < 1 || threshold > len(keys) {
+		return PublicKey{}, fmt.Errorf("the threshold has to be between 1 and the number of keys")
+	}
+	sorted := append([]PublicKey{}, keys...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+
+	var buf bytes.Buffer
+	writeMultisigLength(&buf, uint64(threshold))
+	writeMultisigLength(&buf, uint64(len(sorted)))
+	for i, key := range sorted {
+		if _, exists := schemes[key.Scheme]; !exists || key.Scheme == Multisig {
+			return PublicKey{}, fmt.Errorf("%s keys can't be part of a multisig account", key.Scheme)
+		}
+		if i > 0 && key == sorted[i-1] {
+			return PublicKey{}, fmt.Errorf("key %s is given twice", key)
+		}
+		writeMultisigString(&buf, string(key.Scheme))
+		writeMultisigString(&buf, key.Key)
+	}
+	return PublicKey{Multisig, buf.String()}, nil
+}
+
+// MultisigKeys returns the threshold and the keys of a multisig key, in the order they are indexed by in signatures
+func (t PublicKey) MultisigKeys() (int, []PublicKey, error) {
+	if t.Scheme != Multisig {
+		return 0, nil, fmt.Errorf("%s is not a multisig key", t)
+	}
+	r := bytes.NewReader([]byte(t.Key))
+	threshold, err := readMultisigLength(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	n, err := readMultisigLength(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if n > MaxMultisigKeys {
+		return 0, nil, fmt.Errorf("a multisig account has between 1 and %d keys", MaxMultisigKeys)
+	}
+	keys := make([]PublicKey, n)
+	for i := range keys {
+		scheme, err := readMultisigString(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		key, err := readMultisigString(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		keys[i] = PublicKey{Scheme(scheme), key}
+	}
+	// Only the encoding made by NewMultisigKey is valid, so an account can't be controlled through another key
+	canonical, err := NewMultisigKey(int(threshold), keys)
+	if err != nil {
+		return 0, nil, err
+	}
+	if canonical != t {
+		return 0, nil, fmt.Errorf("multisig key is not in canonical form")
+	}
+	return int(threshold), keys, nil
+}
+
+// CombineSignatures combines signatures of m made by the keys of the multisig key t into a signature of t. Signatures
+// that aren't made by one of the keys are an error, and at least the threshold of the keys have to have signed
+func CombineSignatures(t PublicKey, m string, signatures []string) (string, error) {
+	threshold, keys, err := t.MultisigKeys()
+	if err != nil {
+		return "", err
+	}
+	signed := make(map[int]string)
+	for _, sig := range signatures {
+		index := -1
+		for i, key := range keys {
+			if key.Verify(m, sig) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return "", fmt.Errorf("signature %s is not made by one of the keys", sig)
+		}
+		signed[index] = sig
+	}
+	if len(signed) < threshold {
+		return "", fmt.Errorf("only %d of the %d required keys have signed", len(signed), threshold)
+	}
+
+	var buf bytes.Buffer
+	writeMultisigLength(&buf, uint64(len(signed)))
+	for i := range keys {
+		if sig, exists := signed[i]; exists {
+			writeMultisigLength(&buf, uint64(i))
+			writeMultisigString(&buf, sig)
+		}
+	}
+	return string(Multisig) + ":" + hex.EncodeToString(buf.Bytes()), nil
+}
+
+func writeMultisigLength(buf *bytes.Buffer, n uint64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], n)])
+}
+
+func writeMultisigString(buf *bytes.Buffer, s string) {
+	writeMultisigLength(buf, uint64(len(s)))
+	buf.WriteString(s)
+}
+
+func readMultisigLength(r *bytes.Reader) (uint64, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, fmt.Errorf("invalid multisig encoding")
+	}
+	return n, nil
+}
+
+func readMultisigString(r *bytes.Reader) (string, error) {
+	n, err := readMultisigLength(r)
+	if err != nil {
+		return "", err
+	}
+	if n > uint64(r.Len()) {
+		return "", fmt.Errorf("invalid multisig encoding")
+	}
+	b := make([]byte, n)
+	r.Read(b)
+	return string(b), nil
+}
@@ -0,0 +1,87 @@
+package crypto
+
+import (
+	"testing"
+)
+
+func testMultisig(t *testing.T) ([]SecretKey, PublicKey) {
+	sks := make([]SecretKey, 3)
+	pks := make([]PublicKey, 3)
+	for i := range sks {
+		sks[i], pks[i] = KeyGen()
+	}
+	multisig, err := NewMultisigKey(2, pks)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	return sks, multisig
+}
+
+func TestMultisig(t *testing.T) {
+	sks, multisig := testMultisig(t)
+	for _, signers := range [][]SecretKey{sks[:2], sks[1:], sks} {
+		var signatures []string
+		for _, sk := range signers {
+			signatures = append(signatures, sk.Sign("message"))
+		}
+		signature, err := CombineSignatures(multisig, "message", signatures)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if !multisig.Verify("message", signature) {
+			t.Errorf("signature of %d keys should verify", len(signers))
+		}
+		if multisig.Verify("other message", signature) {
+			t.Errorf("signature of another message should not verify")
+		}
+	}
+}
+
+func TestMultisigFAIL(t *testing.T) {
+	sks, multisig := testMultisig(t)
+	if _, err := CombineSignatures(multisig, "message", []string{sks[0].Sign("message")}); err == nil {
+		t.Error("Should have failed on too few signatures")
+	}
+	if _, err := CombineSignatures(multisig, "message", []string{sks[0].Sign("message"), sks[0].Sign("message")}); err == nil {
+		t.Error("Should have failed on the same key signing twice")
+	}
+	other, _ := KeyGen()
+	if _, err := CombineSignatures(multisig, "message", []string{sks[0].Sign("message"), other.Sign("message")}); err == nil {
+		t.Error("Should have failed on a signature of another key")
+	}
+	if _, err := CombineSignatures(multisig, "message", []string{sks[0].Sign("message"), sks[1].Sign("other")}); err == nil {
+		t.Error("Should have failed on a signature of another message")
+	}
+	if multisig.Verify("message", sks[0].Sign("message")) {
+		t.Error("signature of a single key should not verify")
+	}
+
+	_, pk := KeyGen()
+	if _, err := NewMultisigKey(3, []PublicKey{pk, other.Pk}); err == nil {
+		t.Error("Should have failed on a threshold above the number of keys")
+	}
+	if _, err := NewMultisigKey(1, []PublicKey{pk, pk}); err == nil {
+		t.Error("Should have failed on a repeated key")
+	}
+	if _, err := NewMultisigKey(1, []PublicKey{multisig}); err == nil {
+		t.Error("Should have failed on a nested multisig key")
+	}
+}
+
+func TestMultisigKeyIsCanonical(t *testing.T) {
+	_, pk1 := KeyGen()
+	_, pk2 := KeyGen()
+	key1, _ := NewMultisigKey(1, []PublicKey{pk1, pk2})
+	key2, _ := NewMultisigKey(1, []PublicKey{pk2, pk1})
+	if key1 != key2 || key1.Hash() != key2.Hash() {
+		t.Error("the order of the keys should not matter")
+	}
+	threshold, keys, err := key1.MultisigKeys()
+	if err != nil || threshold != 1 || len(keys) != 2 {
+		t.Errorf("multisig key decoded to %d %v %v", threshold, keys, err)
+	}
+	parsed, err := ParsePublicKey(key1.String())
+	if err != nil || parsed != key1 {
+		t.Error("multisig key should be parsed from its string")
+	}
+}
@@ -0,0 +1,67 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+)
+
+// The public exponent of all legacy RSA keys
+var rsaExponent = big.NewInt(3)
+
+type rsaScheme struct{}
+
+/* Returns a legacy RSA key of the length specified in the argument.
+ * The public key holds the modulus n, and the secret key holds the exponent d.
+ */
+func RSAKeyGen(k int) (SecretKey, PublicKey) {
+	k = k / 2
+	p := big.NewInt(0)
+	q := big.NewInt(0)
+	n := big.NewInt(0)
+	r := big.NewInt(0)
+
+	determiningPrimes := true
+	for determiningPrimes {
+		//Primes p,q with the length of maxBitLengthOfPrimes
+		p, _ = rand.Prime(rand.Reader, k)
+		q, _ = rand.Prime(rand.Reader, k)
+
+		// Ensure that p != q
+		for p.Cmp(q) == 0 {
+			q, _ = rand.Prime(rand.Reader, k)
+		}
+		n.Mul(p, q)
+		// Checks if gcd((p-1)(q-1),3) = 1
+		p.Sub(p, big.NewInt(1))
+		q.Sub(q, big.NewInt(1))
+		r = big.NewInt(0).Mul(p, q)
+		if big.NewInt(0).GCD(big.NewInt(0), big.NewInt(0), r, rsaExponent).Cmp(big.NewInt(1)) == 0 {
+			determiningPrimes = false
+		}
+	}
+
+	pk := PublicKey{RSA, string(n.Bytes())}
+	d := big.NewInt(0).ModInverse(rsaExponent, r)
+	return SecretKey{RSA, string(d.Bytes()), pk}, pk
+}
+
+func (rsaScheme) sign(sk SecretKey, m []byte) []byte {
+	hash := sha256.Sum256(m)
+	z := big.NewInt(0).SetBytes(hash[:])
+	n := big.NewInt(0).SetBytes([]byte(sk.Pk.Key))
+	d := big.NewInt(0).SetBytes([]byte(sk.Key))
+	return z.Exp(z, d, n).Bytes()
+}
+
+func (rsaScheme) verify(pk PublicKey, m []byte, signature []byte) bool {
+	n := big.NewInt(0).SetBytes([]byte(pk.Key))
+	if n.Sign() == 0 {
+		return false
+	}
+	hash := sha256.Sum256(m)
+	hashedMessage := big.NewInt(0).SetBytes(hash[:])
+	hashedMessage.Mod(hashedMessage, n)
+	s := big.NewInt(0).SetBytes(signature)
+	return hashedMessage.Cmp(s.Exp(s, rsaExponent, n)) == 0
+}
@@ -0,0 +1,257 @@
+package crypto
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+)
+
+// A verifiable random function maps a message to an output that only the holder of a secret key can compute, but
+// that anyone can check against the public key with the proof that comes with it. Unlike a signature, there is only
+// one output for every key and message, so the holder of the key can't try several outputs to pick the best one.
+//
+// This is ECVRF-EDWARDS25519-SHA512-TAI of RFC 9381, so the keys are Ed25519 keys. The arithmetic uses math/big and
+// is not constant time, which is fine for outputs that are made public right away, like the draws of bakers
+
+const (
+	vrfSuite    = 0x03
+	vrfProofLen = 80 // point, 16 byte challenge and scalar
+)
+
+var (
+	edP    = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	edQ, _ = new(big.Int).SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	edD, _ = new(big.Int).SetString("37095705934669439343138083508754565189542113879843219016388785533085940283555", 10)
+	edD2   = new(big.Int).Mod(new(big.Int).Lsh(edD, 1), edP)
+	// sqrt(-1) mod p
+	edI = new(big.Int).Exp(big.NewInt(2), new(big.Int).Rsh(new(big.Int).Sub(edP, big.NewInt(1)), 2), edP)
+	edB = func() edPoint {
+		x, _ := new(big.Int).SetString("15112221349535400772501151409588531511454012693041857206046113283949847762202", 10)
+		y, _ := new(big.Int).SetString("46316835694926478169428394003475163141307993866256225615783033603165251855960", 10)
+		return edPoint{x, y, big.NewInt(1), new(big.Int).Mod(new(big.Int).Mul(x, y), edP)}
+	}()
+	edIdentity = edPoint{big.NewInt(0), big.NewInt(1), big.NewInt(1), big.NewInt(0)}
+)
+
+// VRFProve returns the proof of the output of the VRF on alpha. Only Ed25519 keys have a VRF
+func (sk SecretKey) VRFProve(alpha []byte) ([]byte, error) {
+	if sk.Scheme != Ed25519 || len(sk.Key) != 64 {
+		return nil, fmt.Errorf("only ed25519 keys have a VRF, not %s keys", sk.Scheme)
+	}
+	hashedSk := sha512.Sum512([]byte(sk.Key[:32]))
+	x := clampedScalar(hashedSk[:32])
+	pkString := []byte(sk.Pk.Key)
+
+	h, err := vrfHashToCurve(pkString, alpha)
+	if err != nil {
+		return nil, err
+	}
+	hString := h.bytes()
+	gamma := h.mul(x)
+	kHash := sha512.Sum512(append(append([]byte{}, hashedSk[32:]...), hString...))
+	k := new(big.Int).Mod(littleEndianInt(kHash[:]), edQ)
+	c := vrfChallenge(pkString, hString, gamma.bytes(), edB.mul(k).bytes(), h.mul(k).bytes())
+	s := new(big.Int).Mod(new(big.Int).Add(k, new(big.Int).Mul(c, x)), edQ)
+
+	proof := append(gamma.bytes(), littleEndianBytes(c, 16)...)
+	return append(proof, littleEndianBytes(s, 32)...), nil
+}
+
+// VRFVerify checks proof of the output of the VRF of t on alpha, and returns the output
+func (t PublicKey) VRFVerify(alpha []byte, proof []byte) ([]byte, error) {
+	if t.Scheme != Ed25519 {
+		return nil, fmt.Errorf("only ed25519 keys have a VRF, not %s keys", t.Scheme)
+	}
+	y, ok := decodePoint([]byte(t.Key))
+	if !ok || y.mulByCofactor().isIdentity() {
+		return nil, fmt.Errorf("invalid VRF key")
+	}
+	gamma, c, s, err := decodeVRFProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	h, err := vrfHashToCurve([]byte(t.Key), alpha)
+	if err != nil {
+		return nil, err
+	}
+	u := edB.mul(s).add(y.mul(c).neg())
+	v := h.mul(s).add(gamma.mul(c).neg())
+	if vrfChallenge([]byte(t.Key), h.bytes(), gamma.bytes(), u.bytes(), v.bytes()).Cmp(c) != 0 {
+		return nil, fmt.Errorf("invalid VRF proof")
+	}
+	return vrfOutput(gamma), nil
+}
+
+// VRFProofToHash returns the output of the VRF proven by proof. The proof has to be verified with VRFVerify for the
+// output to mean anything
+func VRFProofToHash(proof []byte) ([]byte, error) {
+	gamma, _, _, err := decodeVRFProof(proof)
+	if err != nil {
+		return nil, err
+	}
+	return vrfOutput(gamma), nil
+}
+
+func decodeVRFProof(proof []byte) (edPoint, *big.Int, *big.Int, error) {
+	if len(proof) != vrfProofLen {
+		return edPoint{}, nil, nil, fmt.Errorf("a VRF proof is %d bytes", vrfProofLen)
+	}
+	gamma, ok := decodePoint(proof[:32])
+	if !ok {
+		return edPoint{}, nil, nil, fmt.Errorf("invalid point in VRF proof")
+	}
+	c := littleEndianInt(proof[32:48])
+	s := littleEndianInt(proof[48:])
+	if s.Cmp(edQ) >= 0 {
+		return edPoint{}, nil, nil, fmt.Errorf("invalid scalar in VRF proof")
+	}
+	return gamma, c, s, nil
+}
+
+func vrfOutput(gamma edPoint) []byte {
+	data := append([]byte{vrfSuite, 0x03}, gamma.mulByCofactor().bytes()...)
+	beta := sha512.Sum512(append(data, 0x00))
+	return beta[:]
+}
+
+// vrfHashToCurve hashes alpha to a point with try and increment
+func vrfHashToCurve(pkString []byte, alpha []byte) (edPoint, error) {
+	for ctr := 0; ctr < 256; ctr++ {
+		data := append([]byte{vrfSuite, 0x01}, pkString...)
+		data = append(append(data, alpha...), byte(ctr), 0x00)
+		hash := sha512.Sum512(data)
+		if p, ok := decodePoint(hash[:32]); ok {
+			return p.mulByCofactor(), nil
+		}
+	}
+	return edPoint{}, fmt.Errorf("no point found for VRF input")
+}
+
+func vrfChallenge(points ...[]byte) *big.Int {
+	data := []byte{vrfSuite, 0x02}
+	for _, p := range points {
+		data = append(data, p...)
+	}
+	hash := sha512.Sum512(append(data, 0x00))
+	return littleEndianInt(hash[:16])
+}
+
+func clampedScalar(b []byte) *big.Int {
+	s := append([]byte{}, b[:32]...)
+	s[0] &= 248
+	s[31] &= 127
+	s[31] |= 64
+	return littleEndianInt(s)
+}
+
+func littleEndianInt(b []byte) *big.Int {
+	reversed := make([]byte, len(b))
+	for i := range b {
+		reversed[len(b)-1-i] = b[i]
+	}
+	return new(big.Int).SetBytes(reversed)
+}
+
+func littleEndianBytes(n *big.Int, size int) []byte {
+	b := n.FillBytes(make([]byte, size))
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
+
+// edPoint is a point of edwards25519 in extended coordinates, x = X/Z, y = Y/Z and x*y = T/Z
+type edPoint struct {
+	X, Y, Z, T *big.Int
+}
+
+func edMod(n *big.Int) *big.Int {
+	return n.Mod(n, edP)
+}
+
+func edMul(a, b *big.Int) *big.Int {
+	return edMod(new(big.Int).Mul(a, b))
+}
+
+// add returns p + o. The formula is complete, so it also doubles
+func (p edPoint) add(o edPoint) edPoint {
+	a := edMul(new(big.Int).Sub(p.Y, p.X), new(big.Int).Sub(o.Y, o.X))
+	b := edMul(new(big.Int).Add(p.Y, p.X), new(big.Int).Add(o.Y, o.X))
+	c := edMul(edMul(p.T, edD2), o.T)
+	d := edMul(new(big.Int).Lsh(p.Z, 1), o.Z)
+	e := edMod(new(big.Int).Sub(b, a))
+	f := edMod(new(big.Int).Sub(d, c))
+	g := edMod(new(big.Int).Add(d, c))
+	h := edMod(new(big.Int).Add(b, a))
+	return edPoint{edMul(e, f), edMul(g, h), edMul(f, g), edMul(e, h)}
+}
+
+func (p edPoint) neg() edPoint {
+	return edPoint{edMod(new(big.Int).Neg(p.X)), p.Y, p.Z, edMod(new(big.Int).Neg(p.T))}
+}
+
+func (p edPoint) mul(n *big.Int) edPoint {
+	result := edIdentity
+	for i := n.BitLen() - 1; i >= 0; i-- {
+		result = result.add(result)
+		if n.Bit(i) == 1 {
+			result = result.add(p)
+		}
+	}
+	return result
+}
+
+func (p edPoint) mulByCofactor() edPoint {
+	p = p.add(p)
+	p = p.add(p)
+	return p.add(p)
+}
+
+func (p edPoint) isIdentity() bool {
+	return p.X.Sign() == 0 && edMod(new(big.Int).Sub(p.Y, p.Z)).Sign() == 0
+}
+
+// bytes encodes p as in RFC 8032, y in little endian with the sign of x in the top bit
+func (p edPoint) bytes() []byte {
+	zInv := new(big.Int).ModInverse(p.Z, edP)
+	x := edMul(p.X, zInv)
+	b := littleEndianBytes(edMul(p.Y, zInv), 32)
+	b[31] |= byte(x.Bit(0) << 7)
+	return b
+}
+
+// decodePoint decodes a point encoded by bytes, or returns false if b is not the encoding of a point
+func decodePoint(b []byte) (edPoint, bool) {
+	if len(b) != 32 {
+		return edPoint{}, false
+	}
+	yBytes := append([]byte{}, b...)
+	sign := uint(yBytes[31] >> 7)
+	yBytes[31] &= 0x7f
+	y := littleEndianInt(yBytes)
+	if y.Cmp(edP) >= 0 {
+		return edPoint{}, false
+	}
+	// x^2 = (y^2 - 1) / (d y^2 + 1)
+	y2 := edMul(y, y)
+	u := edMod(new(big.Int).Sub(y2, big.NewInt(1)))
+	v := edMod(new(big.Int).Add(edMul(edD, y2), big.NewInt(1)))
+	v3 := edMul(edMul(v, v), v)
+	uv7 := edMul(edMul(u, v3), edMul(v3, v))
+	exp := new(big.Int).Rsh(new(big.Int).Sub(edP, big.NewInt(5)), 3)
+	x := edMul(edMul(u, v3), new(big.Int).Exp(uv7, exp, edP))
+	vx2 := edMul(v, edMul(x, x))
+	if vx2.Cmp(u) != 0 {
+		if vx2.Cmp(edMod(new(big.Int).Neg(u))) != 0 {
+			return edPoint{}, false
+		}
+		x = edMul(x, edI)
+	}
+	if x.Sign() == 0 && sign == 1 {
+		return edPoint{}, false
+	}
+	if x.Bit(0) != sign {
+		x = edMod(new(big.Int).Neg(x))
+	}
+	return edPoint{x, y, big.NewInt(1), edMul(x, y)}, true
+}
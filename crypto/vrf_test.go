@@ -0,0 +1,69 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Test vectors of ECVRF-EDWARDS25519-SHA512-TAI from RFC 9381, appendix B.3
+var vrfVectors = []struct {
+	seed  string
+	alpha string
+	proof string
+	beta  string
+}{
+	{"9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60", "",
+		"8657106690b5526245a92b003bb079ccd1a92130477671f6fc01ad16f26f723f26f8a57ccaed74ee1b190bed1f479d9727d2d0f9b005a6e456a35d4fb0daab1268a1b0db10836d9826a528ca76567805",
+		"90cf1df3b703cce59e2a35b925d411164068269d7b2d29f3301c03dd757876ff66b71dda49d2de59d03450451af026798e8f81cd2e333de5cdf4f3e140fdd8ae"},
+	{"4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb", "72",
+		"f3141cd382dc42909d19ec5110469e4feae18300e94f304590abdced48aed5933bf0864a62558b3ed7f2fea45c92a465301b3bbf5e3e54ddf2d935be3b67926da3ef39226bbc355bdc9850112c8f4b02",
+		"eb4440665d3891d668e7e0fcaf587f1b4bd7fbfe99d0eb2211ccec90496310eb5e33821bc613efb94db5e5b54c70a848a0bef4553a41befc57663b56373a5031"},
+}
+
+func TestVRFVectors(t *testing.T) {
+	for i, v := range vrfVectors {
+		seed, _ := hex.DecodeString(v.seed)
+		alpha, _ := hex.DecodeString(v.alpha)
+		sk := Ed25519KeyFromSeed(seed)
+		proof, err := sk.VRFProve(alpha)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+		if hex.EncodeToString(proof) != v.proof {
+			t.Errorf("vector %d: proof is %x", i, proof)
+		}
+		beta, err := sk.Pk.VRFVerify(alpha, proof)
+		if err != nil {
+			t.Errorf("vector %d: %s", i, err.Error())
+		} else if hex.EncodeToString(beta) != v.beta {
+			t.Errorf("vector %d: output is %x", i, beta)
+		}
+	}
+}
+
+func TestVRFFAIL(t *testing.T) {
+	sk, pk := KeyGen()
+	proof, err := sk.VRFProve([]byte("message"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if _, err := pk.VRFVerify([]byte("other message"), proof); err == nil {
+		t.Error("Should have failed on a proof of another message")
+	}
+	_, other := KeyGen()
+	if _, err := other.VRFVerify([]byte("message"), proof); err == nil {
+		t.Error("Should have failed on a proof of another key")
+	}
+	tampered := append([]byte{}, proof...)
+	tampered[40] ^= 1
+	if _, err := pk.VRFVerify([]byte("message"), tampered); err == nil {
+		t.Error("Should have failed on a tampered proof")
+	}
+	if _, err := pk.VRFVerify([]byte("message"), proof[:79]); err == nil {
+		t.Error("Should have failed on a truncated proof")
+	}
+	rsaSk, _ := RSAKeyGen(512)
+	if _, err := rsaSk.VRFProve([]byte("message")); err == nil {
+		t.Error("Should have failed on an RSA key")
+	}
+}
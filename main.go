@@ -10,15 +10,21 @@ import (
 	"github.com/nfk93/blockchain/objects"
 	"github.com/nfk93/blockchain/p2p"
 	"github.com/nfk93/blockchain/smart"
+	"github.com/nfk93/blockchain/smart/interpreter"
+	"github.com/nfk93/blockchain/smart/interpreter/value"
+	"github.com/nfk93/blockchain/smart/tokenstd"
+	"github.com/nfk93/blockchain/store"
 	"github.com/nfk93/blockchain/transaction"
 	"io"
 	"io/ioutil"
 	"log"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -37,7 +43,16 @@ var runLocally *bool
 var addr *string
 var port *string
 var autoTransStatus bool
+var keyfile *string
+var dataDir *string
+var trustedBlock *string
+var pruned *bool
+var keepStates *uint64
+var account *string
+var keystore *crypto.Keystore
 var isNetworkStarter bool
+var pendingNonce uint64
+var nonceLock sync.Mutex
 
 func main() {
 
@@ -49,16 +64,51 @@ func main() {
 	finalizeGap = flag.Uint64("finalize_gap", 1500, "Specify the finalization gap, only set this is if you're starting a new network")
 	epochLength = flag.Uint64("epoch_length", 100, "Specify the epoch length, only set this is if you're starting a new network")
 	saveLogFile = flag.Bool("log", false, "Set to write log of tree in each slot to /out (default false)")
+	keyfile = flag.String("keyfile", "", "Wallet file holding the key of this node, created if it does not exist (if not set, a new key is used)")
+	account = flag.String("account", "default", "Name of the account in the keyfile to use as key of this node")
+	dataDir = flag.String("datadir", "", "Directory the chain is stored in, so the node continues from it when restarted (if not set, nothing is stored)")
+	trustedBlock = flag.String("trust", "", "Hash of a finalized block the chain synced from peers has to contain (if not set, any peer is synced from)")
+	pruned = flag.Bool("pruned", false, "Set to keep only the states of the last finalized blocks instead of every state (default false, archive mode)")
+	keepStates = flag.Uint64("keep_states", 100, "Number of finalized blocks the states are kept of in pruned mode")
 	flag.Parse()
 
-	secretKey, publicKey = crypto.KeyGen(2048)
-	_, pk2 = crypto.KeyGen(2048)
+	if err := loadKey(); err != nil {
+		fmt.Println("Could not load key:", err)
+		os.Exit(1)
+	}
+	_, pk2 = crypto.KeyGen()
 	channels = objects.CreateChannelStruct()
+	var db *store.Store
+	if *dataDir != "" {
+		var err error
+		if db, err = store.Open(*dataDir); err != nil {
+			fmt.Println("Could not open the data directory:", err)
+			os.Exit(1)
+		}
+		defer db.Close()
+	}
+	if *pruned {
+		if *keepStates == 0 {
+			fmt.Println("-keep_states has to be at least 1 in pruned mode")
+			os.Exit(1)
+		}
+		consensus.SetPruning(*keepStates)
+	}
 	p2p.StartP2P(*addr, *runLocally, *port, publicKey, channels)
-	consensus.StartConsensus(channels, publicKey, secretKey, false, *saveLogFile)
+	if *addr != "" {
+		// set before the consensus layer starts, so a restored node doesn't run the slots it missed before it synced
+		consensus.SetSyncing(true)
+	}
+	restored := consensus.StartConsensus(channels, publicKey, secretKey, false, *saveLogFile, db)
+	if *addr != "" {
+		go p2p.SyncChain(*trustedBlock)
+	}
 
 	autoTransStatus = false
-	if *addr == "" {
+	if restored {
+		isNetworkStarter = false
+		fmt.Println("Continuing the blockchain stored in " + *dataDir + ". Use -h or --help for further commands!")
+	} else if *addr == "" {
 		isNetworkStarter = true
 		fmt.Println("When all other clients are ready, use start to begin the Blockchain protocol or -h or --help for help with further commands!")
 	} else {
@@ -132,9 +182,11 @@ func cliLoop() {
 			sort.Strings(keyList)
 
 			for _, k := range keyList {
-				log.Printf("Amount %v is owned by %v\n", ledger[k], k[:10])
+				log.Printf("Amount %v is owned by %v\n", ledger[k], objects.AccountAddress(k))
 			}
 
+		case line == "repl":
+			runRepl(l)
 		case line == "contracts":
 			contractMap := smart.GetContracts()
 			var keyList []string
@@ -144,16 +196,24 @@ func cliLoop() {
 			sort.Strings(keyList)
 
 			for _, k := range keyList {
-				log.Printf("Contract %v was created in slot %v.\n", k, contractMap[k].CreatedAtSlot)
+				log.Printf("Contract %v was created in slot %v.\n", objects.ContractAddress(k), contractMap[k].CreatedAtSlot)
 			}
 
 		case strings.HasPrefix(line, "contractInfo "):
 			params := strings.Fields(line[13:])
-			conAddr := params[0]
+			conAddr, success := getContractHash(params[0])
+			if !success {
+				goto exit
+			}
 			conState := smart.GetContractState(conAddr)
 
 			log.Printf(" Contract: %v \n Balance: %v \n Prepaid: %v \n Storage Limit: %v\n Storage: %v \n",
-				conAddr, conState.Balance, conState.PrepaidStorage, conState.Storagecap, conState.Storage)
+				params[0], conState.Balance, conState.PrepaidStorage, conState.Storagecap, conState.Storage)
+			scheduled := transaction.GetScheduledCalls(conAddr)
+			log.Printf(" Scheduled calls to it: %v \n", len(scheduled))
+			for _, sc := range scheduled {
+				log.Printf("  %v \n", sc)
+			}
 
 			if len(params) == 3 && params[1] == "-o" { // with output file
 				var targetFileName string
@@ -178,6 +238,25 @@ func cliLoop() {
 
 			}
 
+		case strings.HasPrefix(line, "stateProof "):
+			stateProofCommand(strings.TrimSpace(line[11:]))
+		case strings.HasPrefix(line, "token "):
+			tokenCommand(strings.Fields(line[6:]))
+		case strings.HasPrefix(line, "broadcast "):
+			broadcastCommand(strings.TrimSpace(line[10:]))
+		case strings.HasPrefix(line, "multisig "):
+			multisigCommand(strings.Fields(line[9:]))
+		case strings.HasPrefix(line, "baker "):
+			bakerCommand(strings.Fields(line[6:]))
+		case strings.HasPrefix(line, "delegate "):
+			delegateCommand(strings.Fields(line[9:]), false)
+		case line == "undelegate" || strings.HasPrefix(line, "undelegate "):
+			delegateCommand(strings.Fields(line[10:]), true)
+		case line == "delegation" || strings.HasPrefix(line, "delegation "):
+			delegationCommand(strings.Fields(line[10:]))
+		case line == "keys" || strings.HasPrefix(line, "keys "):
+			keysCommand(strings.Fields(line[4:]), l)
+
 		case line == "final":
 			consensus.PrintCurrentStake()
 		case line == "start": //"-start_network":
@@ -207,19 +286,50 @@ func cliLoop() {
 				log.Println("Only the network starter can start the network!")
 			}
 
+		case strings.HasPrefix(line, "proof "):
+			transHash := strings.TrimSpace(line[6:])
+			block, proof, found := transaction.GetInclusionProof(transHash)
+			if !found {
+				log.Printf("Transaction %v is not in a block on the current chain\n", transHash)
+				goto exit
+			}
+			log.Printf("Transaction is in block %v of slot %v\n    Merkle root: %v\n", block.CalculateBlockHash(),
+				block.Slot, block.MerkleRoot)
+			for _, step := range proof.Steps {
+				side := "right"
+				if step.Left {
+					side = "left"
+				}
+				log.Printf("    %v sibling: %v\n", side, step.Hash)
+			}
+			log.Printf("Proof verifies: %v\n", objects.VerifyMerkleProof(block.MerkleRoot, transHash, proof))
+		case line == "fee estimate":
+			estimate := transaction.EstimateFees()
+			if estimate.Transactions == 0 {
+				log.Printf("No transactions in the last %v blocks, the minimum gas price of %v is enough\n",
+					estimate.Blocks, objects.MinGasPrice)
+			} else {
+				log.Printf("Gas prices of %v transactions in the last %v blocks:\n    Lowest: %v\n    Median: %v\n    Highest: %v\n"+
+					"    Fee of a transaction at the median price: %v\n", estimate.Transactions, estimate.Blocks,
+					estimate.Lowest, estimate.Median, estimate.Highest, transaction.TransferFee(estimate.Median))
+			}
 		case line == "id":
-			log.Printf("Your ID is: \n    Short Public Key hash: %v\n    Full Public Key hash: %v\n    Port: %v\n", publicKey.Hash()[:10], publicKey.Hash(), *port)
+			log.Printf("Your ID is: \n    Address: %v\n    Full Public Key hash: %v\n    Port: %v\n", publicKey.Address(), publicKey.Hash(), *port)
 		case line == "verbose":
 			consensus.SwitchVerbose()
 
 		case strings.HasPrefix(line, "transaction "):
-			params := strings.Fields(line[12:])
+			params, submit, ok := takeSubmitFlags(strings.Fields(line[12:]))
+			if !ok {
+				goto exit
+			}
 			noOfParams := 2
 
 			var amount uint64
 			var receiver string
+			fee := transaction.TransferFee(objects.MinGasPrice) //default
 
-			if len(params) == noOfParams {
+			if len(params) == noOfParams || len(params) == noOfParams+1 {
 				receiver = params[0]
 				amountUint, err := strconv.ParseUint(params[1], 10, 64)
 				if err != nil {
@@ -227,16 +337,22 @@ func cliLoop() {
 					goto exit
 				}
 				amount = amountUint
+				if len(params) > noOfParams {
+					fee, err = strconv.ParseUint(params[2], 10, 64)
+					if err != nil {
+						log.Println("Bad number as transaction fee")
+						goto exit
+					}
+				}
 
 				if amount > 0 && receiver != "" {
-					recPk, success := getPK(receiver)
+					receiverHash, success := getKeyHash(receiver)
 					if !success {
-						log.Printf("Public Key for %v did not exist", receiver)
 						goto exit
 					}
-					newTrans := objects.CreateTransaction(publicKey, recPk, amount, "", secretKey)
-					log.Printf("Transaction with id: %v has been created!", newTrans.ID)
-					channels.TransClientInput <- objects.TransData{Transaction: newTrans}
+					newTrans := objects.CreateTransaction(publicKey, receiverHash, amount, fee, submit.nextNonce(), submit.validUntil, secretKey)
+					log.Printf("Transaction with nonce %v has been created!", newTrans.Nonce)
+					submit.send(objects.TransData{Transaction: newTrans})
 					goto exit
 
 				}
@@ -245,16 +361,24 @@ func cliLoop() {
 			log.Println("Bad input! Use -h or --help for help menu!")
 
 		case strings.HasPrefix(line, "call "):
-			params := strings.Fields(line[5:])
+			params, submit, ok := takeSubmitFlags(strings.Fields(line[5:]))
+			if !ok {
+				goto exit
+			}
 			noOfParams := 2
-			entry := "main"     //default
-			callParams := "()"  //default
-			amount := uint64(0) //default
+			entry := "main"                 //default
+			callParams := "()"              //default
+			amount := uint64(0)             //default
+			gasPrice := objects.MinGasPrice //default
 			var gas uint64
 			var conAddr string
 
 			if len(params) >= noOfParams {
-				conAddr = params[0]
+				hash, success := getContractHash(params[0])
+				if !success {
+					goto exit
+				}
+				conAddr = hash
 				gasUint, err := strconv.ParseUint(params[1], 10, 64)
 				if err != nil {
 					log.Println("Bad number as contract gas")
@@ -275,12 +399,19 @@ func cliLoop() {
 							goto exit
 						}
 						amount = am
+					} else if params[i] == "-gasprice" {
+						price, err := strconv.ParseUint(params[i+1], 10, 64)
+						if err != nil {
+							log.Println("Bad number as gas price")
+							goto exit
+						}
+						gasPrice = price
 					}
 				}
 				if gas > 0 && conAddr != "" {
-					conCall := objects.CreateContractCall("CALL", entry, callParams, amount, gas, conAddr, publicKey, secretKey)
-					log.Printf("Contract Call to %v has been created!", conCall.Address)
-					channels.TransClientInput <- objects.TransData{ContractCall: conCall}
+					conCall := objects.CreateContractCall("CALL", entry, callParams, amount, gas, gasPrice, conAddr, publicKey, submit.nextNonce(), submit.validUntil, secretKey)
+					log.Printf("Contract Call to %v has been created!", params[0])
+					submit.send(objects.TransData{ContractCall: conCall})
 					goto exit
 
 				}
@@ -289,7 +420,10 @@ func cliLoop() {
 			log.Println("Bad input! Use -h or --help for help menu!")
 
 		case strings.HasPrefix(line, "init "):
-			params := strings.Fields(line[5:])
+			params, submit, ok := takeSubmitFlags(strings.Fields(line[5:]))
+			if !ok {
+				goto exit
+			}
 			noOfParams := 4
 			var code []byte
 			var gas uint64
@@ -302,6 +436,11 @@ func cliLoop() {
 					log.Println("Error in reading file: " + err.Error())
 					goto exit
 				}
+				code, err = interpreter.BundleImports(code, filepath.Dir(params[0]))
+				if err != nil {
+					log.Println("Error in bundling imports: " + err.Error())
+					goto exit
+				}
 
 				gasUint, err := strconv.ParseUint(params[1], 10, 64)
 				if err != nil {
@@ -324,9 +463,9 @@ func cliLoop() {
 				storageLimit = storageUint
 
 				if code != nil && gas > 0 && prepaid > 0 && storageLimit > 0 {
-					conInit := objects.CreateContractInit(publicKey, code, gas, prepaid, storageLimit, secretKey)
+					conInit := objects.CreateContractInit(publicKey, code, gas, prepaid, storageLimit, submit.nextNonce(), submit.validUntil, secretKey)
 					log.Println("The Contract init has been created!")
-					channels.TransClientInput <- objects.TransData{ContractInit: conInit}
+					submit.send(objects.TransData{ContractInit: conInit})
 					goto exit
 				}
 			} else {
@@ -374,9 +513,11 @@ func cliLoop() {
 			for i := 0; i < 5; i++ {
 				receiverPK := pkList[rand.Intn(len(pkList))]
 				trans := objects.CreateTransaction(publicKey,
-					receiverPK,
+					receiverPK.Hash(),
 					amount,
-					strconv.Itoa(i),
+					transaction.TransferFee(objects.MinGasPrice),
+					nextNonce(),
+					0,
 					secretKey)
 				channels.TransClientInput <- objects.TransData{Transaction: trans}
 			}
@@ -407,27 +548,93 @@ func printHelpMenu() {
 	prettyPrintHelpMessage("ledger", []string{"Print the current ledger"})
 	prettyPrintHelpMessage("final", []string{"Print the last finalized ledger"})
 	prettyPrintHelpMessage("seenTrans", []string{"Print list of seen transactions"})
+	prettyPrintHelpMessage("proof HASH", []string{"Prints the Merkle proof that a transaction is in a block on the current chain",
+		"", "HASH: The hash of the transaction, as printed by seenTrans"})
 	prettyPrintHelpMessage("contracts", []string{"Prints a list of all currently active contracts"})
-	prettyPrintHelpMessage("contractInfo ADDRESS", []string{"Prints info of a given contract",
+	prettyPrintHelpMessage("contractInfo ADDRESS", []string{"Prints info of a given contract, including the pending calls scheduled to it",
 		"", "ADDRESS: The address of a given contract",
 		"", "",
 		"-o <string>", "Path with filename of output file. Path should be without file extension.",
 	})
-	prettyPrintHelpMessage("transaction RECEIVER AMOUNT", []string{"Send Amount to the Receiver",
-		"", "RECEIVER: A 10 digit prefix of senders publicKey hash",
-		"", "AMOUNT: Positive integer of amount to transfer"})
+	prettyPrintHelpMessage("stateProof ADDRESS", []string{"Prints the state of an account or contract with proofs against the state root",
+		"", "ADDRESS: The address of a contract, or the address or a prefix of the address of a known key"})
+	prettyPrintHelpMessage("transaction RECEIVER AMOUNT [FEE]", []string{"Send Amount to the Receiver",
+		"", "RECEIVER: The address, or a prefix of the address of a known key",
+		"", "AMOUNT: Positive integer of amount to transfer",
+		"", "FEE: Default: the minimum fee. Fee paid to the baker, a higher fee gets included sooner",
+		"", "",
+		"-o <string>", "Write the signed transaction to a file instead of sending it. Also for call and init",
+		"-nonce <uint>", "Default: the next nonce of your account. Needed with -o when not on the network",
+		"-validuntil <uint>", "Default: never. Last slot the transaction can be included in. Also for call and init"})
+	prettyPrintHelpMessage("broadcast FILE", []string{"Verifies and sends a signed transaction written with -o"})
 	prettyPrintHelpMessage("call ADDRESS GAS ", []string{"Makes a contract call to the specified contract",
 		"", "ADDRESS: The address of the contract",
 		"", "GAS: Positive integer of how much gas to include",
 		"", "",
 		"-entry <string>", "Default: main. Used to specify the entry in the contract to call",
 		"-amount <uint>", "Default: 0. Non negative integer of amount included in a contract call",
+		"-gasprice <uint>", "Default: 1. Price paid for each unit of gas, a higher price gets included sooner",
 		"-params <string>", "Default: \"()\". Used to specify parameters to include in contract call "})
 	prettyPrintHelpMessage("init CODE GAS PREPAID STORAGE", []string{"",
-		"", "CODE: path to code file",
+		"", "CODE: path to code file. Files it imports are bundled with it",
 		"", "GAS: Positive integer of how much gas to include",
 		"", "PREPAID: Positive integer of how much prepaid money to attached at contract",
 		"", "STORAGE: Positive integer of max storage usage for a contract"})
+	prettyPrintHelpMessage("token transfer ADDRESS RECEIVER AMOUNT GAS", []string{"Transfers tokens of a token contract",
+		"", "ADDRESS: The address of the token contract",
+		"", "RECEIVER: The address, or a prefix of the address of a known key",
+		"", "AMOUNT: Positive integer of tokens to transfer",
+		"", "GAS: Positive integer of how much gas to include"})
+	prettyPrintHelpMessage("token balance ADDRESS [HOLDER]", []string{"Prints the token balance of an account",
+		"", "ADDRESS: The address of the token contract",
+		"", "HOLDER: Default: own key. The address, or a prefix of the address of a known key"})
+	prettyPrintHelpMessage("multisig create THRESHOLD KEY...", []string{"Prints the key and address of a multisig account",
+		"", "THRESHOLD: How many of the keys have to sign a transaction from the account",
+		"", "KEY: A full public key, or the address or a prefix of the address of a known key"})
+	prettyPrintHelpMessage("multisig transaction MULTISIG RECEIVER AMOUNT FILE [FEE]", []string{"Creates a transaction from a multisig account and writes it to FILE with your signature",
+		"", "MULTISIG: The key of the multisig account, as printed by multisig create",
+		"", "RECEIVER: The address, or a prefix of the address of a known key",
+		"", "AMOUNT: Positive integer of amount to transfer",
+		"", "FEE: Default: the minimum fee. Fee paid to the baker, a higher fee gets included sooner",
+		"", "",
+		"-nonce <uint>", "Default: the next nonce of the multisig account. Needed when not on the network",
+		"-validuntil <uint>", "Default: never. Last slot the transaction can be included in"})
+	prettyPrintHelpMessage("multisig sign FILE [OUTPUT]", []string{"Adds your signature to a multisig transaction",
+		"", "OUTPUT: Default: FILE. File to write the signed transaction to"})
+	prettyPrintHelpMessage("multisig combine FILE...", []string{"Combines the signatures of a multisig transaction and sends it",
+		"", "FILE: Files with signatures of the same transaction, written by multisig transaction or multisig sign",
+		"", "",
+		"-o <string>", "Write the signed transaction to a file instead of sending it"})
+	prettyPrintHelpMessage("baker consent ACCOUNT SLOT", []string{"Prints the consent of your key to be the baking key of ACCOUNT",
+		"", "ACCOUNT: A full public key, or the address or a prefix of the address of a known key",
+		"", "SLOT: Last slot the registration using the consent can be included in"})
+	prettyPrintHelpMessage("baker register BAKINGKEY CONSENT [FEE]", []string{"Registers the key that bakes with the stake of your account",
+		"", "BAKINGKEY: A full public key, or the address or a prefix of the address of a known key",
+		"", "CONSENT: The consent printed by baker consent on the node of the baking key",
+		"", "FEE: Default: the minimum fee. Fee paid to the baker, a higher fee gets included sooner",
+		"", "",
+		"-validuntil <uint>", "The SLOT the consent was given for. Required",
+		"-o <string>", "Write the signed registration to a file instead of sending it. Also for unregister"})
+	prettyPrintHelpMessage("baker unregister [FEE]", []string{"Bakes with the key of your account again"})
+	prettyPrintHelpMessage("baker info [ADDRESS]", []string{"Prints the baking key of an account",
+		"", "ADDRESS: Default: own key. The address, or a prefix of the address of a known key"})
+	prettyPrintHelpMessage("delegate BAKER [FEE]", []string{"Delegates the stake of your account to a baker, who shares its rewards with you",
+		"", "BAKER: The address, or a prefix of the address of a known key",
+		"", "FEE: Default: the minimum fee. Fee paid to the baker, a higher fee gets included sooner",
+		"", "",
+		"-o <string>", "Write the signed delegation to a file instead of sending it. Also for undelegate"})
+	prettyPrintHelpMessage("undelegate [FEE]", []string{"Ends the delegation of your stake"})
+	prettyPrintHelpMessage("delegation [ADDRESS]", []string{"Prints the baker an account delegates to, and the accounts delegating to it",
+		"", "ADDRESS: Default: own key. The address, or a prefix of the address of a known key"})
+	prettyPrintHelpMessage("fee estimate", []string{"Prints the gas prices paid in the latest blocks"})
+	prettyPrintHelpMessage("keys list", []string{"Lists the accounts in the keyfile. Requires -keyfile"})
+	prettyPrintHelpMessage("keys new NAME", []string{"Creates a new account in the keyfile, encrypted with a passphrase"})
+	prettyPrintHelpMessage("keys import NAME", []string{"Adds a key exported with keys export to the keyfile. Asks for the key"})
+	prettyPrintHelpMessage("keys mnemonic", []string{"Prints a new mnemonic phrase to derive accounts from"})
+	prettyPrintHelpMessage("keys derive NAME N", []string{"Adds account N derived from a mnemonic phrase to the keyfile",
+		"", "N: Non negative integer, the number of the account. The same phrase and N always give the same key"})
+	prettyPrintHelpMessage("keys export NAME", []string{"Prints the secret key of an account. Keep it secret!"})
+	prettyPrintHelpMessage("repl", []string{"Starts a REPL for the contract language. Use :help in it for its commands"})
 	prettyPrintHelpMessage("debug-trans5", []string{"Sends 1/20 of your stake to 5 random users in the network"})
 	prettyPrintHelpMessage("debug-autotrans", []string{"Toggle: Sends 1/50 of your stake to 5 random users in the network every 5 seconds"})
 }
@@ -473,9 +680,11 @@ func autoTrans() {
 			for i := 0; i < 2; i++ {
 				receiverPK := pkList[rand.Intn(len(pkList))]
 				trans := objects.CreateTransaction(publicKey,
-					receiverPK,
+					receiverPK.Hash(),
 					uint64(rand.Intn(int(currentStake)/50)),
-					strconv.Itoa(i),
+					transaction.TransferFee(objects.MinGasPrice),
+					nextNonce(),
+					0,
 					secretKey)
 				channels.TransClientInput <- objects.TransData{Transaction: trans}
 			}
@@ -485,15 +694,848 @@ func autoTrans() {
 	}
 }
 
-func getPK(prefix string) (crypto.PublicKey, bool) {
-	pkList := p2p.GetPublicKeys()
-	if len(prefix) < 10 {
-		log.Println("Receivers key is to short...")
+// stateProofCommand prints the state of an account or contract in the current head, each part with whether its
+// proof against the state root verifies
+func stateProofCommand(address string) {
+	type entry struct {
+		name   string
+		key    string
+		format func(v []byte) string
+	}
+	formatUint := func(v []byte) string { return strconv.FormatUint(objects.DecodeUint(v), 10) }
+	var entries []entry
+	if objects.IsContractAddress(address) {
+		hash, success := getContractHash(address)
+		if !success {
+			return
+		}
+		entries = []entry{
+			{"Balance", objects.ContractBalanceKey(hash), formatUint},
+			{"Owner", objects.OwnerKey(hash), func(v []byte) string {
+				owner, err := crypto.ParsePublicKey(string(v))
+				if err != nil {
+					return "none"
+				}
+				return owner.Address()
+			}},
+			{"Prepaid", objects.PrepaidKey(hash), func(v []byte) string {
+				if len(v) != 16 {
+					return "none"
+				}
+				return fmt.Sprintf("%v, Storage Limit: %v", objects.DecodeUint(v[:8]), objects.DecodeUint(v[8:]))
+			}},
+			{"Storage", objects.StorageKey(hash), func(v []byte) string {
+				storage, err := value.Decode(v)
+				if err != nil {
+					return "none"
+				}
+				return value.Format(storage)
+			}},
+		}
+	} else {
+		keyHash, success := getKeyHash(address)
+		if !success {
+			return
+		}
+		entries = []entry{
+			{"Balance", objects.BalanceKey(keyHash), formatUint},
+			{"Nonce", objects.NonceKey(keyHash), formatUint},
+		}
+	}
+
+	var root string
+	for _, e := range entries {
+		v, proof, stateRoot := transaction.GetStateProof(e.key)
+		root = stateRoot
+		log.Printf(" %v: %v\n    proof of %v hashes verifies: %v\n", e.name, e.format(v), len(proof.Siblings),
+			objects.VerifyStateProof(root, e.key, v, proof))
+	}
+	log.Printf(" State root: %v\n", root)
+}
+
+func tokenCommand(params []string) {
+	if len(params) == 0 {
+		log.Println("Bad input! Use -h or --help for help menu!")
+		return
+	}
+	switch {
+	case params[0] == "transfer" && len(params) == 5:
+		conAddr, success := getContractHash(params[1])
+		if !success {
+			return
+		}
+		receiver, success := getKeyHash(params[2])
+		if !success {
+			return
+		}
+		amount, err := strconv.ParseInt(params[3], 10, 64)
+		if err != nil {
+			log.Println("Bad number as token amount")
+			return
+		}
+		gas, err := strconv.ParseUint(params[4], 10, 64)
+		if err != nil || gas == 0 {
+			log.Println("Bad number as contract gas")
+			return
+		}
+		callParams, err := tokenstd.TransferParams(receiver, amount)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		conCall := objects.CreateContractCall("CALL", tokenstd.EntryTransfer, callParams, 0, gas, objects.MinGasPrice, conAddr, publicKey, nextNonce(), 0, secretKey)
+		log.Printf("Token transfer of %v to %v has been created!", amount, objects.AccountAddress(receiver))
+		channels.TransClientInput <- objects.TransData{ContractCall: conCall}
+
+	case params[0] == "balance" && (len(params) == 2 || len(params) == 3):
+		conAddr, success := getContractHash(params[1])
+		if !success {
+			return
+		}
+		holder := publicKey.Hash()
+		if len(params) == 3 {
+			keyHash, success := getKeyHash(params[2])
+			if !success {
+				return
+			}
+			holder = keyHash
+		}
+		conState := smart.GetContractState(conAddr)
+		if conState.Storage == nil {
+			log.Printf("Contract %v does not exist", params[1])
+			return
+		}
+		balance, err := tokenstd.Balance(conState.Storage, holder)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		supply, err := tokenstd.TotalSupply(conState.Storage)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		log.Printf(" Token: %v \n Holder: %v \n Balance: %v \n Total supply: %v\n", params[1], objects.AccountAddress(holder), balance, supply)
+
+	default:
+		log.Println("Bad input! Use -h or --help for help menu!")
+	}
+}
+
+func multisigCommand(params []string) {
+	params, submit, ok := takeSubmitFlags(params)
+	if !ok || len(params) == 0 {
+		log.Println("Bad input! Use -h or --help for help menu!")
+		return
+	}
+	switch {
+	case params[0] == "create" && len(params) >= 3:
+		threshold, err := strconv.Atoi(params[1])
+		if err != nil {
+			log.Println("Bad number as threshold")
+			return
+		}
+		var keys []crypto.PublicKey
+		for _, s := range params[2:] {
+			key, success := getMemberKey(s)
+			if !success {
+				return
+			}
+			keys = append(keys, key)
+		}
+		multisig, err := crypto.NewMultisigKey(threshold, keys)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		log.Printf("Multisig account of %v of %v keys:\n    Address: %v\n    Key: %v\n", threshold, len(keys),
+			multisig.Address(), multisig.String())
+
+	case params[0] == "transaction" && (len(params) == 5 || len(params) == 6):
+		multisig, err := crypto.ParsePublicKey(params[1])
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		receiver, success := getKeyHash(params[2])
+		if !success {
+			return
+		}
+		amount, err := strconv.ParseUint(params[3], 10, 64)
+		if err != nil || amount == 0 {
+			log.Println("Bad number as transaction amount")
+			return
+		}
+		fee := transaction.TransferFee(objects.MinGasPrice)
+		if len(params) == 6 {
+			fee, err = strconv.ParseUint(params[5], 10, 64)
+			if err != nil {
+				log.Println("Bad number as transaction fee")
+				return
+			}
+		}
+		nonce := transaction.GetNextNonce(multisig.Hash())
+		if submit.hasNonce {
+			nonce = submit.nonce
+		}
+		p, err := objects.NewPartialTransaction(multisig, receiver, amount, fee, nonce, submit.validUntil)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		if err := p.Sign(secretKey); err != nil {
+			log.Println(err)
+			return
+		}
+		if err := ioutil.WriteFile(params[4], p.Encode(), 0644); err != nil {
+			log.Println(err)
+			return
+		}
+		log.Printf("Transaction with nonce %v has been signed and written to %v\n", p.Transaction.Nonce, params[4])
+
+	case params[0] == "sign" && (len(params) == 2 || len(params) == 3):
+		p, success := readPartialTransaction(params[1])
+		if !success {
+			return
+		}
+		if err := p.Sign(secretKey); err != nil {
+			log.Println(err)
+			return
+		}
+		output := params[1]
+		if len(params) == 3 {
+			output = params[2]
+		}
+		if err := ioutil.WriteFile(output, p.Encode(), 0644); err != nil {
+			log.Println(err)
+			return
+		}
+		log.Printf("Transaction of %v to %v has been signed and written to %v\n", p.Transaction.Amount,
+			objects.AccountAddress(p.Transaction.To), output)
+
+	case params[0] == "combine" && len(params) >= 2:
+		p, success := readPartialTransaction(params[1])
+		if !success {
+			return
+		}
+		for _, file := range params[2:] {
+			other, success := readPartialTransaction(file)
+			if !success {
+				return
+			}
+			if err := p.Merge(other); err != nil {
+				log.Println(err)
+				return
+			}
+		}
+		trans, err := p.Combine()
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		log.Printf("Transaction from %v with nonce %v has been created!", trans.From.Address(), trans.Nonce)
+		submit.send(objects.TransData{Transaction: trans})
+
+	default:
+		log.Println("Bad input! Use -h or --help for help menu!")
+	}
+}
+
+// bakerCommand registers a baking key for the account of this node. The node baking with the key runs with the
+// baking key as its key, and only has to give its consent, so the key of the account stays off that machine
+func bakerCommand(params []string) {
+	params, submit, ok := takeSubmitFlags(params)
+	if !ok || len(params) == 0 {
+		log.Println("Bad input! Use -h or --help for help menu!")
+		return
+	}
+	fee := transaction.TransferFee(objects.MinGasPrice)
+	parseFee := func(s string) bool {
+		var err error
+		fee, err = strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			log.Println("Bad number as fee")
+			return false
+		}
+		return true
+	}
+	switch {
+	case params[0] == "consent" && len(params) == 3:
+		account, success := getMemberKey(params[1])
+		if !success {
+			return
+		}
+		validUntil, err := strconv.ParseUint(params[2], 10, 64)
+		if err != nil || validUntil == 0 {
+			log.Println("Bad number as slot")
+			return
+		}
+		log.Printf("Give these to the owner of %v to register your key as its baking key with -validuntil %v:\n"+
+			"    Baking key: %v\n    Consent: %v\n",
+			account.Address(), validUntil, publicKey.String(), objects.BakingConsent(account, validUntil, secretKey))
+
+	case params[0] == "register" && (len(params) == 3 || len(params) == 4):
+		bakingKey, success := getMemberKey(params[1])
+		if !success {
+			return
+		}
+		if len(params) == 4 && !parseFee(params[3]) {
+			return
+		}
+		// the consent is checked before a nonce is taken, so a bad consent doesn't leave a gap in the nonces
+		check := objects.CreateBakerRegistration(publicKey, bakingKey, params[2], fee, 0, submit.validUntil, secretKey)
+		if !check.Verify() {
+			log.Printf("The consent is not a signature of %v on your account and the slot given with -validuntil\n",
+				bakingKey.Address())
+			return
+		}
+		r := objects.CreateBakerRegistration(publicKey, bakingKey, params[2], fee, submit.nextNonce(), submit.validUntil, secretKey)
+		log.Printf("Baker registration with nonce %v has been created!", r.Nonce)
+		submit.send(objects.TransData{Registration: r})
+
+	case params[0] == "unregister" && (len(params) == 1 || len(params) == 2):
+		if len(params) == 2 && !parseFee(params[1]) {
+			return
+		}
+		consent := objects.BakingConsent(publicKey, submit.validUntil, secretKey)
+		r := objects.CreateBakerRegistration(publicKey, publicKey, consent, fee, submit.nextNonce(), submit.validUntil, secretKey)
+		log.Printf("Baker registration with nonce %v has been created!", r.Nonce)
+		submit.send(objects.TransData{Registration: r})
+
+	case params[0] == "info" && (len(params) == 1 || len(params) == 2):
+		account := publicKey.Hash()
+		if len(params) == 2 {
+			var success bool
+			if account, success = getKeyHash(params[1]); !success {
+				return
+			}
+		}
+		if bakingKey, registered := transaction.GetBakingKey(account); registered {
+			log.Printf("%v bakes with the key %v\n", objects.AccountAddress(account), bakingKey.Address())
+		} else {
+			log.Printf("%v bakes with its own key\n", objects.AccountAddress(account))
+		}
+
+	default:
+		log.Println("Bad input! Use -h or --help for help menu!")
+	}
+}
+
+// delegateCommand delegates the stake of the account of this node to a baker, or ends the delegation
+func delegateCommand(params []string, undelegate bool) {
+	params, submit, ok := takeSubmitFlags(params)
+	args := 1
+	if undelegate {
+		args = 0
+	}
+	if !ok || len(params) < args || len(params) > args+1 {
+		log.Println("Bad input! Use -h or --help for help menu!")
+		return
+	}
+	baker := ""
+	if !undelegate {
+		var success bool
+		if baker, success = getKeyHash(params[0]); !success {
+			return
+		}
+	}
+	fee := transaction.TransferFee(objects.MinGasPrice)
+	if len(params) > args {
+		var err error
+		fee, err = strconv.ParseUint(params[args], 10, 64)
+		if err != nil {
+			log.Println("Bad number as fee")
+			return
+		}
+	}
+	d := objects.CreateDelegation(publicKey, baker, fee, submit.nextNonce(), submit.validUntil, secretKey)
+	log.Printf("Delegation with nonce %v has been created!", d.Nonce)
+	submit.send(objects.TransData{Delegation: d})
+}
+
+// delegationCommand prints who an account delegates to, and who delegates to it
+func delegationCommand(params []string) {
+	account := publicKey.Hash()
+	if len(params) > 1 {
+		log.Println("Bad input! Use -h or --help for help menu!")
+		return
+	}
+	if len(params) == 1 {
+		var success bool
+		if account, success = getKeyHash(params[0]); !success {
+			return
+		}
+	}
+	baker, delegators := transaction.GetDelegation(account)
+	if baker != "" {
+		log.Printf("%v delegates to %v\n", objects.AccountAddress(account), objects.AccountAddress(baker))
+	} else {
+		log.Printf("%v doesn't delegate\n", objects.AccountAddress(account))
+	}
+	for _, delegator := range delegators {
+		log.Printf("    Delegated to by %v\n", objects.AccountAddress(delegator))
+	}
+}
+
+// getMemberKey returns a public key given in full, or the known public key with the given address or prefix
+func getMemberKey(s string) (crypto.PublicKey, bool) {
+	if strings.Contains(s, ":") {
+		key, err := crypto.ParsePublicKey(s)
+		if err != nil {
+			log.Println(err)
+			return crypto.PublicKey{}, false
+		}
+		return key, true
+	}
+	return getPK(s)
+}
+
+func readPartialTransaction(file string) (objects.PartialTransaction, bool) {
+	data, err := readFromFile(file)
+	if err != nil {
+		log.Println("Error in reading file: " + err.Error())
+		return objects.PartialTransaction{}, false
+	}
+	p, err := objects.DecodePartialTransaction(data)
+	if err != nil {
+		log.Printf("%v is not a partial signature: %v\n", file, err)
+		return objects.PartialTransaction{}, false
+	}
+	return p, true
+}
+
+// runRepl reads declarations and expressions of the contract language and prints their types and values, until
+// :quit is entered. Input continues over several lines until it is complete, or a blank line is entered
+func runRepl(l *readline.Instance) {
+	repl := interpreter.NewRepl(10000000)
+	repl.Sender = publicKey.Hash()
+	l.SetHistoryPath("/tmp/repl.tmp")
+	defer l.SetHistoryPath("/tmp/readline.tmp")
+	defer l.SetPrompt("\033[31m»\033[0m ")
+	fmt.Fprintln(l.Stdout(), "Contract language REPL. Use :help for commands and :quit to return")
+
+	var input []string
+	for {
+		if len(input) == 0 {
+			l.SetPrompt("\033[34mλ\033[0m ")
+		} else {
+			l.SetPrompt("  ")
+		}
+		line, err := l.Readline()
+		if err == readline.ErrInterrupt {
+			input = nil
+			continue
+		} else if err == io.EOF {
+			return
+		}
+
+		if len(input) == 0 && strings.HasPrefix(strings.TrimSpace(line), ":") {
+			if !replCommand(repl, strings.Fields(line), l.Stdout()) {
+				return
+			}
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			if len(input) > 0 {
+				fmt.Fprintln(l.Stdout(), "Error: "+interpreter.ErrIncompleteInput.Error())
+				input = nil
+			}
+			continue
+		}
+		input = append(input, line)
+		output, err := repl.Eval(strings.Join(input, "\n"))
+		if err == interpreter.ErrIncompleteInput {
+			continue
+		}
+		input = nil
+		if err != nil {
+			fmt.Fprintln(l.Stdout(), "Error: "+err.Error())
+		} else if output != "" {
+			fmt.Fprintln(l.Stdout(), output)
+		}
+	}
+}
+
+// replCommand handles the REPL commands starting with ':', and returns false when the REPL should be left
+func replCommand(repl *interpreter.Repl, params []string, out io.Writer) bool {
+	setUint := func(name string, target *uint64) {
+		if len(params) != 2 {
+			fmt.Fprintf(out, "%s is %d\n", name, *target)
+			return
+		}
+		i, err := strconv.ParseUint(params[1], 10, 64)
+		if err != nil {
+			fmt.Fprintf(out, "Bad number as %s\n", name)
+			return
+		}
+		*target = i
+	}
+	switch params[0] {
+	case ":quit", ":q":
+		return false
+	case ":reset":
+		repl.Reset()
+	case ":gas":
+		setUint("gas", &repl.Gas)
+	case ":amount":
+		setUint("amount", &repl.Amount)
+	case ":balance":
+		setUint("balance", &repl.Balance)
+	case ":sender":
+		if len(params) != 2 {
+			fmt.Fprintf(out, "sender is %s\n", objects.AccountAddress(repl.Sender))
+		} else if keyHash, success := getKeyHash(params[1]); success {
+			repl.Sender = keyHash
+		}
+	case ":help":
+		fmt.Fprintln(out, "  type t = ..., let f (x : t) = ..., import M = ...   declare a type, helper or module")
+		fmt.Fprintln(out, "  let x = EXP                                          bind the value of EXP to x")
+		fmt.Fprintln(out, "  EXP                                                  print the value and type of EXP")
+		fmt.Fprintln(out, "  Helpers are called through the module "+interpreter.ReplModule+", e.g. "+interpreter.ReplModule+".f 1")
+		fmt.Fprintln(out, "  :gas [N], :amount [N], :balance [N], :sender [KEY]   show or set the gas budget and Current")
+		fmt.Fprintln(out, "  :reset                                               forget all declarations and bindings")
+		fmt.Fprintln(out, "  :quit                                                return to the command line")
+	default:
+		fmt.Fprintln(out, "Unknown command, use :help for commands")
+	}
+	return true
+}
+
+// loadKey sets the key of this node. Without a keyfile a new key is generated, otherwise the account is unlocked, or
+// created if the keyfile doesn't hold it yet
+func loadKey() error {
+	if *keyfile == "" {
+		secretKey, publicKey = crypto.KeyGen()
+		return nil
+	}
+	var err error
+	keystore, err = crypto.OpenKeystore(*keyfile)
+	if err != nil {
+		return err
+	}
+	if _, exists := keystore.PublicKey(*account); exists {
+		passphrase, err := readline.Password(fmt.Sprintf("Passphrase of %s: ", *account))
+		if err != nil {
+			return err
+		}
+		secretKey, err = keystore.Unlock(*account, string(passphrase))
+		publicKey = secretKey.Pk
+		return err
+	}
+
+	fmt.Printf("Creating account %s in %s\n", *account, *keyfile)
+	passphrase, err := readNewPassphrase(readline.Password)
+	if err != nil {
+		return err
+	}
+	secretKey, publicKey = crypto.KeyGen()
+	return keystore.Add(*account, secretKey, passphrase)
+}
+
+// readNewPassphrase asks for a passphrase twice, and fails if they differ
+func readNewPassphrase(readPassword func(prompt string) ([]byte, error)) (string, error) {
+	passphrase, err := readPassword("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	repeated, err := readPassword("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if string(passphrase) != string(repeated) {
+		return "", fmt.Errorf("passphrases did not match")
+	}
+	return string(passphrase), nil
+}
+
+func keysCommand(params []string, l *readline.Instance) {
+	if len(params) == 1 && params[0] == "mnemonic" {
+		mnemonic, err := crypto.NewMnemonic(24)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		log.Printf("Write down this phrase and keep it secret. Every account can be derived from it with keys derive:\n    %v\n", mnemonic)
+		return
 	}
+	if keystore == nil {
+		log.Println("No keyfile. Start with -keyfile to use one")
+		return
+	}
+	switch {
+	case len(params) == 0 || (params[0] == "list" && len(params) == 1):
+		for _, name := range keystore.Names() {
+			pk, _ := keystore.PublicKey(name)
+			active := ""
+			if pk == publicKey {
+				active = " (this node)"
+			}
+			log.Printf("%v: %v %v%v\n", name, pk.Scheme, pk.Address(), active)
+		}
+
+	case params[0] == "new" && len(params) == 2:
+		passphrase, err := readNewPassphrase(l.ReadPassword)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		sk, pk := crypto.KeyGen()
+		if err := keystore.Add(params[1], sk, passphrase); err != nil {
+			log.Println(err)
+			return
+		}
+		log.Printf("Created account %v with address %v\n", params[1], pk.Address())
+
+	case params[0] == "import" && len(params) == 2:
+		key, err := l.ReadPassword("Key: ")
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		sk, err := crypto.ImportKey(string(key))
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		passphrase, err := readNewPassphrase(l.ReadPassword)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		if err := keystore.Add(params[1], sk, passphrase); err != nil {
+			log.Println(err)
+			return
+		}
+		log.Printf("Imported account %v with address %v\n", params[1], sk.Pk.Address())
+
+	case params[0] == "derive" && len(params) == 3:
+		n, err := strconv.ParseUint(params[2], 10, 31)
+		if err != nil {
+			log.Println("Bad number as account")
+			return
+		}
+		mnemonic, err := l.ReadPassword("Mnemonic: ")
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		mnemonicPassphrase, err := l.ReadPassword("Passphrase of the mnemonic, if any: ")
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		sk, err := crypto.AccountKey(string(mnemonic), string(mnemonicPassphrase), uint32(n))
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		passphrase, err := readNewPassphrase(l.ReadPassword)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		if err := keystore.Add(params[1], sk, passphrase); err != nil {
+			log.Println(err)
+			return
+		}
+		log.Printf("Derived account %v at %v with address %v\n", params[1], crypto.AccountPath(uint32(n)), sk.Pk.Address())
+
+	case params[0] == "export" && len(params) == 2:
+		passphrase, err := l.ReadPassword(fmt.Sprintf("Passphrase of %s: ", params[1]))
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		sk, err := keystore.Unlock(params[1], string(passphrase))
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		log.Println(crypto.ExportKey(sk))
+
+	default:
+		log.Println("Bad input! Use -h or --help for help menu!")
+	}
+}
+
+// submitOptions says what to do with a transaction created by the CLI. It is sent to the network, unless an output
+// file is given. A transaction written to a file can be created on a machine that isn't on the network, and sent
+// later with broadcast. Such a machine doesn't know the next nonce of the account, so it has to be given. A
+// transaction with validUntil can't be included in a block after that slot, while 0 means it never expires
+type submitOptions struct {
+	output     string
+	nonce      uint64
+	hasNonce   bool
+	validUntil uint64
+}
+
+// takeSubmitFlags removes the -o, -nonce and -validuntil flags from params
+func takeSubmitFlags(params []string) ([]string, submitOptions, bool) {
+	var rest []string
+	var o submitOptions
+	for i := 0; i < len(params); i++ {
+		switch params[i] {
+		case "-o", "-nonce", "-validuntil":
+			if i+1 == len(params) {
+				log.Printf("Missing value of %v\n", params[i])
+				return nil, o, false
+			}
+			switch params[i] {
+			case "-o":
+				o.output = params[i+1]
+			case "-nonce":
+				nonce, err := strconv.ParseUint(params[i+1], 10, 64)
+				if err != nil {
+					log.Println("Bad number as nonce")
+					return nil, o, false
+				}
+				o.nonce, o.hasNonce = nonce, true
+			case "-validuntil":
+				slot, err := strconv.ParseUint(params[i+1], 10, 64)
+				if err != nil {
+					log.Println("Bad number as slot")
+					return nil, o, false
+				}
+				o.validUntil = slot
+			}
+			i++
+		default:
+			rest = append(rest, params[i])
+		}
+	}
+	return rest, o, true
+}
+
+func (o submitOptions) nextNonce() uint64 {
+	if o.hasNonce {
+		return o.nonce
+	}
+	if o.output != "" {
+		// a transaction written to a file might never be sent, so the transactions sent after it don't wait for it
+		return peekNonce()
+	}
+	return nextNonce()
+}
+
+func (o submitOptions) send(td objects.TransData) {
+	if o.output == "" {
+		channels.TransClientInput <- td
+		return
+	}
+	if err := ioutil.WriteFile(o.output, td.Encode(), 0644); err != nil {
+		log.Println(err)
+		return
+	}
+	log.Printf("Signed transaction %v has been written to %v. Send it with broadcast\n", td.Hash(), o.output)
+}
+
+// broadcastCommand sends a signed transaction from a file written with -o
+func broadcastCommand(file string) {
+	data, err := readFromFile(file)
+	if err != nil {
+		log.Println("Error in reading file: " + err.Error())
+		return
+	}
+	td, err := objects.DecodeTransData(data)
+	if err != nil {
+		log.Printf("%v is not a signed transaction: %v\n", file, err)
+		return
+	}
+	if !td.Verify() {
+		log.Println("The signature of the transaction doesn't verify")
+		return
+	}
+	sender := td.Sender().Hash()
+	if next := transaction.GetNextNonce(sender); td.GetNonce() < next {
+		log.Printf("Nonce %v of the transaction has already been used, the next nonce of %v is %v\n", td.GetNonce(),
+			objects.AccountAddress(sender), next)
+		return
+	}
+	if sender == publicKey.Hash() {
+		// the nonce wasn't counted as sent when the transaction was written to the file
+		nonceLock.Lock()
+		if td.GetNonce() >= pendingNonce {
+			pendingNonce = td.GetNonce() + 1
+		}
+		nonceLock.Unlock()
+	}
+	log.Printf("Transaction %v from %v with nonce %v has been sent!\n", td.Hash(), objects.AccountAddress(sender), td.GetNonce())
+	channels.TransClientInput <- td
+}
+
+// getKeyHash returns the key hash of an account. Accepts either a full address, which is rejected if its checksum is
+// wrong, or a prefix of the address of a known public key
+func getKeyHash(s string) (string, bool) {
+	keyHash, err := objects.ParseAccount(s)
+	if err == nil {
+		return keyHash, true
+	}
+	pk, success := getPK(s)
+	if !success {
+		return "", false
+	}
+	return pk.Hash(), true
+}
+
+// getPK returns the known public key with the given address, or the only known public key whose address starts with
+// the given prefix. An address with a typo is never the prefix of a known address, so it is rejected
+func getPK(s string) (crypto.PublicKey, bool) {
+	pkList := p2p.GetPublicKeys()
+	keyHash, err := objects.ParseAccount(s)
+	matches := make([]crypto.PublicKey, 0)
 	for _, pk := range pkList {
-		if strings.HasPrefix(pk.Hash(), prefix) {
+		if pk.Hash() == keyHash {
 			return pk, true
 		}
+		if len(s) > len(crypto.AccountPrefix) && strings.HasPrefix(pk.Address(), s) {
+			matches = append(matches, pk)
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		return matches[0], true
+	case len(matches) > 1:
+		log.Printf("Several known Public Keys have an address starting with %v", s)
+	case err == nil:
+		log.Printf("Public Key for %v is not known", s)
+	default:
+		log.Println(err)
 	}
 	return crypto.PublicKey{}, false
 }
+
+// getContractHash returns the hash of a contract address, which is rejected if its checksum is wrong
+func getContractHash(s string) (string, bool) {
+	hash, err := objects.ParseContract(s)
+	if err != nil {
+		log.Println(err)
+		return "", false
+	}
+	return hash, true
+}
+
+// nextNonce returns the nonce of the next transaction sent from this node. Transactions that aren't in the current
+// head yet are counted too, so several transactions can be sent before a block includes them
+func nextNonce() uint64 {
+	return takeNonce(true)
+}
+
+// peekNonce returns the nonce nextNonce would return, without counting a transaction with it as sent
+func peekNonce() uint64 {
+	return takeNonce(false)
+}
+
+func takeNonce(send bool) uint64 {
+	nonceLock.Lock()
+	defer nonceLock.Unlock()
+	// when a transaction of ours expired, the nonces after the head are taken again from the first one not used
+	evicted := consensus.TakeEvicted(publicKey.Hash())
+	if next := transaction.GetNextNonce(publicKey.Hash()); next > pendingNonce || evicted {
+		pendingNonce = next
+	}
+	nonce := pendingNonce
+	if send {
+		pendingNonce++
+	}
+	return nonce
+}
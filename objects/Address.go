@@ -0,0 +1,47 @@
+package objects
+
+import (
+	. "github.com/nfk93/blockchain/crypto"
+	"strings"
+)
+
+// The ledger is keyed by the hex encoded hashes of keys and contracts, while users see the checksummed addresses.
+// The encoding itself is in the crypto package, as the contract language reads and writes addresses too and can't
+// depend on objects
+
+// AccountAddress returns the address of the account with the given key hash, or the key hash itself if it isn't the
+// hash of a key
+func AccountAddress(keyHash string) string {
+	address, err := EncodeAccount(keyHash)
+	if err != nil {
+		return keyHash
+	}
+	return address
+}
+
+// ContractAddress returns the address of the contract with the given hash, or the hash itself if it isn't the hash of
+// a contract
+func ContractAddress(contractHash string) string {
+	address, err := EncodeContract(contractHash)
+	if err != nil {
+		return contractHash
+	}
+	return address
+}
+
+// ParseAccount returns the key hash of an account address, or an error if the address is malformed or its checksum
+// doesn't match
+func ParseAccount(address string) (string, error) {
+	return DecodeAccount(address)
+}
+
+// ParseContract returns the hash of a contract address, or an error if the address is malformed or its checksum
+// doesn't match
+func ParseContract(address string) (string, error) {
+	return DecodeContract(address)
+}
+
+// IsContractAddress returns whether s is written like the address of a contract rather than an account
+func IsContractAddress(s string) bool {
+	return strings.HasPrefix(s, ContractPrefix)
+}
@@ -0,0 +1,26 @@
+package objects
+
+import (
+	. "github.com/nfk93/blockchain/crypto"
+	"testing"
+)
+
+func TestAddress(t *testing.T) {
+	_, pk := KeyGen()
+	address := AccountAddress(pk.Hash())
+	if address != pk.Address() {
+		t.Error("AccountAddress should be the address of the key")
+	}
+	if keyHash, err := ParseAccount(address); err != nil || keyHash != pk.Hash() {
+		t.Error("The address should parse to the key hash")
+	}
+	if _, err := ParseContract(address); err == nil {
+		t.Error("An account address isn't the address of a contract")
+	}
+	if IsContractAddress(address) || !IsContractAddress(ContractAddress(pk.Hash())) {
+		t.Error("Only contract addresses should be recognized as such")
+	}
+	if AccountAddress("abc") != "abc" {
+		t.Error("Something that isn't a hash should be returned as it is")
+	}
+}
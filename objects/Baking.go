@@ -0,0 +1,138 @@
+package objects
+
+import (
+	. "github.com/nfk93/blockchain/crypto"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// BakerRegistration binds BakingKey to the account of Account. The baking key signs the blocks baked with the stake
+// of the account, and their rewards are paid to the account, so the key of the account can be kept off the machine
+// that bakes. Consent is the signature of the baking key on the account and ValidUntilSlot, so nobody can claim a key
+// they don't hold, and the consent can't be used again once the slot has passed.
+// Registering the key of the account itself undoes the registration, and the account bakes with its own key again
+type BakerRegistration struct {
+	Account        PublicKey
+	BakingKey      PublicKey
+	Fee            uint64
+	Nonce          uint64
+	ValidUntilSlot uint64
+	Consent        string
+	Signature      string
+}
+
+// BakingConsent returns the consent of the baking key of signer to bake for account, given in a registration that
+// expires at validUntilSlot
+func BakingConsent(account PublicKey, validUntilSlot uint64, signer Signer) string {
+	return signer.Sign(consentMessage(account, validUntilSlot))
+}
+
+func (r BakerRegistration) stringToSign() string {
+	e := newEncoder()
+	r.encode(e, false)
+	return e.String()
+}
+
+func (r *BakerRegistration) Sign(signer Signer) {
+	r.Signature = signer.Sign(r.stringToSign())
+}
+
+// Verify checks both the signature of the account and the consent of the baking key
+func (r *BakerRegistration) Verify() bool {
+	return r.Account.Verify(r.stringToSign(), r.Signature) && r.BakingKey.Verify(consentMessage(r.Account, r.ValidUntilSlot), r.Consent)
+}
+
+func CreateBakerRegistration(account PublicKey, bakingKey PublicKey, consent string, fee uint64, nonce uint64, validUntilSlot uint64, signer Signer) BakerRegistration {
+	r := BakerRegistration{account, bakingKey, fee, nonce, validUntilSlot, consent, ""}
+	r.Sign(signer)
+	return r
+}
+
+// RegisterBaker registers the baking key of r and pays its fee, which has to pay for gas units of gas at
+// MinGasPrice at least.
+// Returns the fee
+func (s *State) RegisterBaker(r BakerRegistration, gas uint64) (uint64, error) {
+	if !r.Verify() {
+		return 0, errors.New("Baker registration isn't signed by both the account and the baking key!")
+	}
+	// an account of any scheme can go back to baking itself, which unregisters its baking key
+	if r.BakingKey.Scheme != Ed25519 && r.BakingKey.Hash() != r.Account.Hash() {
+		return 0, errors.New("A baking key has to be an ed25519 key, as the draws are made with its VRF!")
+	}
+	if r.ValidUntilSlot == 0 && r.BakingKey.Hash() != r.Account.Hash() {
+		return 0, errors.New("The consent of a baking key has to expire, so it can't be used again later!")
+	}
+	account := r.Account.Hash()
+	bakingKey := r.BakingKey.Hash()
+	if other := s.Tree.Get(BakingAccountKey(bakingKey)); other != nil && string(other) != account {
+		return 0, errors.New("The key is already the baking key of another account!")
+	}
+
+	if err := s.useNonce(r.Account, r.Nonce); err != nil {
+		return 0, err
+	}
+	if minFee, ok := gasCost(gas, MinGasPrice); !ok || r.Fee < minFee {
+		return 0, errors.New("Baker registration fee is below the minimum gas price!")
+	}
+	balance := s.Balance(account)
+	if balance < r.Fee {
+		return 0, errors.New("Not enough funds for Baker registration!")
+	}
+	s.SetBalance(account, balance-r.Fee)
+	s.TotalStake -= r.Fee
+
+	if old, registered := s.BakingKey(account); registered {
+		s.Tree = s.Tree.Set(BakingAccountKey(old.Hash()), nil)
+	}
+	if bakingKey == account {
+		s.Tree = s.Tree.Set(BakerKey(account), nil)
+	} else {
+		s.Tree = s.Tree.Set(BakerKey(account), []byte(r.BakingKey.String()))
+		s.Tree = s.Tree.Set(BakingAccountKey(bakingKey), []byte(account))
+	}
+	return r.Fee, nil
+}
+
+// BakingKey returns the baking key registered by the account with the given key hash
+func (s State) BakingKey(account string) (PublicKey, bool) {
+	value := s.Tree.Get(BakerKey(account))
+	if value == nil {
+		return PublicKey{}, false
+	}
+	key, err := ParsePublicKey(string(value))
+	return key, err == nil
+}
+
+// Bakers returns the accounts that registered a baking key, keyed by the hash of their baking key
+func (s State) Bakers() map[string]string {
+	bakers := make(map[string]string)
+	s.Tree.Iterate(func(key string, value []byte) {
+		if strings.HasPrefix(key, bakingAccountPrefix) {
+			bakers[key[len(bakingAccountPrefix):]] = string(value)
+		}
+	})
+	return bakers
+}
+
+// BakerAccount returns the key hash of the account baker bakes for, which is its own account unless it is a
+// registered baking key. It is empty if the account of baker registered another key to bake for it
+func (s State) BakerAccount(baker PublicKey) string {
+	if account := s.Tree.Get(BakingAccountKey(baker.Hash())); account != nil {
+		return string(account)
+	}
+	if _, registered := s.BakingKey(baker.Hash()); registered {
+		return ""
+	}
+	return baker.Hash()
+}
+
+// PayBaker pays the reward of a block baked by baker to the account it bakes for, which shares it with the accounts
+// delegating to it
+func (s *State) PayBaker(baker PublicKey, reward uint64) {
+	account := s.BakerAccount(baker)
+	if account == "" {
+		account = baker.Hash()
+	}
+	s.shareReward(account, reward)
+	s.TotalStake += reward
+}
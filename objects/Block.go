@@ -1,9 +1,8 @@
 package objects
 
 import (
-	"bytes"
+	"encoding/hex"
 	. "github.com/nfk93/blockchain/crypto"
-	"strconv"
 )
 
 type Block struct {
@@ -14,7 +13,8 @@ type Block struct {
 	BlockNonce     BlockNonce
 	LastFinalized  string //hash of last finalized block
 	BlockData      BlockData
-	StateHash      string
+	MerkleRoot     string // root of the Merkle tree over the transactions in BlockData, see BlockData.MerkleRoot
+	StateRoot      string // root of the state tree after the block, see State.Root
 	BlockSignature string
 }
 
@@ -43,76 +43,61 @@ type TransData struct {
 	Transaction  Transaction
 	ContractCall ContractCall
 	ContractInit ContractInitialize
+	Registration BakerRegistration
+	Delegation   Delegation
 }
 
 func (t TransData) Hash() string {
-	return t.Transaction.toString() + t.ContractCall.toString() + t.ContractInit.toString()
+	return HashSHA(string(t.Encode()))
 }
 
 // Block Functions
-func (b *Block) SignBlock(sk SecretKey) {
-	m := b.toString()
-	b.BlockSignature = Sign(m, sk)
+
+// SignBlock sets the Merkle root of the transactions and signs the block. The signature covers the root instead of
+// the transactions themselves
+func (b *Block) SignBlock(signer Signer) {
+	b.MerkleRoot = b.BlockData.MerkleRoot()
+	m := b.stringToSign()
+	b.BlockSignature = signer.Sign(m)
 }
 
+// ValidateBlock checks the signature of the block, and that the Merkle root matches its transactions
 func (b Block) ValidateBlock() bool {
-	return Verify(b.toString(), b.BlockSignature, b.BakerID)
+	return b.MerkleRoot == b.BlockData.MerkleRoot() && b.BakerID.Verify(b.stringToSign(), b.BlockSignature)
 }
 
-func (b Block) toString() string {
-	var buf bytes.Buffer
-	buf.WriteString(strconv.Itoa(int(b.Slot)))
-	buf.WriteString(b.ParentPointer)
-	buf.WriteString(b.BakerID.String())
-	buf.WriteString(b.Draw)
-	buf.WriteString(b.BlockNonce.Nonce)
-	buf.WriteString(b.BlockNonce.Proof)
-	buf.WriteString(b.LastFinalized)
-	buf.WriteString(b.BlockData.toString())
-	buf.WriteString(b.StateHash)
-	return buf.String()
+// stringToSign returns the encoding of the block without its block data and signature
+func (b Block) stringToSign() string {
+	e := newEncoder()
+	b.encode(e, false)
+	return e.String()
 }
 
+// CalculateBlockHash returns the hash of the block as signed by the baker
 func (b *Block) CalculateBlockHash() string {
-	return HashSHA(b.toString())
+	return HashSHA(b.stringToSign())
 }
 
-func (d *BlockData) toString() string {
-	var buf bytes.Buffer
-	for _, t := range d.Trans {
-		switch t.GetType() {
-		case 1:
-			buf.WriteString(t.Transaction.toString())
-		case 2:
-			buf.WriteString(t.ContractCall.toString())
-		case 3:
-			buf.WriteString(t.ContractInit.toString())
+// BlockNonce Functions
 
-		}
+// CreateNewBlockNonce computes the VRF of the baking key on the leadership nonce and slot. The output is the nonce,
+// which goes into the leadership nonce of a later epoch, and can't be chosen by the baker
+func CreateNewBlockNonce(leadershipNonce string, sk SecretKey, slot uint64) (BlockNonce, error) {
+	proof, err := sk.VRFProve([]byte(nonceMessage(leadershipNonce, slot)))
+	if err != nil {
+		return BlockNonce{}, err
 	}
-	return buf.String()
-}
-
-// BlockNonce Functions
-func CreateNewBlockNonce(leadershipNonce string, sk SecretKey, slot uint64) BlockNonce {
-	var buf bytes.Buffer
-	buf.WriteString("NONCE") //Old block nonce
-	buf.WriteString(leadershipNonce)
-	buf.WriteString(strconv.Itoa(int(slot)))
-	newNonceString := buf.String()
-	proof := Sign(newNonceString, sk)
-	newNonce := HashSHA(proof)
-	return BlockNonce{newNonce, proof}
+	output, _ := VRFProofToHash(proof)
+	return BlockNonce{hex.EncodeToString(output), hex.EncodeToString(proof)}, nil
 }
 
 func (b *Block) ValidateBlockNonce(leadershipNonce string) bool {
-	var buf bytes.Buffer
-	buf.WriteString("NONCE") //Old block nonce
-	buf.WriteString(leadershipNonce)
-	buf.WriteString(strconv.Itoa(int(b.Slot)))
-	correctSignature := Verify(buf.String(), b.BlockNonce.Proof, b.BakerID)
-	correctNonce := HashSHA(b.BlockNonce.Proof) == b.BlockNonce.Nonce
-	return correctSignature && correctNonce
+	proof, err := hex.DecodeString(b.BlockNonce.Proof)
+	if err != nil {
+		return false
+	}
+	output, err := b.BakerID.VRFVerify([]byte(nonceMessage(leadershipNonce, b.Slot)), proof)
+	return err == nil && hex.EncodeToString(output) == b.BlockNonce.Nonce
 }
 
 func (t TransData) GetType() int {
@@ -128,20 +113,73 @@ func (t TransData) GetType() int {
 		string(t.ContractInit.Code) != "" {
 		return CONTRACTINIT
 	}
+	if t.Registration != (BakerRegistration{}) {
+		return BAKERREGISTRATION
+	}
+	if t.Delegation != (Delegation{}) {
+		return DELEGATION
+	}
 	return ERROR
 }
 
-func (t TransData) GetNonce() string {
-	if t.Transaction != (Transaction{}) {
-		return t.Transaction.ID
-	}
-	if t.ContractCall != (ContractCall{}) {
+// GetNonce returns the account nonce of the transaction
+func (t TransData) GetNonce() uint64 {
+	switch t.GetType() {
+	case TRANSACTION:
+		return t.Transaction.Nonce
+	case CONTRACTCALL:
 		return t.ContractCall.Nonce
-	}
-	if t.ContractInit.Owner != (PublicKey{}) {
+	case CONTRACTINIT:
 		return t.ContractInit.Nonce
+	case BAKERREGISTRATION:
+		return t.Registration.Nonce
+	case DELEGATION:
+		return t.Delegation.Nonce
+	default:
+		return 0
 	}
-	return ""
+}
+
+// Sender returns the public key of the account which sent the transaction
+func (t TransData) Sender() PublicKey {
+	switch t.GetType() {
+	case TRANSACTION:
+		return t.Transaction.From
+	case CONTRACTCALL:
+		return t.ContractCall.Caller
+	case CONTRACTINIT:
+		return t.ContractInit.Owner
+	case BAKERREGISTRATION:
+		return t.Registration.Account
+	case DELEGATION:
+		return t.Delegation.Delegator
+	default:
+		return PublicKey{}
+	}
+}
+
+// GetValidUntilSlot returns the last slot the transaction can be included in, or 0 if it never expires
+func (t TransData) GetValidUntilSlot() uint64 {
+	switch t.GetType() {
+	case TRANSACTION:
+		return t.Transaction.ValidUntilSlot
+	case CONTRACTCALL:
+		return t.ContractCall.ValidUntilSlot
+	case CONTRACTINIT:
+		return t.ContractInit.ValidUntilSlot
+	case BAKERREGISTRATION:
+		return t.Registration.ValidUntilSlot
+	case DELEGATION:
+		return t.Delegation.ValidUntilSlot
+	default:
+		return 0
+	}
+}
+
+// Expired reports whether the transaction can no longer be included in a block of the given slot
+func (t TransData) Expired(slot uint64) bool {
+	validUntil := t.GetValidUntilSlot()
+	return validUntil != 0 && slot > validUntil
 }
 
 func (t TransData) Verify() bool {
@@ -152,6 +190,10 @@ func (t TransData) Verify() bool {
 		return t.ContractCall.Verify()
 	case CONTRACTINIT:
 		return t.ContractInit.Verify()
+	case BAKERREGISTRATION:
+		return t.Registration.Verify()
+	case DELEGATION:
+		return t.Delegation.Verify()
 	default:
 		return false
 	}
@@ -164,4 +206,6 @@ const (
 	CONTRACTCALL
 	CONTRACTINIT
 	ERROR
+	BAKERREGISTRATION // after ERROR, as the type is written in the encoding of a TransData
+	DELEGATION
 )
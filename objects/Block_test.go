@@ -3,12 +3,11 @@ package objects
 import (
 	"fmt"
 	. "github.com/nfk93/blockchain/crypto"
-	"math/big"
 	"testing"
 )
 
 func TestVerifyBlock(t *testing.T) {
-	var sk, pk = KeyGen(2048)
+	var sk, pk = KeyGen()
 
 	block := Block{0,
 		"",
@@ -18,6 +17,7 @@ func TestVerifyBlock(t *testing.T) {
 		"",
 		BlockData{},
 		"",
+		"",
 		""}
 
 	block.SignBlock(sk)
@@ -29,8 +29,8 @@ func TestVerifyBlock(t *testing.T) {
 }
 
 func TestVerifyBlockFAIL(t *testing.T) {
-	var _, pk = KeyGen(2048)
-	var sk2, _ = KeyGen(2048)
+	var _, pk = KeyGen()
+	var sk2, _ = KeyGen()
 
 	block := Block{0,
 		"",
@@ -40,6 +40,7 @@ func TestVerifyBlockFAIL(t *testing.T) {
 		"",
 		BlockData{},
 		"",
+		"",
 		""}
 
 	block.SignBlock(sk2)
@@ -50,9 +51,12 @@ func TestVerifyBlockFAIL(t *testing.T) {
 }
 
 func TestBlockNonce(t *testing.T) {
-	sk, pk := KeyGen(2048)
+	sk, pk := KeyGen()
 	leadershipNonce := "011101101"
-	blockNonce := CreateNewBlockNonce(leadershipNonce, sk, 1)
+	blockNonce, err := CreateNewBlockNonce(leadershipNonce, sk, 1)
+	if err != nil {
+		t.Fatalf("Creating the nonce failed: %s", err.Error())
+	}
 	block := Block{1,
 		"",
 		pk,
@@ -61,10 +65,18 @@ func TestBlockNonce(t *testing.T) {
 		"",
 		BlockData{},
 		"",
+		"",
 		""}
 	if !block.ValidateBlockNonce(leadershipNonce) {
 		t.Error("Nonce validation failed")
 	}
+	if block.ValidateBlockNonce("011101100") {
+		t.Error("Should have failed on another leadership nonce")
+	}
+	block.BlockNonce.Nonce = HashSHA(block.BlockNonce.Proof)
+	if block.ValidateBlockNonce(leadershipNonce) {
+		t.Error("Should have failed on a nonce that isn't the VRF output")
+	}
 }
 
 func TestGetType(t *testing.T) {
@@ -83,11 +95,30 @@ func TestGetType(t *testing.T) {
 
 }
 
+func TestTransDataExpired(t *testing.T) {
+	tests := []TransData{
+		{Transaction: Transaction{Amount: 500, ValidUntilSlot: 10}},
+		{ContractCall: ContractCall{Amount: 400, ValidUntilSlot: 10}},
+		{ContractInit: ContractInitialize{Prepaid: 500, ValidUntilSlot: 10}},
+	}
+	for _, td := range tests {
+		if td.Expired(9) || td.Expired(10) {
+			t.Errorf("type %d expired before its last slot", td.GetType())
+		}
+		if !td.Expired(11) {
+			t.Errorf("type %d should have expired after its last slot", td.GetType())
+		}
+	}
+	if (TransData{Transaction: Transaction{Amount: 500}}).Expired(1000000) {
+		t.Error("a transaction without a last slot should never expire")
+	}
+}
+
 func TestTransDataHash(t *testing.T) {
-	publicKey := PublicKey{big.NewInt(5), big.NewInt(3)}
-	trans := Transaction{publicKey, publicKey, 5, "id1", "sign1"}
-	contractCall := ContractCall{"call", "entry", "params", 15, 12, "addr", publicKey, "nonce", "sign2"}
-	contractInit := ContractInitialize{publicKey, []byte("some code!"), 14, 12, 155, "somenonce", "sign3"}
+	_, publicKey := KeyGen()
+	trans := Transaction{publicKey, publicKey.Hash(), 5, 10000, 1, 0, "sign1"}
+	contractCall := ContractCall{"call", "entry", "params", 15, 12, 1, "addr", publicKey, 2, 0, "sign2"}
+	contractInit := ContractInitialize{publicKey, []byte("some code!"), 14, 12, 155, 3, 0, "sign3"}
 	data1 := TransData{Transaction: trans}
 	data2 := TransData{ContractCall: contractCall}
 	data3 := TransData{ContractInit: contractInit}
@@ -98,7 +129,7 @@ func TestTransDataHash(t *testing.T) {
 }
 
 //func TestCreateAndVerifyNonce(t *testing.T) {
-//	var sk, pk = KeyGen(2048)
+//	var sk, pk = KeyGen()
 //
 //	nonce := BlockNonce{"8556", "Something", pk}
 //
@@ -111,8 +142,8 @@ func TestTransDataHash(t *testing.T) {
 //}
 //
 //func TestCreateAndVerifyNonceFAIL(t *testing.T) {
-//	var sk, _ = KeyGen(2048)
-//	var _, pk2 = KeyGen(2048)
+//	var sk, _ = KeyGen()
+//	var _, pk2 = KeyGen()
 //
 //	nonce := BlockNonce{"8556", "Something", pk2}
 //
@@ -125,7 +156,7 @@ func TestTransDataHash(t *testing.T) {
 //}
 
 //func TestVerifyBlock(t *testing.T) {
-//	var sk, pk = KeyGen(2048)
+//	var sk, pk = KeyGen()
 //
 //	slot := 4
 //	hardness := 0.9
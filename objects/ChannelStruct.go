@@ -10,6 +10,11 @@ type ChannelStruct struct {
 	FinalizeToTrans  chan string
 	BlockFromTrans   chan Block
 	TransToTrans     chan CreateBlockData
+	BlockRequest     chan string   // hashes of blocks the consensus layer is missing, for the P2P layer to fetch
+	BlockDropped     chan string   // hashes of blocks the consensus layer dropped, for the P2P layer to accept again
+	PruneToTrans     chan []string // hashes of blocks the transaction layer can forget the states of
+	PrunedFromTrans  chan int      // number of states the transaction layer forgot
+	InvalidFromTrans chan string   // hashes of blocks the transaction layer found to be invalid, for consensus to drop
 }
 
 func CreateChannelStruct() ChannelStruct {
@@ -22,7 +27,13 @@ func CreateChannelStruct() ChannelStruct {
 	blockDataChannel := make(chan CreateBlockData)
 	stringChannel := make(chan string)
 	stateChannel := make(chan State)
+	requestChannel := make(chan string)
+	droppedChannel := make(chan string)
+	pruneChannel := make(chan []string)
+	prunedChannel := make(chan int)
+	invalidChannel := make(chan string)
 	return ChannelStruct{tci, transChannel, blockChannel1,
 		blockChannel2, blockChannel3, stateChannel,
-		stringChannel, blockChannel4, blockDataChannel}
+		stringChannel, blockChannel4, blockDataChannel, requestChannel,
+		droppedChannel, pruneChannel, prunedChannel, invalidChannel}
 }
@@ -1,32 +1,34 @@
 package objects
 
 import (
-	"bytes"
 	. "github.com/nfk93/blockchain/crypto"
-	"strconv"
-	"time"
 )
 
+// ContractCall calls the contract at Address. Every unit of gas costs GasPrice, so a higher price gets the call into a
+// block sooner. ValidUntilSlot is the last slot the call can be included in, as for a Transaction
 type ContractCall struct {
-	Call      string
-	Entry     string
-	Params    string
-	Amount    uint64
-	Gas       uint64
-	Address   string
-	Caller    PublicKey
-	Nonce     string
-	Signature string
+	Call           string
+	Entry          string
+	Params         string
+	Amount         uint64
+	Gas            uint64
+	GasPrice       uint64
+	Address        string
+	Caller         PublicKey
+	Nonce          uint64
+	ValidUntilSlot uint64
+	Signature      string
 }
 
 type ContractInitialize struct {
-	Owner        PublicKey
-	Code         []byte
-	Gas          uint64
-	Prepaid      uint64
-	StorageLimit uint64
-	Nonce        string
-	Signature    string
+	Owner          PublicKey
+	Code           []byte
+	Gas            uint64
+	Prepaid        uint64
+	StorageLimit   uint64
+	Nonce          uint64
+	ValidUntilSlot uint64
+	Signature      string
 }
 
 type Operation interface {
@@ -38,82 +40,44 @@ type Parameter interface {
 type Storage interface {
 }
 
-func (cc ContractCall) toString() string {
-	var buf bytes.Buffer
-	buf.WriteString(cc.Call)
-	buf.WriteString(cc.Entry)
-	buf.WriteString(cc.Params)
-	buf.WriteString(strconv.Itoa(int(cc.Amount)))
-	buf.WriteString(strconv.Itoa(int(cc.Gas)))
-	buf.WriteString(cc.Address)
-	buf.WriteString(cc.Caller.String())
-	buf.WriteString(cc.Nonce)
-	buf.WriteString(cc.Signature)
-	return buf.String()
-}
-
 func (cc ContractCall) stringToSign() string {
-	var buf bytes.Buffer
-	buf.WriteString(cc.Call)
-	buf.WriteString(cc.Entry)
-	buf.WriteString(cc.Params)
-	buf.WriteString(strconv.Itoa(int(cc.Amount)))
-	buf.WriteString(strconv.Itoa(int(cc.Gas)))
-	buf.WriteString(cc.Address)
-	buf.WriteString(cc.Caller.String())
-	buf.WriteString(cc.Nonce)
-	return buf.String()
-}
-
-func (ci ContractInitialize) toString() string {
-	var buf bytes.Buffer
-	buf.WriteString(ci.Owner.String())
-	buf.Write(ci.Code)
-	buf.WriteString(strconv.Itoa(int(ci.Gas)))
-	buf.WriteString(strconv.Itoa(int(ci.Prepaid)))
-	buf.WriteString(strconv.Itoa(int(ci.StorageLimit)))
-	buf.WriteString(ci.Nonce)
-	buf.WriteString(ci.Signature)
-	return buf.String()
+	e := newEncoder()
+	cc.encode(e, false)
+	return e.String()
 }
 
 func (ci ContractInitialize) stringToSign() string {
-	var buf bytes.Buffer
-	buf.WriteString(ci.Owner.String())
-	buf.Write(ci.Code)
-	buf.WriteString(strconv.Itoa(int(ci.Gas)))
-	buf.WriteString(strconv.Itoa(int(ci.Prepaid)))
-	buf.WriteString(strconv.Itoa(int(ci.StorageLimit)))
-	buf.WriteString(ci.Nonce)
-	return buf.String()
+	e := newEncoder()
+	ci.encode(e, false)
+	return e.String()
 }
 
-func (cc *ContractCall) Sign(sk SecretKey) {
+func (cc *ContractCall) Sign(signer Signer) {
 	m := cc.stringToSign()
-	cc.Signature = Sign(m, sk)
+	cc.Signature = signer.Sign(m)
 }
 
 func (cc *ContractCall) Verify() bool {
-	return Verify(cc.stringToSign(), cc.Signature, cc.Caller)
+	return cc.Caller.Verify(cc.stringToSign(), cc.Signature)
 }
 
-func (ci *ContractInitialize) Sign(sk SecretKey) {
+func (ci *ContractInitialize) Sign(signer Signer) {
 	m := ci.stringToSign()
-	ci.Signature = Sign(m, sk)
+	ci.Signature = signer.Sign(m)
 }
 
 func (ci *ContractInitialize) Verify() bool {
-	return Verify(ci.stringToSign(), ci.Signature, ci.Owner)
+	return ci.Owner.Verify(ci.stringToSign(), ci.Signature)
 }
 
-func CreateContractCall(call string, entry string, params string, amount uint64, gas uint64, address string, caller PublicKey, sk SecretKey) ContractCall {
-	cc := ContractCall{call, entry, params, amount, gas, address, caller, caller.Hash()[:10] + "-" + time.Now().String(), ""}
-	cc.Sign(sk)
+func CreateContractCall(call string, entry string, params string, amount uint64, gas uint64, gasPrice uint64, address string, caller PublicKey, nonce uint64, validUntilSlot uint64, signer Signer) ContractCall {
+	cc := ContractCall{call, entry, params, amount, gas, gasPrice, address, caller, nonce, validUntilSlot, ""}
+	cc.Sign(signer)
 	return cc
 }
 
-func CreateContractInit(owner PublicKey, code []byte, gas uint64, prepaid uint64, storageLimit uint64, sk SecretKey) ContractInitialize {
-	ci := ContractInitialize{owner, code, gas, prepaid, storageLimit, owner.Hash()[:10] + "-" + time.Now().String(), ""}
-	ci.Sign(sk)
+func CreateContractInit(owner PublicKey, code []byte, gas uint64, prepaid uint64, storageLimit uint64, nonce uint64, validUntilSlot uint64, signer Signer) ContractInitialize {
+	ci := ContractInitialize{owner, code, gas, prepaid, storageLimit, nonce, validUntilSlot, ""}
+	ci.Sign(signer)
 	return ci
 }
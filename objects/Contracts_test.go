@@ -6,14 +6,14 @@ import (
 )
 
 func TestVerification(t *testing.T) {
-	var sk, pk = KeyGen(2048)
-	cc := CreateContractCall("Flot", "test", "tis", 20, 2, "adresse", pk, sk)
+	var sk, pk = KeyGen()
+	cc := CreateContractCall("Flot", "test", "tis", 20, 2, 1, "adresse", pk, 0, 0, sk)
 	if !cc.Verify() {
 		t.Error("Verification of ContractCall failed")
 	}
-	sk, pk = KeyGen(2048)
+	sk, pk = KeyGen()
 
-	ci := CreateContractInit(pk, []byte("test"), 23, 20, 2, sk)
+	ci := CreateContractInit(pk, []byte("test"), 23, 20, 2, 0, 0, sk)
 	if !ci.Verify() {
 		t.Error("Verification of ContractCall failed")
 	}
@@ -0,0 +1,161 @@
+package objects
+
+import (
+	. "github.com/nfk93/blockchain/crypto"
+	"github.com/pkg/errors"
+	"math/bits"
+	"sort"
+)
+
+// Delegation delegates the stake of the account of Delegator to the account with key hash Baker, so an account that
+// doesn't run a node still takes part in the lottery. The funds stay in the account of the delegator, and the
+// rewards of the blocks baked by Baker are shared with its delegators in proportion to their balances. An empty Baker
+// ends the delegation
+type Delegation struct {
+	Delegator      PublicKey
+	Baker          string
+	Fee            uint64
+	Nonce          uint64
+	ValidUntilSlot uint64
+	Signature      string
+}
+
+func (d Delegation) stringToSign() string {
+	e := newEncoder()
+	d.encode(e, false)
+	return e.String()
+}
+
+func (d *Delegation) Sign(signer Signer) {
+	d.Signature = signer.Sign(d.stringToSign())
+}
+
+func (d *Delegation) Verify() bool {
+	return d.Delegator.Verify(d.stringToSign(), d.Signature)
+}
+
+func CreateDelegation(delegator PublicKey, baker string, fee uint64, nonce uint64, validUntilSlot uint64, signer Signer) Delegation {
+	d := Delegation{delegator, baker, fee, nonce, validUntilSlot, ""}
+	d.Sign(signer)
+	return d
+}
+
+// Delegate records the delegation of d and pays its fee, which has to pay for gas units of gas at MinGasPrice at
+// least. Stake is only delegated one step, so an account with delegators can't delegate, and an account can't
+// delegate to an account that delegates itself.
+// Returns the fee
+func (s *State) Delegate(d Delegation, gas uint64) (uint64, error) {
+	if !d.Verify() {
+		return 0, errors.New("Delegation signature didn't verify!")
+	}
+	delegator := d.Delegator.Hash()
+	if d.Baker != "" {
+		if !isKeyHash(d.Baker) {
+			return 0, errors.New("Delegation baker is not an address!")
+		}
+		if d.Baker == delegator {
+			return 0, errors.New("An account can't delegate to itself!")
+		}
+		if s.DelegatedTo(d.Baker) != "" {
+			return 0, errors.New("The baker delegates its own stake!")
+		}
+		if len(s.Delegators(delegator)) > 0 {
+			return 0, errors.New("An account with delegators can't delegate!")
+		}
+	} else if s.DelegatedTo(delegator) == "" {
+		return 0, errors.New("The account doesn't delegate!")
+	}
+
+	if err := s.useNonce(d.Delegator, d.Nonce); err != nil {
+		return 0, err
+	}
+	if minFee, ok := gasCost(gas, MinGasPrice); !ok || d.Fee < minFee {
+		return 0, errors.New("Delegation fee is below the minimum gas price!")
+	}
+	balance := s.Balance(delegator)
+	if balance < d.Fee {
+		return 0, errors.New("Not enough funds for Delegation!")
+	}
+	s.SetBalance(delegator, balance-d.Fee)
+	s.TotalStake -= d.Fee
+
+	if previous := s.DelegatedTo(delegator); previous != "" {
+		s.setDelegators(previous, without(s.Delegators(previous), delegator))
+	}
+	if d.Baker != "" {
+		s.setDelegators(d.Baker, with(s.Delegators(d.Baker), delegator))
+	}
+	s.Tree = s.Tree.Set(DelegationKey(delegator), []byte(d.Baker))
+	return d.Fee, nil
+}
+
+// DelegatedTo returns the key hash of the baker the account with the given key hash delegates to, or the empty
+// string if it doesn't delegate
+func (s State) DelegatedTo(account string) string {
+	return string(s.Tree.Get(DelegationKey(account)))
+}
+
+// Delegations returns the bakers delegated to, keyed by the key hash of the delegator
+func (s State) Delegations() map[string]string {
+	delegations := make(map[string]string)
+	for _, baker := range decodeStrings(s.Tree.Get(delegatedKey)) {
+		for _, delegator := range s.Delegators(baker) {
+			delegations[delegator] = baker
+		}
+	}
+	return delegations
+}
+
+// Delegators returns the key hashes of the accounts delegating to baker, sorted
+func (s State) Delegators(baker string) []string {
+	return decodeStrings(s.Tree.Get(DelegatorsKey(baker)))
+}
+
+// setDelegators records the delegators of baker, and whether baker is among the bakers that have delegators
+func (s *State) setDelegators(baker string, delegators []string) {
+	bakers := decodeStrings(s.Tree.Get(delegatedKey))
+	if len(delegators) == 0 {
+		bakers = without(bakers, baker)
+	} else {
+		bakers = with(bakers, baker)
+	}
+	s.Tree = s.Tree.Set(DelegatorsKey(baker), encodeStrings(delegators)).Set(delegatedKey, encodeStrings(bakers))
+}
+
+// with returns the sorted list with s added, unless it is in it already
+func with(list []string, s string) []string {
+	i := sort.SearchStrings(list, s)
+	if i < len(list) && list[i] == s {
+		return list
+	}
+	return append(append(append([]string(nil), list[:i]...), s), list[i:]...)
+}
+
+// without returns the sorted list with s removed
+func without(list []string, s string) []string {
+	i := sort.SearchStrings(list, s)
+	if i == len(list) || list[i] != s {
+		return list
+	}
+	return append(append([]string(nil), list[:i]...), list[i+1:]...)
+}
+
+// shareReward pays reward to account and the accounts delegating to it, in proportion to their balances. What is
+// left after rounding the shares down goes to account
+func (s *State) shareReward(account string, reward uint64) {
+	delegators := s.Delegators(account)
+	total := s.Balance(account)
+	for _, delegator := range delegators {
+		total += s.Balance(delegator)
+	}
+	remaining := reward
+	if total > 0 {
+		for _, delegator := range delegators {
+			hi, lo := bits.Mul64(reward, s.Balance(delegator))
+			share, _ := bits.Div64(hi, lo, total)
+			s.addBalance(delegator, share)
+			remaining -= share
+		}
+	}
+	s.addBalance(account, remaining)
+}
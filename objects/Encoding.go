@@ -0,0 +1,761 @@
+package objects
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	. "github.com/nfk93/blockchain/crypto"
+	"github.com/nfk93/blockchain/smart"
+	"github.com/nfk93/blockchain/smart/interpreter/value"
+	"math"
+	"sort"
+	"time"
+)
+
+// EncodingVersion is written as the first byte of every encoded object. It has to be bumped whenever the encoding
+// below changes, since transactions and blocks are signed and hashed over their encoding
+const EncodingVersion = byte(2)
+
+// tags identifying the type of an encoded object. As signatures are made over the encoding, the tag also keeps a
+// signature of one type of object from being passed off as the signature of another. These are part of the
+// encoding, so never renumber them
+const (
+	tagTransaction byte = iota + 1
+	tagContractCall
+	tagContractInit
+	tagTransData
+	tagBlockNonce
+	tagBlockData
+	tagBlock
+	tagGenesisData
+	tagState
+	tagNonceMessage
+	tagPartialTransaction
+	tagBakerRegistration
+	tagBakingConsent
+	tagDelegation
+	tagFinalRecord
+)
+
+// The canonical encoding of an object is EncodingVersion followed by its tag and its fields in the order they are
+// declared. Integers are written as 8 byte big endian, strings and lists are prefixed by their length as a uvarint,
+// and nested objects are written with their tag but without the version. Signatures are made over the encoding of
+// an object without its signature, which is always the last field.
+//
+// A block is signed and hashed without its block data, as the Merkle root in the block commits to the transactions.
+
+// Encode returns the canonical encoding of t
+func (t Transaction) Encode() []byte {
+	e := newEncoder()
+	t.encode(e, true)
+	return e.Bytes()
+}
+
+// DecodeTransaction is the inverse of Transaction.Encode
+func DecodeTransaction(data []byte) (Transaction, error) {
+	var t Transaction
+	d := newDecoder(data)
+	t.decode(d)
+	return t, d.finish()
+}
+
+// Encode returns the canonical encoding of cc
+func (cc ContractCall) Encode() []byte {
+	e := newEncoder()
+	cc.encode(e, true)
+	return e.Bytes()
+}
+
+// DecodeContractCall is the inverse of ContractCall.Encode
+func DecodeContractCall(data []byte) (ContractCall, error) {
+	var cc ContractCall
+	d := newDecoder(data)
+	cc.decode(d)
+	return cc, d.finish()
+}
+
+// Encode returns the canonical encoding of ci
+func (ci ContractInitialize) Encode() []byte {
+	e := newEncoder()
+	ci.encode(e, true)
+	return e.Bytes()
+}
+
+// DecodeContractInitialize is the inverse of ContractInitialize.Encode
+func DecodeContractInitialize(data []byte) (ContractInitialize, error) {
+	var ci ContractInitialize
+	d := newDecoder(data)
+	ci.decode(d)
+	return ci, d.finish()
+}
+
+// Encode returns the canonical encoding of t. Only the transaction given by t.GetType is encoded
+func (t TransData) Encode() []byte {
+	e := newEncoder()
+	t.encode(e)
+	return e.Bytes()
+}
+
+// DecodeTransData is the inverse of TransData.Encode
+func DecodeTransData(data []byte) (TransData, error) {
+	var t TransData
+	d := newDecoder(data)
+	t.decode(d)
+	return t, d.finish()
+}
+
+// Encode returns the canonical encoding of n
+func (n BlockNonce) Encode() []byte {
+	e := newEncoder()
+	n.encode(e)
+	return e.Bytes()
+}
+
+// DecodeBlockNonce is the inverse of BlockNonce.Encode
+func DecodeBlockNonce(data []byte) (BlockNonce, error) {
+	var n BlockNonce
+	d := newDecoder(data)
+	n.decode(d)
+	return n, d.finish()
+}
+
+// Encode returns the canonical encoding of bd
+func (bd BlockData) Encode() []byte {
+	e := newEncoder()
+	bd.encode(e)
+	return e.Bytes()
+}
+
+// DecodeBlockData is the inverse of BlockData.Encode
+func DecodeBlockData(data []byte) (BlockData, error) {
+	var bd BlockData
+	d := newDecoder(data)
+	bd.decode(d)
+	return bd, d.finish()
+}
+
+// Encode returns the canonical encoding of b
+func (b Block) Encode() []byte {
+	e := newEncoder()
+	b.encode(e, true)
+	return e.Bytes()
+}
+
+// DecodeBlock is the inverse of Block.Encode
+func DecodeBlock(data []byte) (Block, error) {
+	var b Block
+	d := newDecoder(data)
+	b.decode(d)
+	return b, d.finish()
+}
+
+// Encode returns the canonical encoding of g. The genesis time is encoded in UTC
+func (g GenesisData) Encode() []byte {
+	e := newEncoder()
+	g.encode(e)
+	return e.Bytes()
+}
+
+// DecodeGenesisData is the inverse of GenesisData.Encode
+func DecodeGenesisData(data []byte) (GenesisData, error) {
+	var g GenesisData
+	d := newDecoder(data)
+	g.decode(d)
+	return g, d.finish()
+}
+
+// Encode returns the canonical encoding of s. The entries of the state tree are sorted by key
+func (s State) Encode() []byte {
+	e := newEncoder()
+	s.encode(e)
+	return e.Bytes()
+}
+
+// DecodeState is the inverse of State.Encode
+func DecodeState(data []byte) (State, error) {
+	var s State
+	d := newDecoder(data)
+	s.decode(d)
+	return s, d.finish()
+}
+
+// Encode returns the canonical encoding of r, which is how it is stored
+func (r FinalRecord) Encode() []byte {
+	e := newEncoder()
+	r.encode(e)
+	return e.Bytes()
+}
+
+// DecodeFinalRecord is the inverse of FinalRecord.Encode
+func DecodeFinalRecord(data []byte) (FinalRecord, error) {
+	var r FinalRecord
+	d := newDecoder(data)
+	r.decode(d)
+	return r, d.finish()
+}
+
+// Encode returns the canonical encoding of p, which is how partial signatures are passed between signers
+func (p PartialTransaction) Encode() []byte {
+	e := newEncoder()
+	p.encode(e)
+	return e.Bytes()
+}
+
+// DecodePartialTransaction is the inverse of PartialTransaction.Encode
+func DecodePartialTransaction(data []byte) (PartialTransaction, error) {
+	var p PartialTransaction
+	d := newDecoder(data)
+	p.decode(d)
+	return p, d.finish()
+}
+
+func (t Transaction) encode(e *encoder, signed bool) {
+	e.writeByte(tagTransaction)
+	e.writeKey(t.From)
+	e.writeString(t.To)
+	e.writeUint(t.Amount)
+	e.writeUint(t.Fee)
+	e.writeUint(t.Nonce)
+	e.writeUint(t.ValidUntilSlot)
+	if signed {
+		e.writeString(t.Signature)
+	}
+}
+
+func (t *Transaction) decode(d *decoder) {
+	d.expectTag(tagTransaction)
+	t.From = d.readKey()
+	t.To = d.readString()
+	t.Amount = d.readUint()
+	t.Fee = d.readUint()
+	t.Nonce = d.readUint()
+	t.ValidUntilSlot = d.readUint()
+	t.Signature = d.readString()
+}
+
+func (p PartialTransaction) encode(e *encoder) {
+	e.writeByte(tagPartialTransaction)
+	p.Transaction.encode(e, true)
+	e.writeLength(len(p.Signatures))
+	for _, sig := range p.Signatures {
+		e.writeString(sig)
+	}
+}
+
+func (p *PartialTransaction) decode(d *decoder) {
+	d.expectTag(tagPartialTransaction)
+	p.Transaction.decode(d)
+	n := d.readLength()
+	for i := 0; i < n && d.err == nil; i++ {
+		p.Signatures = append(p.Signatures, d.readString())
+	}
+}
+
+func (cc ContractCall) encode(e *encoder, signed bool) {
+	e.writeByte(tagContractCall)
+	e.writeString(cc.Call)
+	e.writeString(cc.Entry)
+	e.writeString(cc.Params)
+	e.writeUint(cc.Amount)
+	e.writeUint(cc.Gas)
+	e.writeUint(cc.GasPrice)
+	e.writeString(cc.Address)
+	e.writeKey(cc.Caller)
+	e.writeUint(cc.Nonce)
+	e.writeUint(cc.ValidUntilSlot)
+	if signed {
+		e.writeString(cc.Signature)
+	}
+}
+
+func (cc *ContractCall) decode(d *decoder) {
+	d.expectTag(tagContractCall)
+	cc.Call = d.readString()
+	cc.Entry = d.readString()
+	cc.Params = d.readString()
+	cc.Amount = d.readUint()
+	cc.Gas = d.readUint()
+	cc.GasPrice = d.readUint()
+	cc.Address = d.readString()
+	cc.Caller = d.readKey()
+	cc.Nonce = d.readUint()
+	cc.ValidUntilSlot = d.readUint()
+	cc.Signature = d.readString()
+}
+
+func (ci ContractInitialize) encode(e *encoder, signed bool) {
+	e.writeByte(tagContractInit)
+	e.writeKey(ci.Owner)
+	e.writeBytes(ci.Code)
+	e.writeUint(ci.Gas)
+	e.writeUint(ci.Prepaid)
+	e.writeUint(ci.StorageLimit)
+	e.writeUint(ci.Nonce)
+	e.writeUint(ci.ValidUntilSlot)
+	if signed {
+		e.writeString(ci.Signature)
+	}
+}
+
+func (ci *ContractInitialize) decode(d *decoder) {
+	d.expectTag(tagContractInit)
+	ci.Owner = d.readKey()
+	ci.Code = d.readBytes()
+	ci.Gas = d.readUint()
+	ci.Prepaid = d.readUint()
+	ci.StorageLimit = d.readUint()
+	ci.Nonce = d.readUint()
+	ci.ValidUntilSlot = d.readUint()
+	ci.Signature = d.readString()
+}
+
+func (r BakerRegistration) encode(e *encoder, signed bool) {
+	e.writeByte(tagBakerRegistration)
+	e.writeKey(r.Account)
+	e.writeKey(r.BakingKey)
+	e.writeUint(r.Fee)
+	e.writeUint(r.Nonce)
+	e.writeUint(r.ValidUntilSlot)
+	e.writeString(r.Consent)
+	if signed {
+		e.writeString(r.Signature)
+	}
+}
+
+func (r *BakerRegistration) decode(d *decoder) {
+	d.expectTag(tagBakerRegistration)
+	r.Account = d.readKey()
+	r.BakingKey = d.readKey()
+	r.Fee = d.readUint()
+	r.Nonce = d.readUint()
+	r.ValidUntilSlot = d.readUint()
+	r.Consent = d.readString()
+	r.Signature = d.readString()
+}
+
+func (d Delegation) encode(e *encoder, signed bool) {
+	e.writeByte(tagDelegation)
+	e.writeKey(d.Delegator)
+	e.writeString(d.Baker)
+	e.writeUint(d.Fee)
+	e.writeUint(d.Nonce)
+	e.writeUint(d.ValidUntilSlot)
+	if signed {
+		e.writeString(d.Signature)
+	}
+}
+
+func (d *Delegation) decode(dec *decoder) {
+	dec.expectTag(tagDelegation)
+	d.Delegator = dec.readKey()
+	d.Baker = dec.readString()
+	d.Fee = dec.readUint()
+	d.Nonce = dec.readUint()
+	d.ValidUntilSlot = dec.readUint()
+	d.Signature = dec.readString()
+}
+
+func (r FinalRecord) encode(e *encoder) {
+	e.writeByte(tagFinalRecord)
+	e.writeString(r.LeadershipNonce)
+	e.writeString(r.BlockHash)
+	e.writeString(r.StateHash)
+}
+
+func (r *FinalRecord) decode(d *decoder) {
+	d.expectTag(tagFinalRecord)
+	r.LeadershipNonce = d.readString()
+	r.BlockHash = d.readString()
+	r.StateHash = d.readString()
+}
+
+// consentMessage returns the message signed by a baking key to consent to bake for account, until validUntilSlot
+func consentMessage(account PublicKey, validUntilSlot uint64) string {
+	e := newEncoder()
+	e.writeByte(tagBakingConsent)
+	e.writeKey(account)
+	e.writeUint(validUntilSlot)
+	return e.String()
+}
+
+func (t TransData) encode(e *encoder) {
+	e.writeByte(tagTransData)
+	kind := t.GetType()
+	e.writeByte(byte(kind))
+	switch kind {
+	case TRANSACTION:
+		t.Transaction.encode(e, true)
+	case CONTRACTCALL:
+		t.ContractCall.encode(e, true)
+	case CONTRACTINIT:
+		t.ContractInit.encode(e, true)
+	case BAKERREGISTRATION:
+		t.Registration.encode(e, true)
+	case DELEGATION:
+		t.Delegation.encode(e, true)
+	}
+}
+
+func (t *TransData) decode(d *decoder) {
+	d.expectTag(tagTransData)
+	switch kind := d.readByte(); kind {
+	case TRANSACTION:
+		t.Transaction.decode(d)
+	case CONTRACTCALL:
+		t.ContractCall.decode(d)
+	case CONTRACTINIT:
+		t.ContractInit.decode(d)
+	case BAKERREGISTRATION:
+		t.Registration.decode(d)
+	case DELEGATION:
+		t.Delegation.decode(d)
+	case ERROR:
+	default:
+		d.fail(fmt.Errorf("unknown transaction type %d", kind))
+	}
+}
+
+func (n BlockNonce) encode(e *encoder) {
+	e.writeByte(tagBlockNonce)
+	e.writeString(n.Nonce)
+	e.writeString(n.Proof)
+}
+
+func (n *BlockNonce) decode(d *decoder) {
+	d.expectTag(tagBlockNonce)
+	n.Nonce = d.readString()
+	n.Proof = d.readString()
+}
+
+// nonceMessage returns the message signed by the baker of slot to draw its block nonce
+func nonceMessage(leadershipNonce string, slot uint64) string {
+	e := newEncoder()
+	e.writeByte(tagNonceMessage)
+	e.writeString(leadershipNonce)
+	e.writeUint(slot)
+	return e.String()
+}
+
+func (bd BlockData) encode(e *encoder) {
+	e.writeByte(tagBlockData)
+	e.writeLength(len(bd.Trans))
+	for _, t := range bd.Trans {
+		t.encode(e)
+	}
+	bd.GenesisData.encode(e)
+}
+
+func (bd *BlockData) decode(d *decoder) {
+	d.expectTag(tagBlockData)
+	n := d.readLength()
+	for i := 0; i < n && d.err == nil; i++ {
+		var t TransData
+		t.decode(d)
+		bd.Trans = append(bd.Trans, t)
+	}
+	bd.GenesisData.decode(d)
+}
+
+// encode writes the block. Without signed, the block data and the signature are left out, which is the message
+// signed by the baker
+func (b Block) encode(e *encoder, signed bool) {
+	e.writeByte(tagBlock)
+	e.writeUint(b.Slot)
+	e.writeString(b.ParentPointer)
+	e.writeKey(b.BakerID)
+	e.writeString(b.Draw)
+	b.BlockNonce.encode(e)
+	e.writeString(b.LastFinalized)
+	e.writeString(b.MerkleRoot)
+	e.writeString(b.StateRoot)
+	if signed {
+		b.BlockData.encode(e)
+		e.writeString(b.BlockSignature)
+	}
+}
+
+func (b *Block) decode(d *decoder) {
+	d.expectTag(tagBlock)
+	b.Slot = d.readUint()
+	b.ParentPointer = d.readString()
+	b.BakerID = d.readKey()
+	b.Draw = d.readString()
+	b.BlockNonce.decode(d)
+	b.LastFinalized = d.readString()
+	b.MerkleRoot = d.readString()
+	b.StateRoot = d.readString()
+	b.BlockData.decode(d)
+	b.BlockSignature = d.readString()
+}
+
+func (g GenesisData) encode(e *encoder) {
+	e.writeByte(tagGenesisData)
+	e.writeUint(uint64(g.GenesisTime.Unix()))
+	e.writeUint(uint64(g.GenesisTime.Nanosecond()))
+	e.writeUint(uint64(g.SlotDuration))
+	e.writeString(g.Nonce)
+	e.writeUint(math.Float64bits(g.Hardness))
+	g.InitialState.encode(e)
+	e.writeUint(g.FinalizeGap)
+	e.writeUint(g.EpochLength)
+}
+
+func (g *GenesisData) decode(d *decoder) {
+	d.expectTag(tagGenesisData)
+	sec, nsec := int64(d.readUint()), int64(d.readUint())
+	g.GenesisTime = time.Unix(sec, nsec).UTC()
+	g.SlotDuration = time.Duration(d.readUint())
+	g.Nonce = d.readString()
+	g.Hardness = math.Float64frombits(d.readUint())
+	g.InitialState.decode(d)
+	g.FinalizeGap = d.readUint()
+	g.EpochLength = d.readUint()
+}
+
+func (s State) encode(e *encoder) {
+	e.writeByte(tagState)
+	entries := make(map[string][]byte)
+	s.Tree.Iterate(func(key string, value []byte) {
+		entries[key] = value
+	})
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	e.writeLength(len(keys))
+	for _, k := range keys {
+		e.writeString(k)
+		e.writeBytes(entries[k])
+	}
+	e.writeString(s.ParentHash)
+	e.writeUint(s.TotalStake)
+	encodeScheduledCalls(e, s.Scheduled)
+}
+
+func (s *State) decode(d *decoder) {
+	d.expectTag(tagState)
+	n := d.readLength()
+	previous := ""
+	for i := 0; i < n && d.err == nil; i++ {
+		key := d.readString()
+		value := d.readBytes()
+		if i > 0 && key <= previous {
+			d.fail(fmt.Errorf("state entries aren't in canonical order"))
+		}
+		previous = key
+		s.Tree = s.Tree.Set(key, value)
+	}
+	s.ParentHash = d.readString()
+	s.TotalStake = d.readUint()
+	s.Scheduled = decodeScheduledCalls(d)
+}
+
+// encodeScheduledCalls writes the calls in the order they were scheduled. The parameters are written with
+// value.Encode, or as no bytes if there are none
+func encodeScheduledCalls(e *encoder, scheduled []smart.ScheduledCall) {
+	e.writeLength(len(scheduled))
+	for _, sc := range scheduled {
+		var params []byte
+		if sc.Params != nil {
+			var err error
+			if params, err = value.Encode(sc.Params); err != nil {
+				// parameters are always the result of interpreting a contract, so they can always be encoded
+				panic(fmt.Sprintf("can't encode parameters of call scheduled to %s: %s", sc.Address, err.Error()))
+			}
+		}
+		e.writeUint(sc.Slot)
+		e.writeString(sc.Scheduler)
+		e.writeString(sc.Address)
+		e.writeString(sc.Entry)
+		e.writeBytes(params)
+		e.writeUint(sc.Deposit)
+	}
+}
+
+func decodeScheduledCalls(d *decoder) []smart.ScheduledCall {
+	var scheduled []smart.ScheduledCall
+	n := d.readLength()
+	for i := 0; i < n && d.err == nil; i++ {
+		var sc smart.ScheduledCall
+		sc.Slot = d.readUint()
+		sc.Scheduler = d.readString()
+		sc.Address = d.readString()
+		sc.Entry = d.readString()
+		if params := d.readBytes(); params != nil {
+			v, err := value.Decode(params)
+			if err != nil {
+				d.fail(err)
+			}
+			sc.Params = v
+		}
+		sc.Deposit = d.readUint()
+		scheduled = append(scheduled, sc)
+	}
+	return scheduled
+}
+
+// encodeStrings writes a list of strings, such as the delegators of a baker. An empty list is nil, so it isn't kept in
+// the state tree
+func encodeStrings(list []string) []byte {
+	if len(list) == 0 {
+		return nil
+	}
+	e := newEncoder()
+	e.writeLength(len(list))
+	for _, s := range list {
+		e.writeString(s)
+	}
+	return e.Bytes()
+}
+
+func decodeStrings(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	d := newDecoder(data)
+	n := d.readLength()
+	list := make([]string, 0, n)
+	for i := 0; i < n && d.err == nil; i++ {
+		list = append(list, d.readString())
+	}
+	if d.finish() != nil {
+		return nil
+	}
+	return list
+}
+
+type encoder struct {
+	bytes.Buffer
+}
+
+func newEncoder() *encoder {
+	e := &encoder{}
+	e.WriteByte(EncodingVersion)
+	return e
+}
+
+func (e *encoder) writeByte(b byte) {
+	e.WriteByte(b)
+}
+
+func (e *encoder) writeUint(i uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], i)
+	e.Write(b[:])
+}
+
+func (e *encoder) writeLength(n int) {
+	var b [binary.MaxVarintLen64]byte
+	e.Write(b[:binary.PutUvarint(b[:], uint64(n))])
+}
+
+func (e *encoder) writeBytes(data []byte) {
+	e.writeLength(len(data))
+	e.Write(data)
+}
+
+func (e *encoder) writeString(s string) {
+	e.writeLength(len(s))
+	e.WriteString(s)
+}
+
+func (e *encoder) writeKey(pk PublicKey) {
+	e.writeString(string(pk.Scheme))
+	e.writeString(pk.Key)
+}
+
+// decoder reads an encoded object. The first error is kept and every read after it returns a zero value, so an
+// object can be decoded field by field and the error checked at the end
+type decoder struct {
+	r   *bytes.Reader
+	err error
+}
+
+func newDecoder(data []byte) *decoder {
+	d := &decoder{r: bytes.NewReader(data)}
+	if version := d.readByte(); d.err == nil && version != EncodingVersion {
+		d.fail(fmt.Errorf("unsupported encoding version %d", version))
+	}
+	return d
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// finish returns the first error, or an error if not all of the input was read
+func (d *decoder) finish() error {
+	if d.err == nil && d.r.Len() != 0 {
+		d.fail(fmt.Errorf("%d trailing bytes after encoded object", d.r.Len()))
+	}
+	return d.err
+}
+
+func (d *decoder) readByte() byte {
+	if d.err != nil {
+		return 0
+	}
+	b, err := d.r.ReadByte()
+	if err != nil {
+		d.fail(fmt.Errorf("unexpected end of encoded object"))
+	}
+	return b
+}
+
+func (d *decoder) expectTag(tag byte) {
+	if actual := d.readByte(); d.err == nil && actual != tag {
+		d.fail(fmt.Errorf("expected object with tag %d, found tag %d", tag, actual))
+	}
+}
+
+func (d *decoder) readUint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	var b [8]byte
+	if n, _ := d.r.Read(b[:]); n != 8 {
+		d.fail(fmt.Errorf("unexpected end of encoded object"))
+		return 0
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// readLength reads a length prefix, rejecting lengths that can't possibly fit in the remaining input
+func (d *decoder) readLength() int {
+	if d.err != nil {
+		return 0
+	}
+	n, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		d.fail(fmt.Errorf("invalid length prefix"))
+		return 0
+	}
+	if n > uint64(d.r.Len()) {
+		d.fail(fmt.Errorf("length prefix %d exceeds remaining input", n))
+		return 0
+	}
+	return int(n)
+}
+
+// readBytes reads a length prefixed byte string. An empty string is read as nil
+func (d *decoder) readBytes() []byte {
+	n := d.readLength()
+	if d.err != nil || n == 0 {
+		return nil
+	}
+	b := make([]byte, n)
+	d.r.Read(b)
+	return b
+}
+
+func (d *decoder) readString() string {
+	return string(d.readBytes())
+}
+
+func (d *decoder) readKey() PublicKey {
+	scheme := d.readString()
+	return PublicKey{Scheme: Scheme(scheme), Key: d.readString()}
+}
@@ -0,0 +1,177 @@
+package objects
+
+import (
+	"bytes"
+	"encoding/hex"
+	. "github.com/nfk93/blockchain/crypto"
+	"github.com/nfk93/blockchain/smart"
+	"github.com/nfk93/blockchain/smart/interpreter/value"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var goldenKey = PublicKey{Scheme: Ed25519, Key: "\x01\x02"}
+
+var goldenTransaction = Transaction{goldenKey, "to", 5, 1000, 2, 9, "sig"}
+
+var goldenCall = ContractCall{"call", "main", "()", 5, 100, 2, "kn2", goldenKey, 3, 0, "sig"}
+
+var goldenInit = ContractInitialize{goldenKey, []byte("code"), 100, 50, 10, 4, 12, "sig"}
+
+var goldenBlock = Block{7, "parent", goldenKey, "draw", BlockNonce{"n", "p"}, "final",
+	BlockData{Trans: []TransData{{Transaction: goldenTransaction}}}, "merkle", "state", "sig"}
+
+func TestEncodeGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded []byte
+		golden  string
+	}{
+		{"transaction", goldenTransaction.Encode(),
+			"0201076564323535313902010202746f000000000000000500000000000003e80000000000000002" +
+				"0000000000000009" +
+				"03736967"},
+		{"contract call", goldenCall.Encode(),
+			"02020463616c6c046d61696e0228290000000000000005000000000000006400000000000000020" +
+				"36b6e3207656432353531390201020000000000000003" +
+				"0000000000000000" +
+				"03736967"},
+		{"contract init", goldenInit.Encode(),
+			"0203076564323535313902010204636f6465000000000000006400000000000000320000000000" +
+				"00000a0000000000000004" +
+				"000000000000000c" +
+				"03736967"},
+		{"empty transdata", TransData{}.Encode(), "020403"},
+		{"block nonce", BlockNonce{"n", "p"}.Encode(), "0205016e0170"},
+		{"block", goldenBlock.Encode(),
+			"0207000000000000000706706172656e740765643235353139020102046472617705016e0170056669" +
+				"6e616c066d65726b6c6505737461746506010400" +
+				"01076564323535313902010202746f000000000000000500000000000003e80000000000000002" +
+				"0000000000000009" +
+				"03736967" +
+				"08fffffff1886e090000000000000000000000000000000000000000000000000000" +
+				"090000000000000000000000" +
+				"00000000000000000000000000000000" +
+				"03736967"},
+	}
+	for _, test := range tests {
+		if hex.EncodeToString(test.encoded) != test.golden {
+			t.Errorf("%s encoded to %x, expected %s", test.name, test.encoded, test.golden)
+		}
+	}
+}
+
+func TestHashGolden(t *testing.T) {
+	if hash := (TransData{Transaction: goldenTransaction}).Hash(); hash != "0704a9a941381261d2fe8ef48e4f4f6d659d842f39b4defdbe440fd803235ba5" {
+		t.Errorf("transaction hashed to %s", hash)
+	}
+	if hash := goldenBlock.CalculateBlockHash(); hash != "ffbf89efc4bbae12afae118e58582ebcef9a126edce3c3fbaed70bd7f9bc1483" {
+		t.Errorf("block hashed to %s", hash)
+	}
+}
+
+func TestEncodeRoundtrip(t *testing.T) {
+	var state State
+	state.SetBalance(goldenKey.Hash(), 100)
+	state.Tree = state.Tree.Set(StorageKey("kn2"), []byte("storage"))
+	state.ParentHash = "parent"
+	state.TotalStake = 100
+	state.Scheduled = []smart.ScheduledCall{
+		{Slot: 10, Scheduler: "kn2a", Address: "kn2b", Entry: "main",
+			Params: value.TupleVal{Values: []value.Value{value.NatVal{Value: 1}}}, Deposit: 5},
+		{Slot: 11, Scheduler: "kn2a", Address: "kn2b", Entry: "main"},
+	}
+	genesis := GenesisData{time.Unix(1500000000, 42).UTC(), time.Second, "nonce", 0.5, state, 10, 100}
+	block := goldenBlock
+	registration := BakerRegistration{goldenKey, PublicKey{Scheme: Ed25519, Key: "\x03"}, 10000, 5, 0, "consent", "sig"}
+	delegation := Delegation{goldenKey, "baker", 10000, 6, 0, "sig"}
+	block.BlockData = BlockData{[]TransData{{Transaction: goldenTransaction}, {ContractCall: goldenCall},
+		{ContractInit: goldenInit}, {Registration: registration}, {Delegation: delegation}}, genesis}
+
+	check := func(name string, original interface{}, decoded interface{}, err error) {
+		if err != nil {
+			t.Errorf("error decoding %s: %s", name, err.Error())
+		} else if !reflect.DeepEqual(original, decoded) {
+			t.Errorf("%s decoded to %v, expected %v", name, decoded, original)
+		}
+	}
+	transaction, err := DecodeTransaction(goldenTransaction.Encode())
+	check("transaction", goldenTransaction, transaction, err)
+	call, err := DecodeContractCall(goldenCall.Encode())
+	check("contract call", goldenCall, call, err)
+	init, err := DecodeContractInitialize(goldenInit.Encode())
+	check("contract init", goldenInit, init, err)
+	transData, err := DecodeTransData(TransData{}.Encode())
+	check("empty transdata", TransData{}, transData, err)
+	decodedRegistration, err := DecodeTransData(TransData{Registration: registration}.Encode())
+	check("baker registration", TransData{Registration: registration}, decodedRegistration, err)
+	decodedDelegation, err := DecodeTransData(TransData{Delegation: delegation}.Encode())
+	check("delegation", TransData{Delegation: delegation}, decodedDelegation, err)
+	nonce, err := DecodeBlockNonce(goldenBlock.BlockNonce.Encode())
+	check("block nonce", goldenBlock.BlockNonce, nonce, err)
+	partial := PartialTransaction{goldenTransaction, []string{"sig1", "sig2"}}
+	decodedPartial, err := DecodePartialTransaction(partial.Encode())
+	check("partial transaction", partial, decodedPartial, err)
+	record := FinalRecord{"nonce", "block", "state"}
+	decodedRecord, err := DecodeFinalRecord(record.Encode())
+	check("final record", record, decodedRecord, err)
+	data, err := DecodeBlockData(block.BlockData.Encode())
+	check("block data", block.BlockData.Encode(), data.Encode(), err)
+	decodedGenesis, err := DecodeGenesisData(genesis.Encode())
+	check("genesis data", genesis.Encode(), decodedGenesis.Encode(), err)
+	decodedState, err := DecodeState(state.Encode())
+	check("state", state.Encode(), decodedState.Encode(), err)
+	if decodedState.Root() != state.Root() {
+		t.Error("decoded state should have the same root")
+	}
+	decodedBlock, err := DecodeBlock(block.Encode())
+	check("block", block.Encode(), decodedBlock.Encode(), err)
+	if !decodedBlock.BlockData.GenesisData.GenesisTime.Equal(genesis.GenesisTime) {
+		t.Error("decoded block should have the same genesis time")
+	}
+}
+
+func TestDecodeFAIL(t *testing.T) {
+	encoded := goldenTransaction.Encode()
+	wrongVersion := append([]byte{EncodingVersion + 1}, encoded[1:]...)
+	tests := map[string][]byte{
+		"empty input":      {},
+		"wrong version":    wrongVersion,
+		"truncated":        encoded[:len(encoded)-1],
+		"trailing bytes":   append(append([]byte{}, encoded...), 0),
+		"other type":       goldenCall.Encode(),
+		"too long a field": {EncodingVersion, tagTransaction, 0xff, 0x01},
+	}
+	for name, data := range tests {
+		if _, err := DecodeTransaction(data); err == nil {
+			t.Errorf("%s: should have failed", name)
+		}
+	}
+
+	var state State
+	state.Tree = state.Tree.Set("a", []byte("1")).Set("b", []byte("2"))
+	unsorted := bytes.Replace(state.Encode(), []byte("\x01a\x011\x01b\x012"), []byte("\x01b\x012\x01a\x011"), 1)
+	if _, err := DecodeState(unsorted); err == nil {
+		t.Error("Should have failed on state entries out of order")
+	}
+}
+
+func TestSignatureCoversEncoding(t *testing.T) {
+	sk, pk := KeyGen()
+	// these would have had the same signature when fields were concatenated without delimiters
+	t1 := CreateTransaction(pk, "ab", 12, 3000, 0, 0, sk)
+	t2 := t1
+	t2.To, t2.Amount = "ab1", 2
+	if !t1.VerifyTransaction() {
+		t.Error("Transaction should verify")
+	}
+	if t2.VerifyTransaction() {
+		t.Error("Signature should not verify for a transaction with different fields")
+	}
+	t3 := t1
+	t3.ValidUntilSlot = 100
+	if t3.VerifyTransaction() {
+		t.Error("Signature should not verify for a transaction with a different last slot")
+	}
+}
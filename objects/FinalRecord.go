@@ -0,0 +1,9 @@
+package objects
+
+// FinalRecord is what a node stores of the finalized data of an epoch. The rest is computed from the state after
+// StateHash, the block finalized in the epoch before, as it was when the epoch was finalized
+type FinalRecord struct {
+	LeadershipNonce string
+	BlockHash       string
+	StateHash       string
+}
@@ -43,5 +43,6 @@ func CreateTestGenesis(pk crypto.PublicKey) Block {
 		"",
 		BlockData{[]TransData{}, data},
 		"",
+		"",
 		""}
 }
@@ -0,0 +1,98 @@
+package objects
+
+import (
+	. "github.com/nfk93/blockchain/crypto"
+	"github.com/pkg/errors"
+)
+
+// The transactions of a block are the leaves of a Merkle tree, whose root is signed by the baker. A transaction can
+// then be proven to be in a block with the hashes on the path from it to the root, without the rest of the block.
+// Leaves and inner nodes are hashed with different prefixes, so an inner node can't be passed off as a transaction.
+// A node without a sibling is moved up a level unchanged.
+const (
+	merkleLeafPrefix = "\x00"
+	merkleNodePrefix = "\x01"
+)
+
+// MerkleStep is a sibling on the path from a transaction to the Merkle root
+type MerkleStep struct {
+	Hash string
+	Left bool // whether the sibling is the left child of their parent
+}
+
+// MerkleProof proves that a transaction is in a block. The steps go from the transaction up to the root
+type MerkleProof struct {
+	Steps []MerkleStep
+}
+
+// MerkleRoot returns the root of the Merkle tree over the hashes of the transactions in d
+func (d BlockData) MerkleRoot() string {
+	level := d.merkleLeaves()
+	if len(level) == 0 {
+		return HashSHA("")
+	}
+	for len(level) > 1 {
+		level = nextMerkleLevel(level)
+	}
+	return level[0]
+}
+
+// MerkleProof returns the proof that the transaction with the given hash is in d
+func (d BlockData) MerkleProof(transHash string) (MerkleProof, error) {
+	index := -1
+	for i, td := range d.Trans {
+		if td.Hash() == transHash {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return MerkleProof{}, errors.Errorf("transaction %s is not in the block", transHash)
+	}
+
+	var proof MerkleProof
+	level := d.merkleLeaves()
+	for len(level) > 1 {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof.Steps = append(proof.Steps, MerkleStep{level[sibling], sibling < index})
+		}
+		level = nextMerkleLevel(level)
+		index /= 2
+	}
+	return proof, nil
+}
+
+// VerifyMerkleProof returns whether proof proves that the transaction with the given hash is in the tree with the
+// given root
+func VerifyMerkleProof(root string, transHash string, proof MerkleProof) bool {
+	hash := HashSHA(merkleLeafPrefix + transHash)
+	for _, step := range proof.Steps {
+		if step.Left {
+			hash = HashSHA(merkleNodePrefix + step.Hash + hash)
+		} else {
+			hash = HashSHA(merkleNodePrefix + hash + step.Hash)
+		}
+	}
+	return hash == root
+}
+
+func (d BlockData) merkleLeaves() []string {
+	leaves := make([]string, len(d.Trans))
+	for i, td := range d.Trans {
+		leaves[i] = HashSHA(merkleLeafPrefix + td.Hash())
+	}
+	return leaves
+}
+
+func nextMerkleLevel(level []string) []string {
+	next := make([]string, 0, (len(level)+1)/2)
+	for i := 0; i < len(level); i += 2 {
+		if i+1 < len(level) {
+			next = append(next, HashSHA(merkleNodePrefix+level[i]+level[i+1]))
+		} else {
+			next = append(next, level[i])
+		}
+	}
+	return next
+}
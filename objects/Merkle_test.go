@@ -0,0 +1,62 @@
+package objects
+
+import (
+	. "github.com/nfk93/blockchain/crypto"
+	"testing"
+)
+
+func testBlockData(n int) BlockData {
+	sk, pk := KeyGen()
+	var trans []TransData
+	for i := 0; i < n; i++ {
+		trans = append(trans, TransData{Transaction: CreateTransaction(pk, pk.Hash(), uint64(i), 10000, uint64(i), 0, sk)})
+	}
+	return BlockData{trans, GenesisData{}}
+}
+
+func TestMerkleProof(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		data := testBlockData(n)
+		root := data.MerkleRoot()
+		for _, td := range data.Trans {
+			proof, err := data.MerkleProof(td.Hash())
+			if err != nil {
+				t.Errorf("%d transactions: unexpected error: %s", n, err.Error())
+				continue
+			}
+			if !VerifyMerkleProof(root, td.Hash(), proof) {
+				t.Errorf("%d transactions: proof of %s didn't verify", n, td.Hash())
+			}
+		}
+	}
+}
+
+func TestMerkleProofFAIL(t *testing.T) {
+	data := testBlockData(5)
+	other := testBlockData(1)
+	if _, err := data.MerkleProof(other.Trans[0].Hash()); err == nil {
+		t.Error("Should have failed on transaction that isn't in the block")
+	}
+
+	proof, _ := data.MerkleProof(data.Trans[2].Hash())
+	if VerifyMerkleProof(data.MerkleRoot(), data.Trans[3].Hash(), proof) {
+		t.Error("Proof should only verify for its own transaction")
+	}
+	if VerifyMerkleProof(other.MerkleRoot(), data.Trans[2].Hash(), proof) {
+		t.Error("Proof should only verify against the root of its block")
+	}
+}
+
+func TestValidateBlockMerkleRoot(t *testing.T) {
+	sk, pk := KeyGen()
+	block := Block{1, "", pk, "", BlockNonce{}, "", testBlockData(3), "", "", ""}
+	block.SignBlock(sk)
+	if !block.ValidateBlock() {
+		t.Error("Block Validation Failed")
+	}
+
+	block.BlockData.Trans = block.BlockData.Trans[:2]
+	if block.ValidateBlock() {
+		t.Error("Should have failed on transactions not matching the Merkle root")
+	}
+}
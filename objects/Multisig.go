@@ -0,0 +1,57 @@
+package objects
+
+import (
+	. "github.com/nfk93/blockchain/crypto"
+	"github.com/pkg/errors"
+)
+
+// PartialTransaction is a transaction from a multisig account collecting the signatures of the keys of the account.
+// It is passed between the signers, who each add their signature, until enough of them have signed to make the
+// transaction
+type PartialTransaction struct {
+	Transaction Transaction
+	Signatures  []string
+}
+
+// NewPartialTransaction returns the unsigned transaction from the multisig account from
+func NewPartialTransaction(from PublicKey, to string, amount uint64, fee uint64, nonce uint64, validUntilSlot uint64) (PartialTransaction, error) {
+	if _, _, err := from.MultisigKeys(); err != nil {
+		return PartialTransaction{}, err
+	}
+	return PartialTransaction{Transaction: Transaction{from, to, amount, fee, nonce, validUntilSlot, ""}}, nil
+}
+
+// Sign adds the signature of signer, which has to be one of the keys of the account
+func (p *PartialTransaction) Sign(signer Signer) error {
+	_, keys, err := p.Transaction.From.MultisigKeys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if key == signer.PublicKey() {
+			p.Signatures = append(p.Signatures, signer.Sign(p.Transaction.stringToSign()))
+			return nil
+		}
+	}
+	return errors.Errorf("%s is not one of the keys of the account", signer.PublicKey().Address())
+}
+
+// Merge adds the signatures of other, which has to be a partial signature of the same transaction
+func (p *PartialTransaction) Merge(other PartialTransaction) error {
+	if other.Transaction != p.Transaction {
+		return errors.New("the partial signatures are of different transactions")
+	}
+	p.Signatures = append(p.Signatures, other.Signatures...)
+	return nil
+}
+
+// Combine returns the transaction signed by the multisig account, once enough of its keys have signed
+func (p PartialTransaction) Combine() (Transaction, error) {
+	t := p.Transaction
+	signature, err := CombineSignatures(t.From, t.stringToSign(), p.Signatures)
+	if err != nil {
+		return Transaction{}, err
+	}
+	t.Signature = signature
+	return t, nil
+}
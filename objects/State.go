@@ -1,118 +1,282 @@
 package objects
 
 import (
-	"bytes"
+	"encoding/binary"
+	"encoding/hex"
 	. "github.com/nfk93/blockchain/crypto"
 	"github.com/nfk93/blockchain/smart"
 	"github.com/pkg/errors"
-	"sort"
+	"math/bits"
 	"strconv"
+	"strings"
 )
 
+// State is the state after a block. Everything but the parent hash is committed to by the state root in the block
 type State struct {
-	Ledger     map[string]uint64
-	ConStake   map[string]uint64
-	ConOwners  map[string]PublicKey
+	// Tree holds the balances, nonces, baking keys and delegations of accounts, and the balances, owners and
+	// storage of contracts. Copying a State shares the tree, which is never changed in place
+	Tree       StateTree
 	ParentHash string
 	TotalStake uint64
+	// Scheduled holds the calls scheduled by contracts that haven't been made yet, in the order they were scheduled
+	Scheduled []smart.ScheduledCall
+}
+
+// Keys of the state tree. Accounts are keyed by key hash and contracts by their address
+const (
+	balancePrefix         = "balance/"
+	noncePrefix           = "nonce/"
+	contractBalancePrefix = "contract/balance/"
+	ownerPrefix           = "contract/owner/"
+	storagePrefix         = "contract/storage/"
+	prepaidPrefix         = "contract/prepaid/"
+	bakerPrefix           = "baker/key/"
+	bakingAccountPrefix   = "baker/account/"
+	delegationPrefix      = "delegation/"
+	delegatorsPrefix      = "delegators/"
+	delegatedKey          = "delegated"
+	totalStakeKey         = "totalstake"
+	scheduledKey          = "scheduled"
+)
+
+// BalanceKey is the key of the balance of an account in the state tree
+func BalanceKey(account string) string { return balancePrefix + account }
+
+// NonceKey is the key of the next nonce of an account in the state tree
+func NonceKey(account string) string { return noncePrefix + account }
+
+// ContractBalanceKey is the key of the balance of a contract in the state tree
+func ContractBalanceKey(addr string) string { return contractBalancePrefix + addr }
+
+// OwnerKey is the key of the owner of a contract in the state tree, the value is the public key of the owner
+func OwnerKey(addr string) string { return ownerPrefix + addr }
+
+// StorageKey is the key of the storage of a contract in the state tree, encoded with value.Encode
+func StorageKey(addr string) string { return storagePrefix + addr }
+
+// PrepaidKey is the key of the prepaid storage and storage cap of a contract in the state tree
+func PrepaidKey(addr string) string { return prepaidPrefix + addr }
+
+// BakerKey is the key of the baking key registered by an account in the state tree, the value is the baking key
+func BakerKey(account string) string { return bakerPrefix + account }
+
+// BakingAccountKey is the key of the account a registered baking key bakes for in the state tree, the value is the
+// key hash of the account
+func BakingAccountKey(bakingKey string) string { return bakingAccountPrefix + bakingKey }
+
+// DelegationKey is the key of the delegation of an account in the state tree, the value is the key hash of the baker
+func DelegationKey(account string) string { return delegationPrefix + account }
+
+// DelegatorsKey is the key of the accounts delegating to a baker in the state tree, the value is their sorted key
+// hashes. The bakers that have delegators are kept sorted under the delegated key, so the delegations are found
+// without walking the tree
+func DelegatorsKey(baker string) string { return delegatorsPrefix + baker }
+
+// DecodeUint reads a number from the state tree. Numbers that are zero are not in the tree
+func DecodeUint(v []byte) uint64 {
+	if len(v) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(v)
+}
+
+func encodeUint(i uint64) []byte {
+	if i == 0 {
+		return nil
+	}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], i)
+	return buf[:]
 }
 
 func NewInitialState(key PublicKey) State {
 	initialStake := uint64(1000000000000000) // 10 mil
-	ledger := make(map[string]uint64)
-	conStake := make(map[string]uint64)
-	conledger := make(map[string]PublicKey)
-	ledger[key.Hash()] = initialStake
-	return State{ledger, conStake, conledger, "", initialStake}
+	var s State
+	s.SetBalance(key.Hash(), initialStake)
+	s.TotalStake = initialStake
+	return s
 }
 
-//Returns gasCost
-func (s *State) AddTransaction(t Transaction, gasCost uint64) (uint64, error) {
-	//TODO: Handle checks of legal transactions
-	amountWithFees := t.Amount + gasCost
+// Balance returns the balance of the account with the given key hash
+func (s State) Balance(account string) uint64 {
+	return DecodeUint(s.Tree.Get(BalanceKey(account)))
+}
+
+// SetBalance sets the balance of the account with the given key hash. It doesn't change the total stake
+func (s *State) SetBalance(account string, amount uint64) {
+	s.Tree = s.Tree.Set(BalanceKey(account), encodeUint(amount))
+}
+
+// Ledger returns the balances of all accounts, keyed by key hash
+func (s State) Ledger() map[string]uint64 {
+	return s.uintsWithPrefix(balancePrefix)
+}
 
+// ContractBalance returns the balance of the contract at addr
+func (s State) ContractBalance(addr string) uint64 {
+	return DecodeUint(s.Tree.Get(ContractBalanceKey(addr)))
+}
+
+// ContractBalances returns the balances of all contracts, keyed by address
+func (s State) ContractBalances() map[string]uint64 {
+	return s.uintsWithPrefix(contractBalancePrefix)
+}
+
+// ContractOwner returns the owner of the contract at addr
+func (s State) ContractOwner(addr string) (PublicKey, bool) {
+	owner, err := ParsePublicKey(string(s.Tree.Get(OwnerKey(addr))))
+	return owner, err == nil
+}
+
+func (s State) uintsWithPrefix(prefix string) map[string]uint64 {
+	result := make(map[string]uint64)
+	s.Tree.Iterate(func(key string, value []byte) {
+		if strings.HasPrefix(key, prefix) {
+			result[key[len(prefix):]] = DecodeUint(value)
+		}
+	})
+	return result
+}
+
+func (s *State) addBalance(account string, amount uint64) {
+	s.SetBalance(account, s.Balance(account)+amount)
+}
+
+// Root returns the state root, the root of the state tree with the total stake and the scheduled calls added
+func (s State) Root() string {
+	return s.committedTree().Root()
+}
+
+// Prove returns the value of key in the state tree with the total stake and the scheduled calls added, together
+// with the proof of it against the state root
+func (s State) Prove(key string) ([]byte, StateProof) {
+	return s.committedTree().Prove(key)
+}
+
+func (s State) committedTree() StateTree {
+	return s.Tree.Set(totalStakeKey, encodeUint(s.TotalStake)).Set(scheduledKey, encodeScheduled(s.Scheduled))
+}
+
+func encodeScheduled(scheduled []smart.ScheduledCall) []byte {
+	if len(scheduled) == 0 {
+		return nil
+	}
+	e := newEncoder()
+	encodeScheduledCalls(e, scheduled)
+	return e.Bytes()
+}
+
+// MinGasPrice is the lowest price a unit of gas can be bought for
+const MinGasPrice = uint64(1)
+
+// Pays the transaction and its fee. A transaction uses gas units of gas, which its fee has to pay at least
+// MinGasPrice for.
+// Returns the fee
+func (s *State) AddTransaction(t Transaction, gas uint64) (uint64, error) {
+	//TODO: Handle checks of legal transactions
 	if !t.VerifyTransaction() {
 		// fmt.Println("The transactions didn't verify", t)
+		if t.From.Scheme == Multisig {
+			return 0, errors.New("Transaction isn't signed by enough keys of the multisig account!")
+		}
 		return 0, errors.New("Transaction signature didn't verify!")
 	}
 
+	if !isKeyHash(t.To) {
+		return 0, errors.New("Transaction receiver is not an address!")
+	}
+
+	if err := s.useNonce(t.From, t.Nonce); err != nil {
+		return 0, err
+	}
+
+	if minFee, ok := gasCost(gas, MinGasPrice); !ok || t.Fee < minFee {
+		return 0, errors.New("Transaction fee is below the minimum gas price!")
+	}
+
 	// Sender has to be able to pay both the amount and the fee
-	if s.Ledger[t.From.Hash()] < amountWithFees {
+	balance := s.Balance(t.From.Hash())
+	if balance < t.Amount || balance-t.Amount < t.Fee {
 		// fmt.Println("Not enough money on senders account")
 		return 0, errors.New("Not enough funds for Transaction!")
 	}
 
-	s.Ledger[t.From.Hash()] -= amountWithFees
-	s.Ledger[t.To.Hash()] += t.Amount
-	s.TotalStake -= gasCost // Take the fee out of the system
-	return gasCost, nil
+	s.SetBalance(t.From.Hash(), balance-t.Amount-t.Fee)
+	s.addBalance(t.To, t.Amount)
+	s.TotalStake -= t.Fee // Take the fee out of the system
+	return t.Fee, nil
 }
 
-func (s *State) AddAmountToAccount(pk PublicKey, reward uint64) {
-	s.Ledger[pk.Hash()] += reward
-	s.TotalStake += reward // putting back the fees and an block reward if anyone claim it
+// gasCost returns the price of gas units of gas, or false if it doesn't fit in a uint64
+func gasCost(gas uint64, price uint64) (uint64, bool) {
+	hi, lo := bits.Mul64(gas, price)
+	return lo, hi == 0
 }
 
-func (s State) toString() string {
-	var buf bytes.Buffer
+// isKeyHash returns whether s is the hex encoded hash of a key, so funds are never sent to an account nobody can own
+func isKeyHash(s string) bool {
+	hash, err := hex.DecodeString(s)
+	return err == nil && len(hash) == 32 && hex.EncodeToString(hash) == s
+}
 
-	sortedLedger := make(map[string]uint64)
+// NextNonce returns the nonce the next transaction from the account with the given key hash must have
+func (s State) NextNonce(account string) uint64 {
+	return DecodeUint(s.Tree.Get(NonceKey(account)))
+}
 
-	keys := make([]string, 0, len(s.Ledger))
-	for k := range s.Ledger {
-		sortedLedger[k] = s.Ledger[k]
-		keys = append(keys, k)
+// useNonce checks that nonce is the next nonce of the account of pk and counts it as used. Nonces that were used
+// already are replays, and nonces after the next one leave a gap, so both are rejected.
+// The nonce is used even if the transaction fails later on, so the following transactions of the account aren't
+// stuck behind it
+func (s *State) useNonce(pk PublicKey, nonce uint64) error {
+	account := pk.Hash()
+	next := s.NextNonce(account)
+	if nonce < next {
+		return errors.Errorf("Nonce %d was already used, the next nonce is %d", nonce, next)
 	}
-	sort.Strings(keys)
-
-	for _, k := range keys {
-		buf.WriteString(k)
-		buf.WriteString(strconv.Itoa(int(sortedLedger[k])))
+	if nonce > next {
+		return errors.Errorf("Nonce %d is ahead of the next nonce %d", nonce, next)
 	}
-	buf.WriteString(s.ParentHash)
-	buf.WriteString(strconv.Itoa(int(s.TotalStake)))
-
-	return buf.String()
-}
-
-func (s *State) SignHashedState(sk SecretKey) string {
-	return Sign(HashSHA(s.toString()), sk)
+	s.Tree = s.Tree.Set(NonceKey(account), encodeUint(next+1))
+	return nil
 }
 
-func (s State) VerifyHashedState(sig string, pk PublicKey) bool {
-	return Verify(HashSHA(s.toString()), sig, pk)
+func (s *State) AddAmountToAccount(pk PublicKey, reward uint64) {
+	s.addBalance(pk.Hash(), reward)
+	s.TotalStake += reward // putting back the fees and an block reward if anyone claim it
 }
 
 // Opens account for contract and moves prepaid to its account
 func (s *State) InitializeContractAccount(addr string, owner PublicKey) {
-	s.ConOwners[addr] = owner
+	s.Tree = s.Tree.Set(OwnerKey(addr), []byte(owner.String()))
 }
 
 // Used for handling contract layer transaction to users
 func (s *State) AddContractTransaction(t smart.ContractTransaction) {
-	s.Ledger[t.To] += t.Amount
+	s.addBalance(t.To, t.Amount)
 }
 
-// Returns true if caller has enough funds on account to pay for call
-func (s *State) FundContractCall(callerAccount PublicKey, amount uint64, gas uint64) bool {
-	if s.Ledger[callerAccount.Hash()] >= amount+gas {
-		s.TotalStake -= gas
-		s.Ledger[callerAccount.Hash()] -= amount + gas
+// Returns true if caller has enough funds on account to pay for call. The cost of the gas is taken out of the system
+func (s *State) FundContractCall(callerAccount PublicKey, amount uint64, cost uint64) bool {
+	balance := s.Balance(callerAccount.Hash())
+	if balance >= amount && balance-amount >= cost {
+		s.TotalStake -= cost
+		s.SetBalance(callerAccount.Hash(), balance-amount-cost)
 		return true
 	}
 	return false
 }
 
-//Used to refund money from contracts back into the original user ledger
+// Used to refund money from contracts back into the original user ledger
 func (s *State) returnAmountFromContracts(callerAccount PublicKey, amount uint64) {
-	s.Ledger[callerAccount.Hash()] += amount
+	s.addBalance(callerAccount.Hash(), amount)
 }
 
 func (s *State) payContractInit(pk PublicKey, gas uint64, prepaid uint64) bool {
 	amount := gas + prepaid
 	owner := pk.Hash()
-	if s.Ledger[owner] > amount {
-		s.Ledger[owner] -= amount
+	if balance := s.Balance(owner); balance > amount {
+		s.SetBalance(owner, balance-amount)
 		s.TotalStake -= amount
 		return true
 	}
@@ -120,16 +284,21 @@ func (s *State) payContractInit(pk PublicKey, gas uint64, prepaid uint64) bool {
 }
 
 func (s *State) HandleContractInit(contractInit ContractInitialize, blockhash string, parenthash string, slot uint64) (uint64, error) {
+	if err := s.useNonce(contractInit.Owner, contractInit.Nonce); err != nil {
+		return 0, err
+	}
+	// The contract address is derived from the nonce, which is unique for the owner
+	nonce := strconv.FormatUint(contractInit.Nonce, 10)
 	if paymentAccepted := s.payContractInit(contractInit.Owner, contractInit.Gas, contractInit.Prepaid); paymentAccepted {
 		var addr string
 		var remainGas uint64
 		var err error
 
 		if blockhash == "" {
-			addr, remainGas, err = smart.InitiateContractOnNewBlock(contractInit.Owner, contractInit.Nonce, contractInit.Code, contractInit.Gas,
+			addr, remainGas, err = smart.InitiateContractOnNewBlock(contractInit.Owner, nonce, contractInit.Code, contractInit.Gas,
 				contractInit.Prepaid, contractInit.StorageLimit)
 		} else {
-			addr, remainGas, err = smart.InitiateContract(contractInit.Owner, contractInit.Nonce, contractInit.Code, contractInit.Gas, contractInit.Prepaid,
+			addr, remainGas, err = smart.InitiateContract(contractInit.Owner, nonce, contractInit.Code, contractInit.Gas, contractInit.Prepaid,
 				contractInit.StorageLimit, blockhash)
 		}
 
@@ -146,28 +315,41 @@ func (s *State) HandleContractInit(contractInit ContractInitialize, blockhash st
 	}
 }
 
+// Makes a contract call, paying GasPrice for each unit of gas it uses.
+// Returns the gas used, the fee of the call is the gas used times its gas price
 func (s *State) HandleContractCall(contract ContractCall, blockhash string, parenthash string, slot uint64) (uint64, error) {
+	if err := s.useNonce(contract.Caller, contract.Nonce); err != nil {
+		return 0, err
+	}
+	if contract.GasPrice < MinGasPrice {
+		return 0, errors.New("Gas price of contract call is below the minimum gas price")
+	}
+	cost, ok := gasCost(contract.Gas, contract.GasPrice)
+	if !ok {
+		return 0, errors.New("Gas of contract call costs more than any account can have")
+	}
 	// Transfer funds from caller to contract
-	if !s.FundContractCall(contract.Caller, contract.Amount, contract.Gas) {
+	if !s.FundContractCall(contract.Caller, contract.Amount, cost) {
 		return 0, errors.New("Not enough funds for contract call")
 	}
 	var newContractLedger map[string]uint64
 	var transferList []smart.ContractTransaction
+	var scheduled []smart.ScheduledCall
 	var remainingGas uint64
 	var callerr error
 
 	// Run contracts in smart contract layer
 	if blockhash == "" {
-		newContractLedger, transferList, remainingGas, callerr = smart.CallContractOnNewBlock(contract.Address,
-			contract.Entry, contract.Params, contract.Amount, contract.Gas)
+		newContractLedger, transferList, scheduled, remainingGas, callerr = smart.CallContractOnNewBlock(contract.Address,
+			contract.Entry, contract.Params, contract.Amount, contract.Gas, contract.Caller.Hash())
 	} else {
-		newContractLedger, transferList, remainingGas, callerr = smart.CallContract(contract.Address,
-			contract.Entry, contract.Params, contract.Amount, contract.Gas, blockhash)
+		newContractLedger, transferList, scheduled, remainingGas, callerr = smart.CallContract(contract.Address,
+			contract.Entry, contract.Params, contract.Amount, contract.Gas, contract.Caller.Hash(), blockhash)
 	}
 
 	// Calc how much gas used and refund not used gas to caller
 	gasUsed := contract.Gas - remainingGas
-	s.AddAmountToAccount(contract.Caller, remainingGas)
+	s.AddAmountToAccount(contract.Caller, remainingGas*contract.GasPrice)
 
 	// If contract not successful, then return amount to caller
 	if callerr != nil {
@@ -176,21 +358,118 @@ func (s *State) HandleContractCall(contract ContractCall, blockhash string, pare
 		return gasUsed, callerr
 	}
 	// If contract succeeded, execute the transactions from the contract layer
-	s.ConStake = newContractLedger
+	s.setContractBalances(newContractLedger)
 	for _, t := range transferList {
 		s.AddContractTransaction(t)
 	}
+	s.Scheduled = append(s.Scheduled, scheduled...)
 
 	return gasUsed, nil
 }
 
+// Makes the scheduled calls that are due in slot, in the order they were scheduled. The gas is paid from the
+// deposits of the calls, so it isn't subject to the gas limit of the block. Calls scheduled by these calls are
+// made in a later block at the earliest.
+// Returns the gas used and the errors of the calls that failed
+func (s *State) RunScheduledCalls(slot uint64, blockhash string) (uint64, []error) {
+	var due, pending []smart.ScheduledCall
+	for _, sc := range s.Scheduled {
+		if sc.Slot <= slot {
+			due = append(due, sc)
+		} else {
+			pending = append(pending, sc)
+		}
+	}
+	s.Scheduled = pending
+
+	accumulatedGas := uint64(0)
+	var errs []error
+	for _, sc := range due {
+		var newContractLedger map[string]uint64
+		var transferList []smart.ContractTransaction
+		var scheduled []smart.ScheduledCall
+		var gasUsed uint64
+		var callerr error
+		if blockhash == "" {
+			newContractLedger, transferList, scheduled, gasUsed, callerr = smart.RunScheduledCallOnNewBlock(sc)
+		} else {
+			newContractLedger, transferList, scheduled, gasUsed, callerr = smart.RunScheduledCall(sc, blockhash)
+		}
+
+		// The deposit was already taken from the schedulers balance, but not out of the stake
+		s.TotalStake -= gasUsed
+		if _, exists := newContractLedger[sc.Scheduler]; !exists {
+			// the scheduler has expired, so there is no one to return the rest of the deposit to
+			s.TotalStake -= sc.Deposit - gasUsed
+		}
+		accumulatedGas += gasUsed
+		s.setContractBalances(newContractLedger)
+		if callerr != nil {
+			errs = append(errs, callerr)
+			continue
+		}
+		for _, t := range transferList {
+			s.AddContractTransaction(t)
+		}
+		s.Scheduled = append(s.Scheduled, scheduled...)
+	}
+	return accumulatedGas, errs
+}
+
+// Returns the pending scheduled calls to the contract at addr
+func (s State) ScheduledTo(addr string) []smart.ScheduledCall {
+	var result []smart.ScheduledCall
+	for _, sc := range s.Scheduled {
+		if sc.Address == addr {
+			result = append(result, sc)
+		}
+	}
+	return result
+}
+
 // Get list of contract addresses that expire from the smart contract layer
 // pay contract stake back to owner and delete account
 func (s *State) CleanExpiredContract(expiring []string) {
 	for _, conAddr := range expiring {
-		owner := s.ConOwners[conAddr]
-		s.Ledger[owner.Hash()] += s.ConStake[conAddr]
-		delete(s.ConOwners, conAddr)
+		if owner, exists := s.ContractOwner(conAddr); exists {
+			s.addBalance(owner.Hash(), s.ContractBalance(conAddr))
+		}
+		for _, key := range []string{OwnerKey(conAddr), ContractBalanceKey(conAddr), StorageKey(conAddr), PrepaidKey(conAddr)} {
+			s.Tree = s.Tree.Delete(key)
+		}
+	}
+
+}
+
+// Sets the balances of the contracts in the ledger from the smart contract layer
+func (s *State) setContractBalances(ledger map[string]uint64) {
+	for addr, balance := range ledger {
+		s.Tree = s.Tree.Set(ContractBalanceKey(addr), encodeUint(balance))
+	}
+}
+
+// SetContractStorage records the storage of the contracts after a block, as given by the smart contract layer
+func (s *State) SetContractStorage(contracts map[string]smart.StoredContract) {
+	for addr, c := range contracts {
+		prepaid := make([]byte, 16)
+		binary.BigEndian.PutUint64(prepaid[:8], c.PrepaidStorage)
+		binary.BigEndian.PutUint64(prepaid[8:], c.Storagecap)
+		s.Tree = s.Tree.Set(StorageKey(addr), c.Storage).Set(PrepaidKey(addr), prepaid)
 	}
+}
 
+// ContractStorage returns what SetContractStorage recorded of the contracts, keyed by address
+func (s State) ContractStorage() map[string]smart.StoredContract {
+	contracts := make(map[string]smart.StoredContract)
+	s.Tree.Iterate(func(key string, value []byte) {
+		if strings.HasPrefix(key, prepaidPrefix) && len(value) == 16 {
+			addr := key[len(prepaidPrefix):]
+			contracts[addr] = smart.StoredContract{
+				PrepaidStorage: binary.BigEndian.Uint64(value[:8]),
+				Storagecap:     binary.BigEndian.Uint64(value[8:]),
+				Storage:        s.Tree.Get(StorageKey(addr)),
+			}
+		}
+	})
+	return contracts
 }
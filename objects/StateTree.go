@@ -0,0 +1,284 @@
+package objects
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// StateTree is an authenticated key/value store, a sparse Merkle tree over the sha256 hashes of the keys. A subtree
+// holding a single entry is collapsed into its leaf, so the tree only gets as deep as needed to tell the keys apart.
+// Trees are never changed. Set and Delete return a new tree sharing all untouched nodes with the old one, so copying
+// a tree is free and forks of the state only cost the nodes they change.
+type StateTree struct {
+	root *treeNode
+}
+
+type treeNode struct {
+	// inner nodes have at least one child, leaves have none
+	left, right *treeNode
+	key         string
+	path        [32]byte
+	value       []byte
+	hash        [32]byte
+}
+
+// Leaves and inner nodes are hashed with different prefixes, so an inner node can't be passed off as a leaf.
+// An empty subtree hashes to all zeroes
+const (
+	stateLeafPrefix = 0
+	stateNodePrefix = 1
+)
+
+// StateProof proves the value of a key in a tree with a given root, or that the key is not in the tree. Siblings
+// holds the hashes of the siblings on the path of the key, from the root down. If the path ends in the leaf of
+// another key, OtherPath and OtherValue are its path and the hash of its value
+type StateProof struct {
+	Siblings   []string
+	OtherPath  string
+	OtherValue string
+}
+
+// Root returns the hash of the tree
+func (t StateTree) Root() string {
+	return hex.EncodeToString(nodeHash(t.root))
+}
+
+// Get returns the value of key, or nil if key is not in the tree
+func (t StateTree) Get(key string) []byte {
+	path := sha256.Sum256([]byte(key))
+	n := t.root
+	for depth := 0; n != nil; depth++ {
+		if n.isLeaf() {
+			if n.path == path {
+				return n.value
+			}
+			return nil
+		}
+		n = n.child(bit(path, depth))
+	}
+	return nil
+}
+
+// Set returns the tree with key set to value. Setting a key to nil or an empty value deletes it
+func (t StateTree) Set(key string, value []byte) StateTree {
+	if len(value) == 0 {
+		return t.Delete(key)
+	}
+	if bytes.Equal(t.Get(key), value) {
+		return t
+	}
+	return StateTree{insert(t.root, newLeaf(key, value), 0)}
+}
+
+// Delete returns the tree without key
+func (t StateTree) Delete(key string) StateTree {
+	if t.Get(key) == nil {
+		return t
+	}
+	return StateTree{remove(t.root, sha256.Sum256([]byte(key)), 0)}
+}
+
+// Iterate calls f with every key and value in the tree, in the order of their paths
+func (t StateTree) Iterate(f func(key string, value []byte)) {
+	var walk func(n *treeNode)
+	walk = func(n *treeNode) {
+		if n == nil {
+			return
+		}
+		if n.isLeaf() {
+			f(n.key, n.value)
+			return
+		}
+		walk(n.left)
+		walk(n.right)
+	}
+	walk(t.root)
+}
+
+// Prove returns the value of key together with the proof of it. The value is nil if key is not in the tree, and the
+// proof then proves that
+func (t StateTree) Prove(key string) ([]byte, StateProof) {
+	path := sha256.Sum256([]byte(key))
+	var proof StateProof
+	n := t.root
+	for depth := 0; n != nil && !n.isLeaf(); depth++ {
+		b := bit(path, depth)
+		proof.Siblings = append(proof.Siblings, hex.EncodeToString(nodeHash(n.child(1-b))))
+		n = n.child(b)
+	}
+	if n == nil {
+		return nil, proof
+	}
+	if n.path != path {
+		valueHash := sha256.Sum256(n.value)
+		proof.OtherPath = hex.EncodeToString(n.path[:])
+		proof.OtherValue = hex.EncodeToString(valueHash[:])
+		return nil, proof
+	}
+	return n.value, proof
+}
+
+// VerifyStateProof returns whether proof proves that key has value in the tree with the given root. A nil value
+// means that key is not in the tree
+func VerifyStateProof(root string, key string, value []byte, proof StateProof) bool {
+	path := sha256.Sum256([]byte(key))
+	var hash []byte
+	switch {
+	case len(value) > 0:
+		if proof.OtherPath != "" {
+			return false
+		}
+		valueHash := sha256.Sum256(value)
+		hash = leafHash(path, valueHash)
+	case proof.OtherPath == "":
+		hash = make([]byte, 32)
+	default:
+		other, err1 := hex.DecodeString(proof.OtherPath)
+		otherValue, err2 := hex.DecodeString(proof.OtherValue)
+		if err1 != nil || err2 != nil || len(other) != 32 || len(otherValue) != 32 || bytes.Equal(other, path[:]) {
+			return false
+		}
+		var otherPath, otherValueHash [32]byte
+		copy(otherPath[:], other)
+		copy(otherValueHash[:], otherValue)
+		// the other leaf can only be where the path of key ends if their paths agree up to there
+		for depth := range proof.Siblings {
+			if bit(otherPath, depth) != bit(path, depth) {
+				return false
+			}
+		}
+		hash = leafHash(otherPath, otherValueHash)
+	}
+	for depth := len(proof.Siblings) - 1; depth >= 0; depth-- {
+		sibling, err := hex.DecodeString(proof.Siblings[depth])
+		if err != nil || len(sibling) != 32 {
+			return false
+		}
+		if bit(path, depth) == 0 {
+			hash = innerHash(hash, sibling)
+		} else {
+			hash = innerHash(sibling, hash)
+		}
+	}
+	return hex.EncodeToString(hash) == root
+}
+
+func newLeaf(key string, value []byte) *treeNode {
+	path := sha256.Sum256([]byte(key))
+	valueHash := sha256.Sum256(value)
+	n := &treeNode{key: key, path: path, value: value}
+	copy(n.hash[:], leafHash(path, valueHash))
+	return n
+}
+
+func newInner(left, right *treeNode) *treeNode {
+	n := &treeNode{left: left, right: right}
+	copy(n.hash[:], innerHash(nodeHash(left), nodeHash(right)))
+	return n
+}
+
+func (n *treeNode) isLeaf() bool {
+	return n.left == nil && n.right == nil
+}
+
+func (n *treeNode) child(b int) *treeNode {
+	if b == 0 {
+		return n.left
+	}
+	return n.right
+}
+
+func withChild(n *treeNode, b int, child *treeNode) *treeNode {
+	if b == 0 {
+		return newInner(child, n.right)
+	}
+	return newInner(n.left, child)
+}
+
+func insert(n *treeNode, leaf *treeNode, depth int) *treeNode {
+	switch {
+	case n == nil:
+		return leaf
+	case n.isLeaf() && n.path == leaf.path:
+		return leaf
+	case n.isLeaf():
+		return split(n, leaf, depth)
+	default:
+		b := bit(leaf.path, depth)
+		return withChild(n, b, insert(n.child(b), leaf, depth+1))
+	}
+}
+
+// split returns the subtree at depth holding the two leaves
+func split(a, b *treeNode, depth int) *treeNode {
+	bitA, bitB := bit(a.path, depth), bit(b.path, depth)
+	switch {
+	case bitA == bitB && bitA == 0:
+		return newInner(split(a, b, depth+1), nil)
+	case bitA == bitB:
+		return newInner(nil, split(a, b, depth+1))
+	case bitA == 0:
+		return newInner(a, b)
+	default:
+		return newInner(b, a)
+	}
+}
+
+func remove(n *treeNode, path [32]byte, depth int) *treeNode {
+	if n == nil {
+		return nil
+	}
+	if n.isLeaf() {
+		if n.path == path {
+			return nil
+		}
+		return n
+	}
+	b := bit(path, depth)
+	child := remove(n.child(b), path, depth+1)
+	left, right := n.left, n.right
+	if b == 0 {
+		left = child
+	} else {
+		right = child
+	}
+	// a subtree left with a single leaf collapses into it
+	switch {
+	case left == nil && right == nil:
+		return nil
+	case left == nil && right.isLeaf():
+		return right
+	case right == nil && left.isLeaf():
+		return left
+	default:
+		return newInner(left, right)
+	}
+}
+
+func bit(path [32]byte, depth int) int {
+	return int(path[depth/8]>>(7-uint(depth%8))) & 1
+}
+
+func nodeHash(n *treeNode) []byte {
+	if n == nil {
+		return make([]byte, 32)
+	}
+	return n.hash[:]
+}
+
+func leafHash(path [32]byte, valueHash [32]byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{stateLeafPrefix})
+	h.Write(path[:])
+	h.Write(valueHash[:])
+	return h.Sum(nil)
+}
+
+func innerHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{stateNodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
@@ -0,0 +1,106 @@
+package objects
+
+import (
+	"strconv"
+	"testing"
+)
+
+func testTree(keys []string) StateTree {
+	var tree StateTree
+	for _, k := range keys {
+		tree = tree.Set(k, []byte("value of "+k))
+	}
+	return tree
+}
+
+func testKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	return keys
+}
+
+func TestStateTree_SetGetDelete(t *testing.T) {
+	keys := testKeys(50)
+	tree := testTree(keys)
+	for _, k := range keys {
+		if string(tree.Get(k)) != "value of "+k {
+			t.Errorf("wrong value of %s: %s", k, tree.Get(k))
+		}
+	}
+	if tree.Get("missing") != nil {
+		t.Error("missing key should have no value")
+	}
+
+	deleted := tree.Delete("key7").Set("key8", nil)
+	if deleted.Get("key7") != nil || deleted.Get("key8") != nil {
+		t.Error("deleted keys should have no value")
+	}
+	if string(tree.Get("key7")) != "value of key7" {
+		t.Error("deleting from a tree should not change the old tree")
+	}
+}
+
+func TestStateTree_RootIsCanonical(t *testing.T) {
+	keys := testKeys(30)
+	reversed := make([]string, len(keys))
+	for i, k := range keys {
+		reversed[len(keys)-1-i] = k
+	}
+	if testTree(keys).Root() != testTree(reversed).Root() {
+		t.Error("root should not depend on the order of insertion")
+	}
+
+	withExtra := testTree(append(keys, "extra"))
+	if withExtra.Root() == testTree(keys).Root() {
+		t.Error("root should change when a key is added")
+	}
+	if withExtra.Delete("extra").Root() != testTree(keys).Root() {
+		t.Error("deleting a key should give the root of the tree without it")
+	}
+	if (StateTree{}).Set("a", []byte("1")).Delete("a").Root() != (StateTree{}).Root() {
+		t.Error("deleting every key should give the root of the empty tree")
+	}
+}
+
+func TestStateTree_Proofs(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 17} {
+		tree := testTree(testKeys(n))
+		root := tree.Root()
+		for _, k := range testKeys(n) {
+			value, proof := tree.Prove(k)
+			if !VerifyStateProof(root, k, value, proof) {
+				t.Errorf("%d keys: proof of %s didn't verify", n, k)
+			}
+			if VerifyStateProof(root, k, []byte("wrong"), proof) {
+				t.Errorf("%d keys: proof of %s verified a wrong value", n, k)
+			}
+			if VerifyStateProof(root, k, nil, proof) {
+				t.Errorf("%d keys: proof of %s verified that it is missing", n, k)
+			}
+		}
+
+		value, proof := tree.Prove("missing")
+		if value != nil {
+			t.Errorf("%d keys: missing key should have no value", n)
+		}
+		if !VerifyStateProof(root, "missing", nil, proof) {
+			t.Errorf("%d keys: proof of missing key didn't verify", n)
+		}
+		if VerifyStateProof(root, "missing", []byte("value of missing"), proof) {
+			t.Errorf("%d keys: proof of missing key verified a value", n)
+		}
+	}
+}
+
+func TestStateTree_Encode(t *testing.T) {
+	tree := testTree(testKeys(20))
+	decoded, err := DecodeState(State{Tree: tree}.Encode())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if decoded.Tree.Root() != tree.Root() {
+		t.Error("decoded tree should have the same root")
+	}
+}
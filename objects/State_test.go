@@ -2,24 +2,24 @@ package objects
 
 import (
 	. "github.com/nfk93/blockchain/crypto"
-	"github.com/nfk93/blockchain/transaction"
+	"github.com/nfk93/blockchain/smart"
+	"reflect"
 	"testing"
 )
 
 func TestState_AddContractTransaction(t *testing.T) {
 
 	var s State
-	s.Ledger = make(map[string]uint64)
-	sk1, pk1 := KeyGen(2048)
-	_, pk2 := KeyGen(2048)
-	s.Ledger[pk1.Hash()] = 100
-	s.Ledger[pk2.Hash()] = 100
+	sk1, pk1 := KeyGen()
+	_, pk2 := KeyGen()
+	s.SetBalance(pk1.Hash(), 100)
+	s.SetBalance(pk2.Hash(), 100)
 	s.TotalStake = 100 + 100
 
-	trans := CreateTransaction(pk1, pk2, 50, "transOne", sk1)
+	trans := CreateTransaction(pk1, pk2.Hash(), 50, 2, 0, 0, sk1)
 	s.AddTransaction(trans, 2)
 
-	if s.Ledger[pk1.Hash()] != 150 && s.Ledger[pk1.Hash()] != 48 {
+	if s.Balance(pk1.Hash()) != 150 && s.Balance(pk1.Hash()) != 48 {
 		t.Error("not correct amount!")
 	}
 	if s.TotalStake != 100+100-2 {
@@ -31,18 +31,17 @@ func TestState_AddContractTransaction(t *testing.T) {
 func TestState_AddBlockReward(t *testing.T) {
 
 	var s State
-	s.Ledger = make(map[string]uint64)
-	sk1, pk1 := KeyGen(2048)
-	_, pk2 := KeyGen(2048)
-	s.Ledger[pk1.Hash()] = 100
-	s.Ledger[pk2.Hash()] = 100
+	sk1, pk1 := KeyGen()
+	_, pk2 := KeyGen()
+	s.SetBalance(pk1.Hash(), 100)
+	s.SetBalance(pk2.Hash(), 100)
 	s.TotalStake = 100 + 100
 
-	trans := CreateTransaction(pk1, pk2, 50, "transOne", sk1)
+	trans := CreateTransaction(pk1, pk2.Hash(), 50, 2, 0, 0, sk1)
 	s.AddTransaction(trans, 2)
 	s.AddAmountToAccount(pk1, 2)
 
-	if s.Ledger[pk1.Hash()] != 150 && s.Ledger[pk1.Hash()] != 50 {
+	if s.Balance(pk1.Hash()) != 150 && s.Balance(pk1.Hash()) != 50 {
 		t.Error("not correct amount!")
 	}
 	if s.TotalStake != 100+100-2+2 {
@@ -51,22 +50,358 @@ func TestState_AddBlockReward(t *testing.T) {
 
 }
 
+func TestState_AddTransactionToAddress(t *testing.T) {
+	var s State
+	sk1, pk1 := KeyGen()
+	s.SetBalance(pk1.Hash(), 100)
+	s.TotalStake = 100
+
+	// the receiver has never revealed its key
+	receiver := HashSHA("cold wallet")
+	if _, err := s.AddTransaction(CreateTransaction(pk1, receiver, 50, 2, 0, 0, sk1), 2); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if s.Balance(receiver) != 50 || s.Balance(pk1.Hash()) != 48 {
+		t.Error("not correct amount!")
+	}
+
+	if _, err := s.AddTransaction(CreateTransaction(pk1, "not an address", 10, 2, 1, 0, sk1), 2); err == nil {
+		t.Error("transaction to something that isn't an address should fail")
+	}
+	if s.Balance(pk1.Hash()) != 48 {
+		t.Error("failed transaction should not change the ledger")
+	}
+}
+
+func TestState_Nonces(t *testing.T) {
+	var s State
+	sk1, pk1 := KeyGen()
+	_, pk2 := KeyGen()
+	s.SetBalance(pk1.Hash(), 100)
+	s.TotalStake = 100
+
+	first := CreateTransaction(pk1, pk2.Hash(), 10, 2, 0, 0, sk1)
+	if _, err := s.AddTransaction(first, 2); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if _, err := s.AddTransaction(first, 2); err == nil {
+		t.Error("replayed transaction should fail")
+	}
+	if _, err := s.AddTransaction(CreateTransaction(pk1, pk2.Hash(), 10, 2, 2, 0, sk1), 2); err == nil {
+		t.Error("transaction leaving a gap in the nonces should fail")
+	}
+	if s.Balance(pk1.Hash()) != 88 || s.Balance(pk2.Hash()) != 10 {
+		t.Error("only the first transaction should change the ledger")
+	}
+	if s.NextNonce(pk1.Hash()) != 1 || s.NextNonce(pk2.Hash()) != 0 {
+		t.Error("not correct nonces")
+	}
+
+	// a transaction that can't be paid still uses its nonce
+	if _, err := s.AddTransaction(CreateTransaction(pk1, pk2.Hash(), 1000, 2, 1, 0, sk1), 2); err == nil {
+		t.Error("transaction without funds should fail")
+	}
+	if _, err := s.AddTransaction(CreateTransaction(pk1, pk2.Hash(), 10, 2, 2, 0, sk1), 2); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+}
+
+func TestState_TransactionFee(t *testing.T) {
+	var s State
+	sk1, pk1 := KeyGen()
+	_, pk2 := KeyGen()
+	s.SetBalance(pk1.Hash(), 100)
+	s.TotalStake = 100
+
+	if _, err := s.AddTransaction(CreateTransaction(pk1, pk2.Hash(), 10, 1, 0, 0, sk1), 2); err == nil {
+		t.Error("transaction paying less than the minimum gas price should fail")
+	}
+	fee, err := s.AddTransaction(CreateTransaction(pk1, pk2.Hash(), 10, 30, 1, 0, sk1), 2)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if fee != 30 || s.Balance(pk1.Hash()) != 60 || s.TotalStake != 70 {
+		t.Error("not correct fee!")
+	}
+	if _, err := s.AddTransaction(CreateTransaction(pk1, pk2.Hash(), 50, 20, 2, 0, sk1), 2); err == nil {
+		t.Error("transaction without funds for its fee should fail")
+	}
+}
+
+func TestState_MultisigTransaction(t *testing.T) {
+	sk1, pk1 := KeyGen()
+	sk2, pk2 := KeyGen()
+	_, pk3 := KeyGen()
+	multisig, err := NewMultisigKey(2, []PublicKey{pk1, pk2, pk3})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	var s State
+	s.SetBalance(multisig.Hash(), 100)
+	s.TotalStake = 100
+
+	p, err := NewPartialTransaction(multisig, pk3.Hash(), 50, 2, 0, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	p.Sign(sk1)
+	if _, err := p.Combine(); err == nil {
+		t.Error("Should have failed on too few signatures")
+	}
+	unsigned := p.Transaction
+	unsigned.Signature = CreateTransaction(pk1, pk3.Hash(), 50, 2, 0, 0, sk1).Signature
+	if _, err := s.AddTransaction(unsigned, 2); err == nil {
+		t.Error("Transaction signed by a single key of the account should fail")
+	}
+
+	other, _ := NewPartialTransaction(multisig, pk3.Hash(), 50, 2, 0, 0)
+	other.Sign(sk2)
+	if err := p.Merge(other); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	trans, err := p.Combine()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if _, err := s.AddTransaction(trans, 2); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if s.Balance(multisig.Hash()) != 48 || s.Balance(pk3.Hash()) != 50 {
+		t.Error("Multisig transaction was not paid")
+	}
+}
+
+func TestState_RegisterBaker(t *testing.T) {
+	sk1, pk1 := KeyGen()
+	bakingSk, bakingKey := KeyGen()
+	sk3, pk3 := KeyGen()
+	var s State
+	s.SetBalance(pk1.Hash(), 100)
+	s.SetBalance(pk3.Hash(), 100)
+	s.TotalStake = 200
+
+	forged := CreateBakerRegistration(pk1, bakingKey, BakingConsent(pk1, 100, sk1), 2, 0, 100, sk1)
+	if _, err := s.RegisterBaker(forged, 2); err == nil {
+		t.Error("Should have failed on a consent not signed by the baking key")
+	}
+
+	consent := BakingConsent(pk1, 100, bakingSk)
+	if _, err := s.RegisterBaker(CreateBakerRegistration(pk1, bakingKey, consent, 2, 0, 200, sk1), 2); err == nil {
+		t.Error("Should have failed on a consent given for another slot")
+	}
+	neverExpires := CreateBakerRegistration(pk1, bakingKey, BakingConsent(pk1, 0, bakingSk), 2, 0, 0, sk1)
+	if _, err := s.RegisterBaker(neverExpires, 2); err == nil {
+		t.Error("Should have failed on a consent that never expires")
+	}
+	if _, err := s.RegisterBaker(CreateBakerRegistration(pk1, bakingKey, consent, 2, 0, 100, sk1), 2); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if key, registered := s.BakingKey(pk1.Hash()); !registered || key != bakingKey {
+		t.Error("Baking key was not registered")
+	}
+	if s.BakerAccount(bakingKey) != pk1.Hash() || s.BakerAccount(pk1) != "" || s.BakerAccount(pk3) != pk3.Hash() {
+		t.Error("Baking key should bake for the account, and the account shouldn't bake itself")
+	}
+	if s.Balance(pk1.Hash()) != 98 || s.TotalStake != 198 {
+		t.Error("Fee of the registration was not paid")
+	}
+	s.PayBaker(bakingKey, 10)
+	if s.Balance(pk1.Hash()) != 108 || s.Balance(bakingKey.Hash()) != 0 {
+		t.Error("Reward of the baking key should be paid to the account")
+	}
+
+	taken := CreateBakerRegistration(pk3, bakingKey, BakingConsent(pk3, 100, bakingSk), 2, 0, 100, sk3)
+	if _, err := s.RegisterBaker(taken, 2); err == nil {
+		t.Error("Should have failed on the baking key of another account")
+	}
+
+	reset := CreateBakerRegistration(pk1, pk1, BakingConsent(pk1, 0, sk1), 2, 1, 0, sk1)
+	if _, err := s.RegisterBaker(reset, 2); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if _, registered := s.BakingKey(pk1.Hash()); registered || len(s.Bakers()) != 0 {
+		t.Error("Registering the key of the account should remove the baking key")
+	}
+	if s.BakerAccount(pk1) != pk1.Hash() {
+		t.Error("The account should bake itself again")
+	}
+}
+
+func TestState_RegisterBakerRSAAccount(t *testing.T) {
+	sk, pk := RSAKeyGen(512)
+	bakingSk, bakingKey := KeyGen()
+	var s State
+	s.SetBalance(pk.Hash(), 100)
+	s.TotalStake = 100
+
+	rsaSk, rsaKey := RSAKeyGen(512)
+	if _, err := s.RegisterBaker(CreateBakerRegistration(pk, rsaKey, BakingConsent(pk, 100, rsaSk), 2, 0, 100, sk), 2); err == nil {
+		t.Error("Should have failed on a baking key that isn't an ed25519 key")
+	}
+	if _, err := s.RegisterBaker(CreateBakerRegistration(pk, bakingKey, BakingConsent(pk, 100, bakingSk), 2, 0, 100, sk), 2); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if _, err := s.RegisterBaker(CreateBakerRegistration(pk, pk, BakingConsent(pk, 0, sk), 2, 1, 0, sk), 2); err != nil {
+		t.Fatalf("An rsa account should be able to unregister its baking key, got: %s", err.Error())
+	}
+	if _, registered := s.BakingKey(pk.Hash()); registered {
+		t.Error("The baking key should have been unregistered")
+	}
+}
+
+func TestState_Delegate(t *testing.T) {
+	sk1, pk1 := KeyGen()
+	sk2, pk2 := KeyGen()
+	sk3, pk3 := KeyGen()
+	var s State
+	s.SetBalance(pk1.Hash(), 302)
+	s.SetBalance(pk2.Hash(), 102)
+	s.SetBalance(pk3.Hash(), 600)
+	s.TotalStake = 1004
+
+	if _, err := s.Delegate(CreateDelegation(pk1, "not an address", 2, 0, 0, sk1), 2); err == nil {
+		t.Error("Should have failed on a baker that is not an address")
+	}
+	if _, err := s.Delegate(CreateDelegation(pk1, "", 2, 0, 0, sk1), 2); err == nil {
+		t.Error("Should have failed on undelegating without a delegation")
+	}
+	for i, sk := range []SecretKey{sk1, sk2} {
+		if _, err := s.Delegate(CreateDelegation(sk.Pk, pk3.Hash(), 2, 0, 0, sk), 2); err != nil {
+			t.Fatalf("unexpected error in delegation %d: %s", i, err.Error())
+		}
+	}
+	if s.DelegatedTo(pk1.Hash()) != pk3.Hash() || len(s.Delegators(pk3.Hash())) != 2 {
+		t.Error("Delegations were not recorded")
+	}
+	if _, err := s.Delegate(CreateDelegation(pk3, pk1.Hash(), 2, 0, 0, sk3), 2); err == nil {
+		t.Error("Should have failed on a baker with delegators delegating")
+	}
+	if _, err := s.Delegate(CreateDelegation(pk3, pk2.Hash(), 2, 0, 0, sk3), 2); err == nil {
+		t.Error("Should have failed on delegating to an account that delegates")
+	}
+
+	// balances are now 300, 100 and 600, so the delegators get 30% and 10% of the reward
+	s.PayBaker(pk3, 101)
+	if s.Balance(pk1.Hash()) != 330 || s.Balance(pk2.Hash()) != 110 || s.Balance(pk3.Hash()) != 661 {
+		t.Errorf("reward was shared as %d, %d and %d", s.Balance(pk1.Hash()), s.Balance(pk2.Hash()), s.Balance(pk3.Hash()))
+	}
+	if s.TotalStake != 1000+101 {
+		t.Error("TotalStake is not correct...")
+	}
+
+	if _, err := s.Delegate(CreateDelegation(pk1, "", 2, 1, 0, sk1), 2); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if s.DelegatedTo(pk1.Hash()) != "" || len(s.Delegations()) != 1 {
+		t.Error("Undelegating should remove the delegation")
+	}
+}
+
+func TestState_DelegatorsIndex(t *testing.T) {
+	sk1, pk1 := KeyGen()
+	_, pk2 := KeyGen()
+	_, pk3 := KeyGen()
+	var s State
+	s.SetBalance(pk1.Hash(), 100)
+	s.TotalStake = 100
+	empty := s.Root()
+
+	if _, err := s.Delegate(CreateDelegation(pk1, pk2.Hash(), 2, 0, 0, sk1), 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Delegate(CreateDelegation(pk1, pk3.Hash(), 2, 1, 0, sk1), 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Delegators(pk2.Hash())) != 0 || !reflect.DeepEqual(s.Delegators(pk3.Hash()), []string{pk1.Hash()}) {
+		t.Error("Delegating to another baker should move the delegator to it")
+	}
+	if !reflect.DeepEqual(s.Delegations(), map[string]string{pk1.Hash(): pk3.Hash()}) {
+		t.Errorf("Expected only the delegation to pk3, got %v", s.Delegations())
+	}
+
+	if _, err := s.Delegate(CreateDelegation(pk1, "", 2, 2, 0, sk1), 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Delegations()) != 0 {
+		t.Error("Undelegating should remove the delegator from the index")
+	}
+	// only the fees, nonce and ended delegation differ from the state before delegating
+	s.SetBalance(pk1.Hash(), 100)
+	s.TotalStake = 100
+	s.Tree = s.Tree.Delete(NonceKey(pk1.Hash()))
+	if s.Root() != empty {
+		t.Error("The index of a baker without delegators should be removed from the state")
+	}
+}
+
+func TestState_Root(t *testing.T) {
+	sk1, pk1 := KeyGen()
+	_, pk2 := KeyGen()
+	s := NewInitialState(pk1)
+	fork := s
+
+	if _, err := s.AddTransaction(CreateTransaction(pk1, pk2.Hash(), 50, 10000, 0, 0, sk1), 10000); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	if fork.Balance(pk2.Hash()) != 0 || fork.NextNonce(pk1.Hash()) != 0 {
+		t.Error("changing a copy of the state should not change the original")
+	}
+	if s.Root() == fork.Root() {
+		t.Error("state root should change with the state")
+	}
+
+	root := s.Root()
+	for _, key := range []string{BalanceKey(pk1.Hash()), BalanceKey(pk2.Hash()), NonceKey(pk1.Hash()), NonceKey(pk2.Hash())} {
+		value, proof := s.Prove(key)
+		if !VerifyStateProof(root, key, value, proof) {
+			t.Errorf("proof of %s didn't verify", key)
+		}
+	}
+	value, _ := s.Prove(BalanceKey(pk2.Hash()))
+	if DecodeUint(value) != 50 {
+		t.Error("proven balance is not correct")
+	}
+	value, _ = s.Prove(NonceKey(pk1.Hash()))
+	if DecodeUint(value) != 1 {
+		t.Error("proven nonce is not correct")
+	}
+
+	s.TotalStake++
+	if s.Root() == root {
+		t.Error("state root should cover the total stake")
+	}
+}
+
+func TestState_ContractStorage(t *testing.T) {
+	var s State
+	stored := map[string]smart.StoredContract{
+		"contract1": {PrepaidStorage: 100, Storagecap: 10, Storage: []byte{1, 2, 3}},
+		"contract2": {PrepaidStorage: 5, Storagecap: 5, Storage: []byte{4}},
+	}
+	s.SetContractStorage(stored)
+	if !reflect.DeepEqual(s.ContractStorage(), stored) {
+		t.Errorf("Expected the stored contracts back, got %v", s.ContractStorage())
+	}
+	s.CleanExpiredContract([]string{"contract1"})
+	if _, exists := s.ContractStorage()["contract1"]; exists {
+		t.Error("An expired contract should not have storage")
+	}
+}
+
 func TestState_FundContractCall(t *testing.T) {
 	var s State
-	s.Ledger = make(map[string]uint64)
-	_, pk1 := KeyGen(2048)
-	s.Ledger[pk1.Hash()] = 100
+	_, pk1 := KeyGen()
+	s.SetBalance(pk1.Hash(), 100)
 	s.TotalStake = 100
 
-	s.ConOwners = make(map[string]PublicKey)
-	s.ConOwners["address22"] = pk1
+	s.InitializeContractAccount("address22", pk1)
 
 	success := s.FundContractCall(pk1, 50, 20)
 
 	if !success {
 		t.Error("Fund account didn't succeed!")
 	}
-	if s.Ledger[pk1.Hash()] != 100-50-20 {
+	if s.Balance(pk1.Hash()) != 100-50-20 {
 		t.Error("Owners account is not correct...")
 	}
 	if s.TotalStake != 100-20 {
@@ -74,15 +409,10 @@ func TestState_FundContractCall(t *testing.T) {
 	}
 }
 
-func initState() {
-	channels := CreateChannelStruct()
-	go transaction.StartTransactionLayer(channels, false)
-}
-
 func BenchmarkState_AddTransactionTransactions(b *testing.B) {
-	sk, pk := KeyGen(2048)
-	_, pk1 := KeyGen(2048)
-	transaction := CreateTransaction(pk, pk1, 100000, "", sk)
+	sk, pk := KeyGen()
+	_, pk1 := KeyGen()
+	transaction := CreateTransaction(pk, pk1.Hash(), 100000, 200000, 0, 0, sk)
 	state := NewInitialState(pk)
 	b.ResetTimer()
 	state.AddTransaction(transaction, uint64(200000))
@@ -90,7 +420,7 @@ func BenchmarkState_AddTransactionTransactions(b *testing.B) {
 
 //func TestState_CleanContractLedger(t *testing.T) {
 //	var s State
-//	_, pk := KeyGen(2048)
+//	_, pk := KeyGen()
 //	s.Ledger = make(map[string]uint64)
 //	s.Ledger[pk.Hash()] = 100
 //	s.ConStake = make(map[string]uint64)
@@ -128,7 +458,7 @@ func BenchmarkState_AddTransactionTransactions(b *testing.B) {
 
 //func TestState_CollectStorageCost(t *testing.T) {
 //	var s State
-//	_, pk := KeyGen(2048)
+//	_, pk := KeyGen()
 //
 //	s.ConOwners = make(map[string]ContractAccount)
 //	s.ConOwners["address1"] = ContractAccount{pk, 200, 15}
@@ -159,7 +489,7 @@ func BenchmarkState_AddTransactionTransactions(b *testing.B) {
 //	// TODO: make an actual test now
 //
 //	var s State
-//	_, pk := KeyGen(2048)
+//	_, pk := KeyGen()
 //	con1 := "contract1"
 //
 //	s.ConOwners = map[string]PublicKey{}
@@ -188,3 +518,19 @@ func BenchmarkState_AddTransactionTransactions(b *testing.B) {
 //	}
 //
 //}
+
+func TestState_ScheduledTo(t *testing.T) {
+	var s State
+	s.Scheduled = []smart.ScheduledCall{
+		{Slot: 5, Scheduler: "a", Address: "b", Entry: "main"},
+		{Slot: 6, Scheduler: "b", Address: "a", Entry: "main"},
+		{Slot: 7, Scheduler: "c", Address: "b", Entry: "poke"},
+	}
+	scheduled := s.ScheduledTo("b")
+	if len(scheduled) != 2 || scheduled[0].Slot != 5 || scheduled[1].Slot != 7 {
+		t.Errorf("Expected the calls to b in slot 5 and 7, got %v", scheduled)
+	}
+	if len(s.ScheduledTo("c")) != 0 {
+		t.Error("No calls are scheduled to c")
+	}
+}
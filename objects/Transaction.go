@@ -1,50 +1,41 @@
 package objects
 
 import (
-	"bytes"
 	. "github.com/nfk93/blockchain/crypto"
-	"strconv"
-	"time"
 )
 
+// Transaction pays Amount from the account of From to the account with key hash To. The receiver doesn't need to
+// have revealed its public key, as keys are only revealed by the transactions sent from them. Nonce has to be the next
+// nonce of the account of From, so a transaction can't be replayed. Fee is paid to the baker including the
+// transaction, so a higher fee gets the transaction into a block sooner. A transaction with a ValidUntilSlot can only
+// be included in blocks up to that slot, while 0 means it never expires
 type Transaction struct {
-	From      PublicKey
-	To        PublicKey
-	Amount    uint64
-	ID        string
-	Signature string
-}
-
-func (t Transaction) toString() string {
-	var buf bytes.Buffer
-	buf.WriteString(t.From.String())
-	buf.WriteString(t.To.String())
-	buf.WriteString(strconv.Itoa(int(t.Amount)))
-	buf.WriteString(t.ID)
-	buf.WriteString(t.Signature)
-	return buf.String()
+	From           PublicKey
+	To             string
+	Amount         uint64
+	Fee            uint64
+	Nonce          uint64
+	ValidUntilSlot uint64
+	Signature      string
 }
 
 func (t Transaction) stringToSign() string {
-	var buf bytes.Buffer
-	buf.WriteString(t.From.String())
-	buf.WriteString(t.To.String())
-	buf.WriteString(strconv.Itoa(int(t.Amount)))
-	buf.WriteString(t.ID)
-	return buf.String()
+	e := newEncoder()
+	t.encode(e, false)
+	return e.String()
 }
 
-func (t *Transaction) SignTransaction(sk SecretKey) {
+func (t *Transaction) SignTransaction(signer Signer) {
 	m := t.stringToSign()
-	t.Signature = Sign(m, sk)
+	t.Signature = signer.Sign(m)
 }
 
 func (t *Transaction) VerifyTransaction() bool {
-	return Verify(t.stringToSign(), t.Signature, t.From)
+	return t.From.Verify(t.stringToSign(), t.Signature)
 }
 
-func CreateTransaction(from PublicKey, to PublicKey, amount uint64, id string, sk SecretKey) Transaction {
-	t := Transaction{from, to, amount, from.Hash()[:10] + "-" + id + "-" + time.Now().String(), ""}
-	t.SignTransaction(sk)
+func CreateTransaction(from PublicKey, to string, amount uint64, fee uint64, nonce uint64, validUntilSlot uint64, signer Signer) Transaction {
+	t := Transaction{from, to, amount, fee, nonce, validUntilSlot, ""}
+	t.SignTransaction(signer)
 	return t
 }
@@ -2,14 +2,13 @@ package objects
 
 import (
 	. "github.com/nfk93/blockchain/crypto"
-	"github.com/nfk93/blockchain/transaction"
 	"testing"
 )
 
 func TestVerifyTransaction(t *testing.T) {
-	var sk, pk = KeyGen(2048)
-	var _, pk2 = KeyGen(2048)
-	b := Transaction{pk, pk2, 200, "1", ""}
+	var sk, pk = KeyGen()
+	var _, pk2 = KeyGen()
+	b := Transaction{pk, pk2.Hash(), 200, 10000, 1, 0, ""}
 	b.SignTransaction(sk)
 
 	if !b.VerifyTransaction() {
@@ -17,9 +16,3 @@ func TestVerifyTransaction(t *testing.T) {
 	}
 
 }
-
-func initState() {
-	channels := CreateChannelStruct()
-	go transaction.StartTransactionLayer(channels, false)
-
-}
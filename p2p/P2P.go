@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"fmt"
+	"github.com/nfk93/blockchain/consensus"
 	"github.com/nfk93/blockchain/crypto"
 	"github.com/nfk93/blockchain/objects"
 	"io/ioutil"
@@ -19,16 +20,22 @@ const (
 	RPC_NEW_CONNECTION       string = "RPCHandler.NewConnection"
 	RPC_SEND_BLOCK           string = "RPCHandler.SendBlock"
 	RPC_SEND_TRANSDATA       string = "RPCHandler.SendTransData"
+	RPC_GET_BLOCK            string = "RPCHandler.GetBlock"
+	RPC_GET_BLOCKS           string = "RPCHandler.GetBlocks"
+	RPC_GET_CHECKPOINT       string = "RPCHandler.GetCheckpoint"
 
 	NUMBER_OF_PEERS int = 5
+	// SYNC_BATCH_SLOTS is the most slots of blocks fetched with one request when syncing
+	SYNC_BATCH_SLOTS uint64 = 100
 )
 
 // TODO use stringSet for networklist aswell
 var networkList map[string]bool
 var nLock sync.RWMutex
-var peers []rpc.Client
+var peers []*rpc.Client
 var peersLock sync.RWMutex
 var blocksSeen stringSet
+var blocksFetching stringSet // blocks being fetched from our peers, so a block is asked for only once at a time
 var transSeen stringSet
 var myHostPort string
 var myIp string
@@ -36,11 +43,18 @@ var deliverBlock chan objects.Block
 var deliverTrans chan objects.TransData
 var inputBlock chan objects.Block
 var inputTrans chan objects.TransData
+var blockRequests chan string
+var blocksDropped chan string
 var myKey crypto.PublicKey
 var publicKeys map[crypto.PublicKey]bool
 var pkLock sync.RWMutex
 var runLocally bool
 
+// The chain served to our peers, and synced from, is the one of the Consensus layer. Tests serve a chain of their own
+var getBlock = consensus.GetBlock
+var getBlocks = consensus.GetBlocks
+var getCheckpoint = consensus.GetCheckpoint
+
 type stringSet struct {
 	m map[string]bool
 	l sync.RWMutex
@@ -56,6 +70,10 @@ func (b *stringSet) add(s string) {
 	b.m[s] = true
 }
 
+func (b *stringSet) remove(s string) {
+	delete(b.m, s)
+}
+
 func (b *stringSet) lock() {
 	b.l.Lock()
 }
@@ -79,6 +97,7 @@ func (b *stringSet) contains(s string) bool {
 func StartP2P(connectTo string, runLocal bool, hostPort string, mypk crypto.PublicKey, channels objects.ChannelStruct) {
 	networkList = make(map[string]bool)
 	blocksSeen = *newStringSet()
+	blocksFetching = *newStringSet()
 	transSeen = *newStringSet()
 	runLocally = runLocal
 	myIp = getIP()
@@ -87,6 +106,8 @@ func StartP2P(connectTo string, runLocal bool, hostPort string, mypk crypto.Publ
 	deliverTrans = channels.TransFromP2P
 	inputBlock = channels.BlockToP2P
 	inputTrans = channels.TransClientInput
+	blockRequests = channels.BlockRequest
+	blocksDropped = channels.BlockDropped
 	myKey = mypk
 	publicKeys = make(map[crypto.PublicKey]bool)
 
@@ -119,6 +140,22 @@ func StartP2P(connectTo string, runLocal bool, hostPort string, mypk crypto.Publ
 			go handleBlock(block)
 		}
 	}()
+	// Fetch the blocks the Consensus layer is missing from our peers
+	go func() {
+		for {
+			hash := <-blockRequests
+			go fetchBlock(hash)
+		}
+	}()
+	// Forget the blocks the Consensus layer dropped, so they aren't ignored when they arrive again
+	go func() {
+		for {
+			hash := <-blocksDropped
+			blocksSeen.lock()
+			blocksSeen.remove(hash)
+			blocksSeen.unlock()
+		}
+	}()
 }
 
 func PrintNetworkList() {
@@ -157,7 +194,7 @@ func PrintPublicKeys() {
 		}
 	}
 	for _, k := range list {
-		log.Println(k.Hash()[:10])
+		log.Println(k.Address())
 	}
 }
 
@@ -260,7 +297,12 @@ func broadcastNewConnection(data NewConnectionData) {
 	}
 }
 
-func (r *RPCHandler) SendBlock(block objects.Block, _ *struct{}) error {
+// SendBlock receives a block in its canonical encoding, see objects.Block.Encode
+func (r *RPCHandler) SendBlock(data []byte, _ *struct{}) error {
+	block, err := objects.DecodeBlock(data)
+	if err != nil {
+		return err
+	}
 	// Check if we know the peer, and exit early if we do.
 	alreadyKnown := false
 	func() {
@@ -297,7 +339,7 @@ func broadcastBlock(block objects.Block) {
 		defer peersLock.Unlock()
 		for _, peer := range peers {
 			void := struct{}{}
-			err := peer.Call(RPC_SEND_BLOCK, block, &void)
+			err := peer.Call(RPC_SEND_BLOCK, block.Encode(), &void)
 			if err != nil {
 				log.Println("error broadcasting block: ", err.Error())
 				failed = true
@@ -311,7 +353,12 @@ func broadcastBlock(block objects.Block) {
 	}
 }
 
-func (r *RPCHandler) SendTransData(trans objects.TransData, _ *struct{}) error {
+// SendTransData receives a transaction in its canonical encoding, see objects.TransData.Encode
+func (r *RPCHandler) SendTransData(data []byte, _ *struct{}) error {
+	trans, err := objects.DecodeTransData(data)
+	if err != nil {
+		return err
+	}
 	// Check if we know the peer, and exit early if we do.
 	alreadyKnown := false
 	func() {
@@ -348,7 +395,7 @@ func broadcastTrans(trans objects.TransData) {
 		defer peersLock.Unlock()
 		for _, peer := range peers {
 			void := struct{}{}
-			err := peer.Call(RPC_SEND_TRANSDATA, trans, &void)
+			err := peer.Call(RPC_SEND_TRANSDATA, trans.Encode(), &void)
 			if err != nil {
 				log.Println("error broadcasting transaction: ", err.Error())
 				failed = true
@@ -362,6 +409,218 @@ func broadcastTrans(trans objects.TransData) {
 	}
 }
 
+// GetBlock replies with the canonical encoding of the block with the given hash
+func (r *RPCHandler) GetBlock(hash string, reply *[]byte) error {
+	block, exists := getBlock(hash)
+	if !exists {
+		return fmt.Errorf("unknown block %s", hash)
+	}
+	*reply = block.Encode()
+	return nil
+}
+
+type SlotRange struct {
+	From uint64
+	To   uint64
+}
+
+// GetBlocks replies with the canonical encodings of the blocks in the slots of the range, sorted by slot. At most
+// SYNC_BATCH_SLOTS slots are returned, so a node asking for more has to ask again from where the reply ends
+func (r *RPCHandler) GetBlocks(slots SlotRange, reply *[][]byte) error {
+	if slots.To >= slots.From+SYNC_BATCH_SLOTS {
+		slots.To = slots.From + SYNC_BATCH_SLOTS - 1
+	}
+	var encoded [][]byte
+	for _, block := range getBlocks(slots.From, slots.To) {
+		encoded = append(encoded, block.Encode())
+	}
+	*reply = encoded
+	return nil
+}
+
+// GetCheckpoint replies with the last block finalized by this node
+func (r *RPCHandler) GetCheckpoint(_ struct{}, reply *consensus.Checkpoint) error {
+	*reply = getCheckpoint()
+	return nil
+}
+
+// fetchBlock asks our peers for the block with the given hash, and delivers it to the Consensus layer. Its parent is
+// fetched the same way if the Consensus layer is missing that too, so a gap in the chain is filled block by block.
+// The block is delivered even if we have seen it before, as the Consensus layer only asks for blocks it doesn't have
+func fetchBlock(hash string) {
+	// the blocks building on a missing block all ask for it, but it only has to be fetched once
+	blocksFetching.lock()
+	fetching := blocksFetching.contains(hash)
+	blocksFetching.add(hash)
+	blocksFetching.unlock()
+	if fetching {
+		return
+	}
+	defer func() {
+		blocksFetching.lock()
+		defer blocksFetching.unlock()
+		blocksFetching.remove(hash)
+	}()
+
+	// the peers are asked without holding the lock, so a slow peer doesn't hold up broadcasting
+	peersLock.RLock()
+	clients := append([]*rpc.Client(nil), peers...)
+	peersLock.RUnlock()
+	for _, client := range clients {
+		var data []byte
+		if err := client.Call(RPC_GET_BLOCK, hash, &data); err != nil {
+			continue
+		}
+		// a peer can't send another block in place of the one asked for, as it wouldn't have the same hash
+		if b, err := objects.DecodeBlock(data); err == nil && b.CalculateBlockHash() == hash {
+			blocksSeen.lock()
+			blocksSeen.add(hash)
+			blocksSeen.unlock()
+			go func() { deliverBlock <- b }()
+			return
+		}
+	}
+}
+
+// deliverFetchedBlock delivers a block that was fetched instead of broadcast to the Consensus layer, unless it was
+// seen before. It is not broadcast, as our peers have seen it already. Blocks delivered with wait are processed in
+// the order they are delivered
+func deliverFetchedBlock(block objects.Block, wait bool) {
+	blocksSeen.lock()
+	hash := block.CalculateBlockHash()
+	seen := blocksSeen.contains(hash)
+	blocksSeen.add(hash)
+	blocksSeen.unlock()
+	if seen {
+		return
+	}
+	if wait {
+		deliverBlock <- block
+	} else {
+		go func() { deliverBlock <- block }()
+	}
+}
+
+// SyncChain catches up with the chain of a peer before the node takes part in the lottery. It fetches the blocks
+// from the last block we finalized, or from genesis, up to the head of the peer. The slots of the Consensus layer are
+// paused until then, and catch up with the slots that passed once the blocks are delivered. If trustedBlock is set,
+// only peers whose last finalized block builds on the block with that hash are synced from, so the node can't be led
+// onto another chain that doesn't contain a block it knows to be final
+func SyncChain(trustedBlock string) {
+	consensus.SetSyncing(true)
+	defer consensus.SetSyncing(false)
+
+	nLock.RLock()
+	addresses := setAsList(networkList)
+	nLock.RUnlock()
+	sort.Strings(addresses)
+	for _, addr := range addresses {
+		if addr == myIp+":"+myHostPort {
+			continue
+		}
+		checkpoint, err := syncFrom(addr, getCheckpoint().Slot, trustedBlock)
+		if err != nil {
+			log.Println(fmt.Sprintf("Can't sync from %s: %s", addr, err.Error()))
+			continue
+		}
+		log.Println(fmt.Sprintf("Synced to slot %d from %s", checkpoint.HeadSlot, addr))
+		return
+	}
+	log.Println("Couldn't sync with any peer, continuing from the blocks we have")
+}
+
+// syncFrom fetches the blocks of the peer at addr from the slot from and delivers them to the Consensus layer in the
+// order of their slots. Returns the checkpoint of the peer
+func syncFrom(addr string, from uint64, trustedBlock string) (consensus.Checkpoint, error) {
+	client, err := rpc.DialHTTP("tcp", addr)
+	if err != nil {
+		return consensus.Checkpoint{}, err
+	}
+	defer client.Close()
+
+	var checkpoint consensus.Checkpoint
+	if err := client.Call(RPC_GET_CHECKPOINT, struct{}{}, &checkpoint); err != nil {
+		return consensus.Checkpoint{}, err
+	}
+	if checkpoint.BlockHash == "" {
+		return consensus.Checkpoint{}, fmt.Errorf("the peer has no chain")
+	}
+	if trustedBlock != "" {
+		var data []byte
+		if err := client.Call(RPC_GET_BLOCK, trustedBlock, &data); err != nil {
+			return consensus.Checkpoint{}, fmt.Errorf("the peer doesn't have the trusted block")
+		}
+		trusted, err := objects.DecodeBlock(data)
+		if err != nil || trusted.CalculateBlockHash() != trustedBlock {
+			return consensus.Checkpoint{}, fmt.Errorf("the peer sent another block than the trusted block")
+		}
+		// having the block isn't enough, the peer could have it on a fork it abandoned
+		final, err := isAncestor(client, trusted, checkpoint.BlockHash, checkpoint.Slot)
+		if err != nil {
+			return consensus.Checkpoint{}, err
+		}
+		if !final {
+			return consensus.Checkpoint{}, fmt.Errorf("the trusted block isn't on the finalized chain of the peer")
+		}
+	}
+
+	err = fetchBlocks(client, from, checkpoint.HeadSlot, func(block objects.Block) {
+		deliverFetchedBlock(block, true)
+	})
+	if err != nil {
+		return consensus.Checkpoint{}, err
+	}
+	return checkpoint, nil
+}
+
+// isAncestor returns whether ancestor is on the chain of the peer leading up to the block with the given hash and
+// slot. Only the parents of the blocks in between are kept, so it doesn't matter how far apart they are
+func isAncestor(client *rpc.Client, ancestor objects.Block, hash string, slot uint64) (bool, error) {
+	if ancestor.Slot > slot {
+		return false, nil
+	}
+	parents := make(map[string]string)
+	err := fetchBlocks(client, ancestor.Slot, slot, func(block objects.Block) {
+		parents[block.CalculateBlockHash()] = block.ParentPointer
+	})
+	if err != nil {
+		return false, err
+	}
+	// the hash of a block covers its parent pointer, so the peer can't make up the chain between the blocks
+	target := ancestor.CalculateBlockHash()
+	for hash != target {
+		parent, exists := parents[hash]
+		if !exists {
+			return false, nil
+		}
+		hash = parent
+	}
+	return true, nil
+}
+
+// fetchBlocks asks the peer for the blocks with a slot from from to to, both included, SYNC_BATCH_SLOTS slots at a
+// time. The blocks are handled in the order of their slots
+func fetchBlocks(client *rpc.Client, from, to uint64, handle func(block objects.Block)) error {
+	for ; from <= to; from += SYNC_BATCH_SLOTS {
+		last := from + SYNC_BATCH_SLOTS - 1
+		if last > to {
+			last = to
+		}
+		var reply [][]byte
+		if err := client.Call(RPC_GET_BLOCKS, SlotRange{from, last}, &reply); err != nil {
+			return err
+		}
+		for _, data := range reply {
+			block, err := objects.DecodeBlock(data)
+			if err != nil {
+				return err
+			}
+			handle(block)
+		}
+	}
+	return nil
+}
+
 func connectToNetwork(addr string) {
 	client, err := rpc.DialHTTP("tcp", addr)
 	defer client.Close()
@@ -399,7 +658,7 @@ func determinePeers() {
 	if err != nil {
 		log.Fatal("FATAL ERROR, determinePeers: ", err)
 	}
-	peers = make([]rpc.Client, peersSize)
+	peers = make([]*rpc.Client, peersSize)
 	j := 0
 	for i := 0; i < peersSize; i++ {
 		notConnected := true
@@ -412,7 +671,7 @@ func determinePeers() {
 					connections[nextIndex], err.Error(), j))
 			} else {
 				// log.Println("Connected to %s", connections[nextIndex])
-				peers[i] = *peerClient
+				peers[i] = peerClient
 				notConnected = false
 			}
 		}
@@ -2,8 +2,14 @@ package p2p
 
 import (
 	"fmt"
+	"github.com/nfk93/blockchain/consensus"
 	"github.com/nfk93/blockchain/crypto"
 	"github.com/nfk93/blockchain/objects"
+	"net"
+	"net/http"
+	"net/rpc"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 )
@@ -15,7 +21,7 @@ func fail(t *testing.T, expected interface{}, actual interface{}) {
 	}
 }
 
-var _, mockPK = crypto.KeyGen(128)
+var _, mockPK = crypto.KeyGen()
 
 var mockBlock_1 = objects.Block{
 	42,
@@ -26,6 +32,7 @@ var mockBlock_1 = objects.Block{
 	"",
 	objects.BlockData{},
 	"",
+	"",
 	"123",
 }
 
@@ -38,21 +45,26 @@ var mockBlock_2 = objects.Block{
 	"",
 	objects.BlockData{},
 	"",
+	"",
 	"555",
 }
 
 var mockTrans_1 = objects.Transaction{
 	mockPK,
-	mockPK,
+	mockPK.Hash(),
 	103,
-	"id1",
+	10000,
+	1,
+	0,
 	"sign1"}
 
 var mockTrans_2 = objects.Transaction{
 	mockPK,
-	mockPK,
+	mockPK.Hash(),
 	11,
-	"id2",
+	10000,
+	2,
+	0,
 	"sign2"}
 
 /*func TestAll(t *testing.T) {
@@ -71,7 +83,7 @@ func TestRPC(t *testing.T) {
 	resetMockVars()
 	fmt.Println("Running BlocksReceivedOnce_1")
 	t.Run("BlocksReceivedOnce_1", func(t *testing.T) {
-		rpcObj.SendBlock(mockBlock_1, &struct{}{})
+		rpcObj.SendBlock(mockBlock_1.Encode(), &struct{}{})
 		block := <-deliverBlock
 		if block.CalculateBlockHash() != mockBlock_1.CalculateBlockHash() {
 			t.Errorf("First block seen isn't testblock_1")
@@ -79,15 +91,15 @@ func TestRPC(t *testing.T) {
 	})
 	fmt.Println("Running BlocksReceivedOnce_2")
 	t.Run("BlocksReceivedOnce_2", func(t *testing.T) {
-		go rpcObj.SendBlock(mockBlock_2, &struct{}{})
-		go rpcObj.SendBlock(mockBlock_1, &struct{}{})
-		go rpcObj.SendBlock(mockBlock_2, &struct{}{})
-		go rpcObj.SendBlock(mockBlock_1, &struct{}{})
-		go rpcObj.SendBlock(mockBlock_2, &struct{}{})
-		go rpcObj.SendBlock(mockBlock_1, &struct{}{})
-		go rpcObj.SendBlock(mockBlock_2, &struct{}{})
-		go rpcObj.SendBlock(mockBlock_1, &struct{}{})
-		go rpcObj.SendBlock(mockBlock_2, &struct{}{})
+		go rpcObj.SendBlock(mockBlock_2.Encode(), &struct{}{})
+		go rpcObj.SendBlock(mockBlock_1.Encode(), &struct{}{})
+		go rpcObj.SendBlock(mockBlock_2.Encode(), &struct{}{})
+		go rpcObj.SendBlock(mockBlock_1.Encode(), &struct{}{})
+		go rpcObj.SendBlock(mockBlock_2.Encode(), &struct{}{})
+		go rpcObj.SendBlock(mockBlock_1.Encode(), &struct{}{})
+		go rpcObj.SendBlock(mockBlock_2.Encode(), &struct{}{})
+		go rpcObj.SendBlock(mockBlock_1.Encode(), &struct{}{})
+		go rpcObj.SendBlock(mockBlock_2.Encode(), &struct{}{})
 		block := <-deliverBlock
 		if block.CalculateBlockHash() != mockBlock_2.CalculateBlockHash() {
 			t.Error("Second block seen isn't testblock_2")
@@ -106,7 +118,7 @@ func TestRPC(t *testing.T) {
 	fmt.Println("Running SendBlockUpdatesBlocksSeen")
 	t.Run("SendBlockUpdatesBlocksSeen", func(t *testing.T) {
 		rpcObj := new(RPCHandler)
-		rpcObj.SendBlock(mockBlock_1, &struct{}{})
+		rpcObj.SendBlock(mockBlock_1.Encode(), &struct{}{})
 		_ = <-deliverBlock
 		if !blocksSeen.contains(mockBlock_1.CalculateBlockHash()) {
 			t.Fail()
@@ -114,7 +126,7 @@ func TestRPC(t *testing.T) {
 		if blocksSeen.contains(mockBlock_2.CalculateBlockHash()) {
 			t.Fail()
 		}
-		rpcObj.SendBlock(mockBlock_2, &struct{}{})
+		rpcObj.SendBlock(mockBlock_2.Encode(), &struct{}{})
 		_ = <-deliverBlock
 		if !blocksSeen.contains(mockBlock_2.CalculateBlockHash()) {
 			t.Fail()
@@ -126,7 +138,7 @@ func TestRPC(t *testing.T) {
 	fmt.Println("Running TransactionsReceivedOnce_1")
 	t.Run("TransactionsReceivedOnce_1", func(t *testing.T) {
 		mocktransdata1 := objects.TransData{Transaction: mockTrans_1}
-		rpcObj.SendTransData(mocktransdata1, &struct{}{})
+		rpcObj.SendTransData(mocktransdata1.Encode(), &struct{}{})
 		trans := <-deliverTrans
 		if trans.Transaction != mockTrans_1 {
 			t.Errorf("First transaction seen isn't mockTrans_1")
@@ -136,16 +148,16 @@ func TestRPC(t *testing.T) {
 	t.Run("TransactionsReceivedOnce_2", func(t *testing.T) {
 		mocktransdata1 := objects.TransData{Transaction: mockTrans_1}
 		mocktransdata2 := objects.TransData{Transaction: mockTrans_2}
-		go rpcObj.SendTransData(mocktransdata2, &struct{}{})
-		go rpcObj.SendTransData(mocktransdata1, &struct{}{})
-		go rpcObj.SendTransData(mocktransdata2, &struct{}{})
-		go rpcObj.SendTransData(mocktransdata1, &struct{}{})
-		go rpcObj.SendTransData(mocktransdata2, &struct{}{})
-		go rpcObj.SendTransData(mocktransdata1, &struct{}{})
-		go rpcObj.SendTransData(mocktransdata2, &struct{}{})
-		go rpcObj.SendTransData(mocktransdata1, &struct{}{})
-		go rpcObj.SendTransData(mocktransdata2, &struct{}{})
-		go rpcObj.SendTransData(mocktransdata1, &struct{}{})
+		go rpcObj.SendTransData(mocktransdata2.Encode(), &struct{}{})
+		go rpcObj.SendTransData(mocktransdata1.Encode(), &struct{}{})
+		go rpcObj.SendTransData(mocktransdata2.Encode(), &struct{}{})
+		go rpcObj.SendTransData(mocktransdata1.Encode(), &struct{}{})
+		go rpcObj.SendTransData(mocktransdata2.Encode(), &struct{}{})
+		go rpcObj.SendTransData(mocktransdata1.Encode(), &struct{}{})
+		go rpcObj.SendTransData(mocktransdata2.Encode(), &struct{}{})
+		go rpcObj.SendTransData(mocktransdata1.Encode(), &struct{}{})
+		go rpcObj.SendTransData(mocktransdata2.Encode(), &struct{}{})
+		go rpcObj.SendTransData(mocktransdata1.Encode(), &struct{}{})
 		trans := <-deliverTrans
 		if trans.Transaction != mockTrans_2 {
 			t.Error("Second transaction seen isn't mocktransdata2")
@@ -173,3 +185,208 @@ func resetMockVars() {
 	myHostPort = "65000"
 	networkList["127.0.0.1:65000"] = true
 }
+
+// mockChain is the chain
+//
+//	g - a - b - c
+//	     \
+//	      x
+//
+// where b is the last finalized block. Blocks are named by their draw
+var mockChain = func() []objects.Block {
+	g := objects.Block{Slot: 0, Draw: "g"}
+	a := objects.Block{Slot: 1, ParentPointer: g.CalculateBlockHash(), Draw: "a"}
+	b := objects.Block{Slot: 2, ParentPointer: a.CalculateBlockHash(), Draw: "b"}
+	c := objects.Block{Slot: 3, ParentPointer: b.CalculateBlockHash(), Draw: "c"}
+	x := objects.Block{Slot: 2, ParentPointer: a.CalculateBlockHash(), Draw: "x"}
+	return []objects.Block{g, a, b, x, c}
+}()
+
+func mockBlock(draw string) objects.Block {
+	for _, b := range mockChain {
+		if b.Draw == draw {
+			return b
+		}
+	}
+	panic("no mock block " + draw)
+}
+
+func mockHash(draw string) string {
+	b := mockBlock(draw)
+	return b.CalculateBlockHash()
+}
+
+// serveMockChain serves mockChain over RPC, and returns the address of the server and a count of the GetBlock calls
+func serveMockChain(t *testing.T) (string, *int64) {
+	calls := new(int64)
+	var callsLock sync.Mutex
+	getBlock = func(hash string) (objects.Block, bool) {
+		callsLock.Lock()
+		*calls++
+		callsLock.Unlock()
+		time.Sleep(100 * time.Millisecond)
+		for _, b := range mockChain {
+			if b.CalculateBlockHash() == hash {
+				return b, true
+			}
+		}
+		return objects.Block{}, false
+	}
+	getBlocks = func(from, to uint64) []objects.Block {
+		var result []objects.Block
+		for _, b := range mockChain {
+			if b.Slot >= from && b.Slot <= to {
+				result = append(result, b)
+			}
+		}
+		return result
+	}
+	getCheckpoint = func() consensus.Checkpoint {
+		return consensus.Checkpoint{Epoch: 1, BlockHash: mockHash("b"), Slot: 2, HeadSlot: 3}
+	}
+
+	server := rpc.NewServer()
+	if err := server.Register(new(RPCHandler)); err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go http.Serve(ln, server)
+	return ln.Addr().String(), calls
+}
+
+func TestGetBlocks(t *testing.T) {
+	serveMockChain(t)
+	var reply [][]byte
+	if err := new(RPCHandler).GetBlocks(SlotRange{1, 2}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	var draws []string
+	for _, data := range reply {
+		b, err := objects.DecodeBlock(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		draws = append(draws, b.Draw)
+	}
+	sort.Strings(draws)
+	if fmt.Sprint(draws) != "[a b x]" {
+		t.Errorf("Expected the blocks of slot 1 and 2, got %v", draws)
+	}
+
+	// a range of more than SYNC_BATCH_SLOTS slots is cut short
+	var asked SlotRange
+	getBlocks = func(from, to uint64) []objects.Block {
+		asked = SlotRange{from, to}
+		return nil
+	}
+	new(RPCHandler).GetBlocks(SlotRange{5, 5 + 10*SYNC_BATCH_SLOTS}, &reply)
+	if asked.From != 5 || asked.To != 5+SYNC_BATCH_SLOTS-1 {
+		t.Errorf("Expected slots 5 to %d to be looked up, got %v", 5+SYNC_BATCH_SLOTS-1, asked)
+	}
+}
+
+func TestGetCheckpoint(t *testing.T) {
+	serveMockChain(t)
+	var checkpoint consensus.Checkpoint
+	new(RPCHandler).GetCheckpoint(struct{}{}, &checkpoint)
+	if checkpoint != getCheckpoint() {
+		t.Errorf("Expected the checkpoint of the Consensus layer, got %v", checkpoint)
+	}
+}
+
+// syncMockChain syncs from the mock chain, and returns the draws of the blocks delivered in the order they were
+func syncMockChain(t *testing.T, from uint64, trustedBlock string) ([]string, error) {
+	addr, _ := serveMockChain(t)
+	resetMockVars()
+	deliverBlock = make(chan objects.Block, len(mockChain))
+	_, err := syncFrom(addr, from, trustedBlock)
+	close(deliverBlock)
+	var draws []string
+	for b := range deliverBlock {
+		draws = append(draws, b.Draw)
+	}
+	return draws, err
+}
+
+func TestSyncFrom(t *testing.T) {
+	draws, err := syncMockChain(t, 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(draws) != 4 || draws[0] != "a" || draws[3] != "c" {
+		t.Errorf("Expected the blocks from slot 1 in the order of their slots, got %v", draws)
+	}
+	// a block seen before isn't delivered again
+	blocksSeen.add(mockHash("c"))
+	deliverBlock = make(chan objects.Block, 1)
+	deliverFetchedBlock(mockBlock("c"), true)
+	if len(deliverBlock) != 0 {
+		t.Error("A block that was seen before shouldn't be delivered")
+	}
+}
+
+func TestSyncFromTrustedBlock(t *testing.T) {
+	if _, err := syncMockChain(t, 0, mockHash("a")); err != nil {
+		t.Errorf("Block a is an ancestor of the finalized block, so it should be synced from: %v", err)
+	}
+	if _, err := syncMockChain(t, 0, mockHash("c")); err == nil {
+		t.Error("Block c isn't finalized yet, so the peer shouldn't be synced from")
+	}
+	draws, err := syncMockChain(t, 0, mockHash("x"))
+	if err == nil || len(draws) != 0 {
+		t.Error("Block x is on an abandoned fork, so the peer shouldn't be synced from")
+	}
+	if _, err := syncMockChain(t, 0, "unknown"); err == nil {
+		t.Error("A peer without the trusted block shouldn't be synced from")
+	}
+}
+
+func TestFetchBlock(t *testing.T) {
+	addr, calls := serveMockChain(t)
+	resetMockVars()
+	blocksFetching = *newStringSet()
+	client, err := rpc.DialHTTP("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	peersLock.Lock()
+	peers = []*rpc.Client{client}
+	peersLock.Unlock()
+	defer func() { peers = nil }()
+
+	// the blocks building on a missing block all ask for it at once
+	hash := mockHash("b")
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fetchBlock(hash)
+		}()
+	}
+	wg.Wait()
+	if *calls != 1 {
+		t.Errorf("A block being fetched should only be asked for once, it was asked for %d times", *calls)
+	}
+	if b := <-deliverBlock; b.CalculateBlockHash() != hash {
+		t.Error("The fetched block should be delivered")
+	}
+	if !blocksSeen.contains(hash) || blocksFetching.contains(hash) {
+		t.Error("A fetched block should be seen, and not be fetching anymore")
+	}
+
+	// a block seen before is fetched again when the Consensus layer asks for it
+	fetchBlock(hash)
+	select {
+	case b := <-deliverBlock:
+		if b.CalculateBlockHash() != hash {
+			t.Error("The block asked for should be delivered")
+		}
+	case <-time.After(time.Second):
+		t.Error("A block that was seen before should be delivered when it is asked for")
+	}
+}
@@ -1,7 +1,9 @@
 package smart
 
 import (
+	"fmt"
 	"github.com/nfk93/blockchain/smart/interpreter/ast"
+	"github.com/nfk93/blockchain/smart/interpreter/value"
 )
 
 type contract struct {
@@ -9,3 +11,31 @@ type contract struct {
 	tabs          ast.TypedExp
 	CreatedAtSlot uint64
 }
+
+// encode returns how a deployed contract is stored: its code and the slot it was created in, as a tuple written with
+// value.Encode, so the record carries the version of the encoding
+func (c contract) encode() []byte {
+	data, err := value.Encode(value.TupleVal{Values: []value.Value{value.StringVal{Value: c.Code},
+		value.NatVal{Value: c.CreatedAtSlot}}})
+	if err != nil {
+		// strings and nats can always be encoded
+		panic(fmt.Sprintf("can't encode contract: %s", err.Error()))
+	}
+	return data
+}
+
+// decodeContract is the inverse of contract.encode. The typed AST of the contract isn't stored
+func decodeContract(data []byte) (contract, error) {
+	v, err := value.Decode(data)
+	if err != nil {
+		return contract{}, err
+	}
+	if tuple, ok := v.(value.TupleVal); ok && len(tuple.Values) == 2 {
+		code, isString := tuple.Values[0].(value.StringVal)
+		slot, isNat := tuple.Values[1].(value.NatVal)
+		if isString && isNat {
+			return contract{Code: code.Value, CreatedAtSlot: slot.Value}, nil
+		}
+	}
+	return contract{}, fmt.Errorf("stored contract isn't a tuple of its code and slot")
+}
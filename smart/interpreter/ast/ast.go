@@ -62,7 +62,7 @@ type TopLevel struct {
 
 func NewRoot(e interface{}) (Exp, error) {
 	switch e.(type) {
-	case TypeDecl, EntryExpression, StorageInitExp:
+	case TypeDecl, EntryExpression, StorageInitExp, HelperDecl, ImportDecl:
 		return TopLevel{[]Exp{e.(Exp)}}, nil
 	default:
 		ex, _ := fail(fmt.Sprintf("Toplevel error, New root can't be type %T", e))
@@ -595,6 +595,58 @@ func NewStorageInitExp(id string, exp interface{}) (Exp, error) {
 	return StorageInitExp{exp.(Exp)}, nil
 }
 
+/* HelperDecl */
+type HelperDecl struct {
+	Id     string
+	Params Pattern
+	Body   Exp
+}
+
+func (e HelperDecl) String() string {
+	return fmt.Sprintf("HelperDecl(Id: %s, Params: %s, Body: %s)", e.Id, e.Params.String(), e.Body.String())
+}
+
+func NewHelperDecl(id string, params, body interface{}) (Exp, error) {
+	return HelperDecl{id, params.(Pattern), body.(Exp)}, nil
+}
+
+/* ImportDecl */
+// Imports the types and helpers of a library as module ModId. The library is either the contract deployed at Address,
+// a file, or given inline. Only inline libraries are type checked, so imports of addresses and files have to be
+// resolved to inline imports first.
+type ImportDecl struct {
+	ModId   string
+	Address string
+	File    string
+	Library Exp
+}
+
+func (e ImportDecl) String() string {
+	switch {
+	case e.Library != nil:
+		return fmt.Sprintf("ImportDecl(ModId: %s, Address: %s, Library: %s)", e.ModId, e.Address, e.Library.String())
+	case e.Address != "":
+		return fmt.Sprintf("ImportDecl(ModId: %s, Address: %s)", e.ModId, e.Address)
+	default:
+		return fmt.Sprintf("ImportDecl(ModId: %s, File: %s)", e.ModId, e.File)
+	}
+}
+
+func NewAddressImport(modid string, address string, err error) (Exp, error) {
+	if err != nil {
+		return nil, err
+	}
+	return ImportDecl{modid, address, "", nil}, nil
+}
+
+func NewFileImport(modid string, file string) (Exp, error) {
+	return ImportDecl{modid, "", file, nil}, nil
+}
+
+func NewInlineImport(modid string, library interface{}) (Exp, error) {
+	return ImportDecl{modid, "", "", library.(Exp)}, nil
+}
+
 // ---------------------------
 
 func NewExpList(exp1, exp2 interface{}) ([]Exp, error) {
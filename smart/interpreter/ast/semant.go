@@ -31,7 +31,8 @@ func InitialVarEnv() VarEnv {
 	initmap := ps.NewMap()
 	i1 := initmap.Set("Current", GenerateCurrentModule())
 	i2 := i1.Set("Contract", GenerateContractModule())
-	return i2.Set("Account", GenerateAccountModule())
+	i3 := i2.Set("Account", GenerateAccountModule())
+	return i3.Set("Map", GenerateMapModule())
 }
 
 func InitialStructEnv() StructEnv {
@@ -55,6 +56,27 @@ func AddTypes(exp Exp, gas uint64) (texp TypedExp, err_ error, remainingGas uint
 	return texp, err, gas
 }
 
+// AddTypesWithEnvs types exp in the given environments, and returns the environments extended with the declarations
+// of exp. It is used to type declarations and expressions one at a time, as in the REPL.
+func AddTypesWithEnvs(
+	exp Exp,
+	venv VarEnv,
+	tenv TypeEnv,
+	senv StructEnv,
+	gas uint64,
+) (texp TypedExp, venv_ VarEnv, tenv_ TypeEnv, senv_ StructEnv, remainingGas uint64, err_ error) {
+	defer func() {
+		if err := recover(); err != nil {
+			texp = TypedExp{ErrorExpression{}, ErrorType{"out of gas!"}}
+			venv_, tenv_, senv_ = venv, tenv, senv
+			err_ = fmt.Errorf("ran out of gas building AST")
+			remainingGas = 0
+		}
+	}()
+
+	return addTypes(exp, venv, tenv, senv, gas)
+}
+
 func checkForErrorTypes(texp_ Exp) bool {
 	switch texp_.(type) {
 	case TypedExp:
@@ -124,6 +146,12 @@ func checkForErrorTypes(texp_ Exp) bool {
 	case StorageInitExp:
 		e := e.(StorageInitExp)
 		return checkForErrorTypes(e.Exp)
+	case HelperDecl:
+		e := e.(HelperDecl)
+		return checkForErrorTypes(e.Body)
+	case ImportDecl:
+		e := e.(ImportDecl)
+		return checkForErrorTypes(e.Library)
 	case StructLit:
 		e := e.(StructLit)
 		for _, v := range e.Vals {
@@ -156,12 +184,14 @@ func GenerateCurrentModule() StructType {
 	amount := StructField{"amount", LambdaType{[]Type{UnitType{}}, KoinType{}}}
 	gas := StructField{"gas", LambdaType{[]Type{UnitType{}}, NatType{}}}
 	failwith := StructField{"failwith", LambdaType{[]Type{StringType{}}, UnitType{}}}
-	return StructType{[]StructField{balance, amount, gas, failwith}}
+	sender := StructField{"sender", LambdaType{[]Type{UnitType{}}, KeyType{}}}
+	return StructType{[]StructField{balance, amount, gas, failwith, sender}}
 }
 
 func GenerateContractModule() StructType {
 	call := StructField{"call", LambdaType{[]Type{AddressType{}, KoinType{}, StringType{}, GenericType{}}, OperationType{}}}
-	return StructType{[]StructField{call}}
+	schedule := StructField{"schedule", LambdaType{[]Type{NatType{}, AddressType{}, StringType{}, GenericType{}}, OperationType{}}}
+	return StructType{[]StructField{call, schedule}}
 }
 
 func GenerateAccountModule() StructType {
@@ -170,6 +200,123 @@ func GenerateAccountModule() StructType {
 	return StructType{[]StructField{transfer, default_}}
 }
 
+// The Map functions are typed generically here. The actual key and value types are inferred from the
+// arguments when the function is called, see typeMapCall
+func GenerateMapModule() StructType {
+	generic := MapType{GenericType{}, GenericType{}}
+	empty := StructField{"empty", LambdaType{[]Type{UnitType{}}, generic}}
+	mem := StructField{"mem", LambdaType{[]Type{GenericType{}, generic}, BoolType{}}}
+	find := StructField{"find", LambdaType{[]Type{GenericType{}, generic}, GenericType{}}}
+	add := StructField{"add", LambdaType{[]Type{GenericType{}, GenericType{}, generic}, generic}}
+	remove := StructField{"remove", LambdaType{[]Type{GenericType{}, generic}, generic}}
+	size := StructField{"size", LambdaType{[]Type{generic}, NatType{}}}
+	return StructType{[]StructField{empty, mem, find, add, remove, size}}
+}
+
+// helperVarEnv removes everything with side effects from the variable environment of a helper body
+func helperVarEnv(venv VarEnv) VarEnv {
+	failwith := StructField{"failwith", LambdaType{[]Type{StringType{}}, UnitType{}}}
+	return venv.Delete("Contract").Delete("Account").Set("Current", StructType{[]StructField{failwith}})
+}
+
+// typeLibrary types the types, helpers and imports of a library in fresh environments, and returns the library with
+// its module type, i.e. a struct of the helper types. Entries and storage are ignored for libraries deployed as
+// contracts, but not allowed in inline libraries, as they could never be called.
+func typeLibrary(library Exp, deployed bool, gas uint64) (TypedExp, TypeEnv, StructEnv, uint64, error) {
+	venv, tenv, senv := GenInitEnvs()
+	roots := make([]Exp, 0)
+	fields := make([]StructField, 0)
+	for _, root := range library.(TopLevel).Roots {
+		switch root.(type) {
+		case TypeDecl, HelperDecl, ImportDecl:
+			texp, venv_, tenv_, senv_, gas_, err := addTypes(root, venv, tenv, senv, gas)
+			venv, tenv, senv, gas = venv_, tenv_, senv_, gas_
+			roots = append(roots, texp)
+			if err != nil {
+				return TypedExp{TopLevel{roots}, ErrorType{err.Error()}}, tenv, senv, gas, err
+			}
+			if helper, ok := root.(HelperDecl); ok {
+				if _, exists := (StructType{fields}).FindFieldType(helper.Id); exists {
+					err := fmt.Sprintf("helper %s already declared", helper.Id)
+					return TypedExp{TopLevel{roots}, ErrorType{err}}, tenv, senv, gas, fmt.Errorf(err)
+				}
+				fields = append(fields, StructField{helper.Id, texp.Type})
+			}
+		case EntryExpression, StorageInitExp:
+			if !deployed {
+				err := "libraries can only declare types, helpers and imports"
+				return TypedExp{TopLevel{roots}, ErrorType{err}}, tenv, senv, gas, fmt.Errorf(err)
+			}
+		default:
+			err := "unexpected expression in library"
+			return TypedExp{TopLevel{roots}, ErrorType{err}}, tenv, senv, gas, fmt.Errorf(err)
+		}
+	}
+	return TypedExp{TopLevel{roots}, StructType{fields}}, tenv, senv, gas, nil
+}
+
+func isComparableType(typ Type) bool {
+	switch typ.Type() {
+	case STRING, INT, KEY, NAT, BOOL, KOIN, ADDRESS, GENERIC:
+		return true
+	default:
+		return false
+	}
+}
+
+// picks the non-generic type of the two, if any
+func mostSpecificType(typ1, typ2 Type) Type {
+	if typ1.Type() == GENERIC {
+		return typ2
+	}
+	return typ1
+}
+
+// typeMapCall checks a call to a function of the Map module, and returns the return type of the call with
+// the key and value types of the map argument filled in
+func typeMapCall(fieldId string, args []TypedExp) (Type, error) {
+	switch fieldId {
+	case "empty":
+		return MapType{GenericType{}, GenericType{}}, nil
+	case "size":
+		return NatType{}, nil
+	}
+	key := args[0].Type
+	maparg := args[len(args)-1].Type
+	if maparg.Type() != MAP {
+		return ErrorType{"expected map argument"}, fmt.Errorf("last argument of Map.%s must be a map, but was %s",
+			fieldId, maparg.String())
+	}
+	maptyp := maparg.(MapType)
+	if !isComparableType(key) {
+		err := fmt.Sprintf("values of type %s can't be used as map keys", key.String())
+		return ErrorType{err}, fmt.Errorf(err)
+	}
+	if !checkTypesEqual(key, maptyp.KeyTyp) {
+		err := fmt.Sprintf("key of type %s doesn't match map key type %s", key.String(), maptyp.KeyTyp.String())
+		return ErrorType{err}, fmt.Errorf(err)
+	}
+	keytyp := mostSpecificType(maptyp.KeyTyp, key)
+	switch fieldId {
+	case "mem":
+		return BoolType{}, nil
+	case "find":
+		return maptyp.ValTyp, nil
+	case "remove":
+		return MapType{keytyp, maptyp.ValTyp}, nil
+	case "add":
+		val := args[1].Type
+		if !checkTypesEqual(val, maptyp.ValTyp) {
+			err := fmt.Sprintf("value of type %s doesn't match map value type %s", val.String(), maptyp.ValTyp.String())
+			return ErrorType{err}, fmt.Errorf(err)
+		}
+		return MapType{keytyp, mostSpecificType(maptyp.ValTyp, val)}, nil
+	default:
+		err := fmt.Sprintf("No field in module Map with name %s", fieldId)
+		return ErrorType{err}, fmt.Errorf(err)
+	}
+}
+
 func lookupType(id string, tenv TypeEnv) Type {
 	val, contained := tenv.Lookup(id)
 	if contained {
@@ -220,6 +367,14 @@ func translateType(typ Type, tenv TypeEnv, gas uint64) (Type, uint64) {
 			typs[i], gas = translateType(t, tenv, gas)
 		}
 		return TupleType{typs}, gas
+	case MAP:
+		typ := typ.(MapType)
+		keytype, gas := translateType(typ.KeyTyp, tenv, gas)
+		valtype, gas := translateType(typ.ValTyp, tenv, gas)
+		if !isComparableType(keytype) {
+			return ErrorType{fmt.Sprintf("values of type %s can't be used as map keys", keytype.String())}, gas
+		}
+		return MapType{keytype, valtype}, gas
 	case STRUCT:
 		typ := typ.(StructType)
 		fields := make([]StructField, 0)
@@ -292,6 +447,15 @@ func checkTypesEqual(typ1, typ2 Type) bool {
 		default:
 			return false
 		}
+	case MAP:
+		switch typ2.Type() {
+		case MAP:
+			typ1 := typ1.(MapType)
+			typ2 := typ2.(MapType)
+			return checkTypesEqual(typ1.KeyTyp, typ2.KeyTyp) && checkTypesEqual(typ1.ValTyp, typ2.ValTyp)
+		default:
+			return false
+		}
 	case STRUCT:
 		switch typ2.Type() {
 		case STRUCT:
@@ -456,9 +620,16 @@ func addTypes(
 				if err != nil {
 					return TypedExp{TopLevel{roots}, ErrorType{err.Error()}}, venv, tenv, senv, gas, err
 				}
+			case HelperDecl, ImportDecl:
+				texp_, venv_, tenv_, senv_, gas_, err := addTypes(exp1, venv, tenv, senv, gas)
+				texp, venv, tenv, senv, gas = texp_, venv_, tenv_, senv_, gas_
+				roots = append(roots, texp)
+				if err != nil {
+					return TypedExp{TopLevel{roots}, ErrorType{err.Error()}}, venv, tenv, senv, gas, err
+				}
 			default:
-				roots = append(roots, TypedExp{ErrorExpression{"can only have entries, typedecls and storageinits in toplevel"}, ErrorType{}})
-				return TypedExp{TopLevel{roots}, ErrorType{}}, venv, tenv, senv, gas, fmt.Errorf("can only have entries, typedecls and storageinits in toplevel")
+				roots = append(roots, TypedExp{ErrorExpression{"can only have entries, typedecls, helpers, imports and storageinits in toplevel"}, ErrorType{}})
+				return TypedExp{TopLevel{roots}, ErrorType{}}, venv, tenv, senv, gas, fmt.Errorf("can only have entries, typedecls, helpers, imports and storageinits in toplevel")
 			}
 		}
 		if storageDefined && storageInitialized && mainEntryDefined {
@@ -690,6 +861,10 @@ func addTypes(
 				venv, tenv, senv, gas, fmt.Errorf(err)
 		}
 		actualType, gas := translateType(exp.Typ, tenv, gas)
+		if actualType.Type() == ERROR {
+			err := actualType.(ErrorType).err
+			return TypedExp{exp, actualType}, venv, tenv, senv, gas, fmt.Errorf(err)
+		}
 		switch exp.Typ.Type() {
 		case STRUCT:
 			actualType := actualType.(StructType)
@@ -892,6 +1067,17 @@ func addTypes(
 			}
 			texps = append(texps, argument)
 		}
+		if lookup, ok := exp.ExpList[0].(ModuleLookupExp); ok && lookup.ModId == "Map" {
+			args := make([]TypedExp, 0)
+			for _, e := range texps[1:] {
+				args = append(args, e.(TypedExp))
+			}
+			returntype, err := typeMapCall(lookup.FieldId, args)
+			if err != nil {
+				return TypedExp{ErrorExpression{exp.String()}, ErrorType{err.Error()}}, venv, tenv, senv, gas, err
+			}
+			return TypedExp{CallExp{texps}, returntype}, venv, tenv, senv, gas, nil
+		}
 		return TypedExp{CallExp{texps}, lambdatype.ReturnType}, venv, tenv, senv, gas, nil
 	case LetExp:
 		exp := exp.(LetExp)
@@ -1120,6 +1306,84 @@ func addTypes(
 			return TypedExp{StorageInitExp{texp}, ErrorType{err}}, venv, tenv, senv, gas, fmt.Errorf(err)
 		}
 		return TypedExp{StorageInitExp{texp}, UnitType{}}, venv, tenv, senv, gas, nil
+	case HelperDecl:
+		exp := exp.(HelperDecl)
+		// helpers are pure, so their bodies can only use imported modules, Map and Current.failwith
+		venv_ := helperVarEnv(venv)
+		paramlist := make([]Param, 0)
+		argtypes := make([]Type, 0)
+		for _, v := range exp.Params.Params {
+			if v.Anno.Opt != true {
+				err := fmt.Sprintf("unannotated parameter of helper %s can't be inferred", exp.Id)
+				return TypedExp{ErrorExpression{}, ErrorType{err}}, venv, tenv, senv, gas, fmt.Errorf(err)
+			}
+			vartyp, gas_ := translateType(v.Anno.Typ, tenv, gas)
+			gas = gas_
+			if vartyp.Type() == ERROR {
+				err := vartyp.(ErrorType).err
+				return TypedExp{ErrorExpression{}, vartyp}, venv, tenv, senv, gas, fmt.Errorf(err)
+			}
+			venv_ = venv_.Set(v.Id, vartyp)
+			paramlist = append(paramlist, Param{v.Id, TypeOption{true, vartyp}})
+			argtypes = append(argtypes, vartyp)
+		}
+		if len(argtypes) == 0 {
+			argtypes = append(argtypes, UnitType{})
+		}
+		body, _, _, _, gas, err := addTypes(exp.Body, venv_, tenv, senv, gas)
+		if err != nil {
+			return TypedExp{HelperDecl{exp.Id, Pattern{paramlist}, body}, ErrorType{err.Error()}},
+				venv, tenv, senv, gas, err
+		}
+		return TypedExp{HelperDecl{exp.Id, Pattern{paramlist}, body}, LambdaType{argtypes, body.Type}},
+			venv, tenv, senv, gas, nil
+	case ImportDecl:
+		exp := exp.(ImportDecl)
+		if exp.Library == nil {
+			var err string
+			if exp.Address != "" {
+				err = fmt.Sprintf("library %s imported as %s could not be resolved", exp.Address, exp.ModId)
+			} else {
+				err = fmt.Sprintf("file import %s has to be bundled before the contract is deployed", exp.File)
+			}
+			return TypedExp{exp, ErrorType{err}}, venv, tenv, senv, gas, fmt.Errorf(err)
+		}
+		if lookupVar(exp.ModId, venv) != nil {
+			err := fmt.Sprintf("module %s already exists", exp.ModId)
+			return TypedExp{exp, ErrorType{err}}, venv, tenv, senv, gas, fmt.Errorf(err)
+		}
+		library, libtenv, libsenv, gas, err := typeLibrary(exp.Library, exp.Address != "", gas)
+		texp := ImportDecl{exp.ModId, exp.Address, exp.File, library}
+		if err != nil {
+			return TypedExp{texp, ErrorType{err.Error()}}, venv, tenv, senv, gas, err
+		}
+		// export the types declared by the library as ModId.typename
+		for _, root := range library.Exp.(TopLevel).Roots {
+			typedecl, ok := root.(TypedExp).Exp.(TypeDecl)
+			if !ok {
+				continue
+			}
+			id := exp.ModId + "." + typedecl.Id
+			if lookupType(id, tenv) != nil {
+				err := fmt.Sprintf("type %s already declared", id)
+				return TypedExp{texp, ErrorType{err}}, venv, tenv, senv, gas, fmt.Errorf(err)
+			}
+			tenv = tenv.Set(id, lookupType(typedecl.Id, libtenv))
+		}
+		// struct literals of library types have to be usable by the importer too
+		var mergeErr error
+		libsenv.ForEach(func(fields string, typ interface{}) {
+			existing := lookupStruct(fields, senv)
+			if existing == nil {
+				senv = senv.Set(fields, typ)
+			} else if !checkTypesEqual(existing, typ.(Type)) && mergeErr == nil {
+				mergeErr = fmt.Errorf("struct field names of library %s already used", exp.ModId)
+			}
+		})
+		if mergeErr != nil {
+			return TypedExp{texp, ErrorType{mergeErr.Error()}}, venv, tenv, senv, gas, mergeErr
+		}
+		return TypedExp{texp, UnitType{}}, venv.Set(exp.ModId, library.Type), tenv, senv, gas, nil
 	default:
 		texp, venv, tenv, senv := todo(exp, venv, tenv, senv)
 		return texp, venv, tenv, senv, gas, fmt.Errorf("unknown expression in semant check, unexpected error")
@@ -27,6 +27,7 @@ const (
 	UNIT
 	OPTION
 	ADDRESS
+	MAP
 	LAMBDA
 	GENERIC
 	ERROR
@@ -159,6 +160,22 @@ func NewListType(typ interface{}) ListType {
 	return ListType{typ.(Type)}
 }
 
+/* MapType */
+type MapType struct {
+	KeyTyp Type
+	ValTyp Type
+}
+
+func (t MapType) Type() Typecode {
+	return MAP
+}
+func (t MapType) String() string {
+	return fmt.Sprintf("(%s, %s) map", t.KeyTyp.String(), t.ValTyp.String())
+}
+func NewMapType(keytyp, valtyp interface{}) MapType {
+	return MapType{keytyp.(Type), valtyp.(Type)}
+}
+
 type UnitType struct{}
 
 func (t UnitType) Type() Typecode {
@@ -2,11 +2,11 @@ package interpreter
 
 import (
 	"fmt"
-	"github.com/mndrix/ps"
 	. "github.com/nfk93/blockchain/smart/interpreter/ast"
 	"github.com/nfk93/blockchain/smart/interpreter/lexer"
 	"github.com/nfk93/blockchain/smart/interpreter/parser"
 	"github.com/nfk93/blockchain/smart/interpreter/value"
+	"math"
 	"strconv"
 )
 
@@ -17,8 +17,12 @@ type PanicStruct struct {
 
 var currentAmt uint64
 var currentBal uint64
+var currentSender string
 var spentsofar uint64
 
+// ScheduleDeposit is reserved from the contract balance for every scheduled call, and pays the gas of the call
+const ScheduleDeposit = uint64(200000)
+
 func todo(n int, gas uint64) value.Value {
 	interpPanic("Hit todo nr. "+strconv.Itoa(n), gas)
 	return value.UnitVal{}
@@ -40,6 +44,10 @@ func currentAmount() value.KoinVal {
 	return value.KoinVal{currentAmt}
 }
 
+func currentSenderKey() value.KeyVal {
+	return value.KeyVal{currentSender}
+}
+
 func currentFailWith(failmessage value.StringVal, gas uint64) value.OperationVal {
 	interpPanic(failmessage.Value, gas)
 	return value.OperationVal{value.FailWith{failmessage.Value}}
@@ -53,6 +61,14 @@ func contractCall(address value.AddressVal, amount value.KoinVal, entry value.St
 	return value.OperationVal{value.ContractCall{address.Value, amount.Value, entry.Value, param}}
 }
 
+func contractSchedule(slot value.NatVal, address value.AddressVal, entry value.StringVal, param value.Value, gas uint64) value.OperationVal {
+	spentsofar = spentsofar + ScheduleDeposit
+	if int64(currentBal+currentAmt)-int64(spentsofar) < 0 {
+		interpPanic("contract spendings exceed contract balance", gas)
+	}
+	return value.OperationVal{value.Schedule{slot.Value, address.Value, entry.Value, param, ScheduleDeposit}}
+}
+
 func accountTransfer(key value.KeyVal, amount value.KoinVal, gas uint64) value.OperationVal {
 	spentsofar = spentsofar + amount.Value
 	if int64(currentBal+currentAmt)-int64(spentsofar) < 0 {
@@ -65,6 +81,43 @@ func accountDefault(key value.KeyVal) value.AddressVal {
 	return value.AddressVal{"dummy address"} //TODO add proper functionality
 }
 
+func mapEmpty() value.MapVal {
+	return value.MapVal{make(map[value.Value]value.Value)}
+}
+
+func mapMem(key value.Value, m value.MapVal) value.BoolVal {
+	_, exists := m.Values[key]
+	return value.BoolVal{exists}
+}
+
+func mapFind(key value.Value, m value.MapVal, gas uint64) value.Value {
+	val, exists := m.Values[key]
+	if !exists {
+		interpPanic("key not found in map", gas)
+	}
+	return val
+}
+
+// maps are values, so adding and removing builds a new map instead of changing the given one
+func mapAdd(key, val value.Value, m value.MapVal) value.MapVal {
+	newmap := make(map[value.Value]value.Value)
+	for k, v := range m.Values {
+		newmap[k] = v
+	}
+	newmap[key] = val
+	return value.MapVal{newmap}
+}
+
+func mapRemove(key value.Value, m value.MapVal) value.MapVal {
+	newmap := make(map[value.Value]value.Value)
+	for k, v := range m.Values {
+		if k != key {
+			newmap[k] = v
+		}
+	}
+	return value.MapVal{newmap}
+}
+
 func lookupVar(id string, venv VarEnv) value.Value {
 	val, contained := venv.Lookup(id)
 	if contained {
@@ -74,7 +127,17 @@ func lookupVar(id string, venv VarEnv) value.Value {
 	}
 }
 
-func InitiateContract(contractCode []byte, gas uint64) (texp TypedExp, initstor value.Value, remainingGas uint64, returnErr error) {
+func InitiateContract(contractCode []byte, sender string, gas uint64) (texp TypedExp, initstor value.Value, remainingGas uint64, returnErr error) {
+	return InitiateContractWithLibraries(contractCode, sender, gas, nil)
+}
+
+// InitiateContractWithLibraries initiates a contract, resolving imports of deployed libraries with libraries
+func InitiateContractWithLibraries(
+	contractCode []byte,
+	sender string,
+	gas uint64,
+	libraries LibraryResolver,
+) (texp TypedExp, initstor value.Value, remainingGas uint64, returnErr error) {
 	defer func() {
 		if err := recover(); err != nil {
 			err := err.(PanicStruct)
@@ -88,6 +151,7 @@ func InitiateContract(contractCode []byte, gas uint64) (texp TypedExp, initstor
 
 	currentBal = 0
 	currentAmt = 0
+	currentSender = sender
 	spentsofar = 0
 
 	// initial gas cost
@@ -102,7 +166,11 @@ func InitiateContract(contractCode []byte, gas uint64) (texp TypedExp, initstor
 	if err != nil {
 		return TypedExp{}, value.UnitVal{}, gas, fmt.Errorf("syntax error in contract code: %s", err.Error())
 	}
-	texp, err, gas = AddTypes(par.(Exp), gas)
+	contractExp, err := resolveImports(par.(Exp), libraries, 0)
+	if err != nil {
+		return TypedExp{}, value.UnitVal{}, gas, err
+	}
+	texp, err, gas = AddTypes(contractExp, gas)
 	if gas == 0 {
 		interpPanic("ran out of gas when building typed AST", gas)
 	}
@@ -114,9 +182,29 @@ func InitiateContract(contractCode []byte, gas uint64) (texp TypedExp, initstor
 	return texp, initstorage, gas, nil
 }
 
+// TypeContract builds the typed AST of a contract that was already initiated, resolving imports with libraries. No
+// gas is charged and the storage is not initialized, as this only rebuilds a deployed contract
+func TypeContract(contractCode []byte, libraries LibraryResolver) (TypedExp, error) {
+	lex := lexer.NewLexer(contractCode)
+	p := parser.NewParser()
+	par, err := p.Parse(lex)
+	if err != nil {
+		return TypedExp{}, fmt.Errorf("syntax error in contract code: %s", err.Error())
+	}
+	contractExp, err := resolveImports(par.(Exp), libraries, 0)
+	if err != nil {
+		return TypedExp{}, err
+	}
+	texp, err, _ := AddTypes(contractExp, math.MaxInt64)
+	if err != nil {
+		return TypedExp{}, fmt.Errorf("semantic error in contract code: %s", err.Error())
+	}
+	return texp, nil
+}
+
 func interpretStorageInit(texp TypedExp, gas uint64) (value.Value, uint64) {
 	exp := texp.Exp.(TopLevel)
-	venv := ps.NewMap()
+	venv := moduleEnv(exp.Roots)
 	for _, e := range exp.Roots {
 		e := e.(TypedExp).Exp
 		switch e.(type) {
@@ -136,12 +224,14 @@ func InterpretContractCall(
 	stor value.Value,
 	amount uint64,
 	balance uint64,
+	sender string,
 	gas uint64,
 ) (oplist []value.Operation, storage value.Value, spent uint64, remainingGas uint64) {
 
 	// initiate module variables
 	currentAmt = amount
 	currentBal = balance
+	currentSender = sender
 	spentsofar = 0
 
 	defer func() {
@@ -156,7 +246,7 @@ func InterpretContractCall(
 	}()
 
 	exp := texp.Exp.(TopLevel)
-	venv := ps.NewMap()
+	venv := moduleEnv(exp.Roots)
 	for _, e := range exp.Roots {
 		e := e.(TypedExp).Exp
 		switch e.(type) {
@@ -184,7 +274,7 @@ func InterpretContractCall(
 			}
 		}
 	}
-	return nil, value.UnitVal{}, 0, gas // TODO this is just a dummy return Value. Should never happen
+	return []value.Operation{failwith(fmt.Sprintf("contract has no entry %s", entry))}, stor, 0, gas
 }
 
 func applyParams(paramVal value.Value, pattern Pattern, venv VarEnv) (VarEnv, error) {
@@ -304,6 +394,16 @@ func checkParam(param interface{}, typ Type) bool {
 			ok = checkParam(val.Value, typ.(OptionType).Typ)
 		}
 		return ok
+	case MAP:
+		val, ok := param.(value.MapVal)
+		if !ok {
+			return false
+		}
+		maptype := typ.(MapType)
+		for k, v := range val.Values {
+			ok = ok && checkParam(k, maptype.KeyTyp) && checkParam(v, maptype.ValTyp)
+		}
+		return ok
 	case STRUCT:
 		val, ok := param.(value.StructVal)
 		structtype := typ.(StructType)
@@ -619,6 +719,9 @@ func interpret(texp TypedExp, venv VarEnv, gas uint64) (value.Value, uint64) {
 	case CallExp:
 		exp := exp.(CallExp)
 		name_, gas := interpret(exp.ExpList[0].(TypedExp), venv, gas)
+		if helper, ok := name_.(helperVal); ok {
+			return callHelper(helper, exp.ExpList[1:], venv, gas)
+		}
 		name := name_.(value.LambdaVal)
 		switch name.Value {
 		case value.CURRENT_BALANCE:
@@ -627,6 +730,8 @@ func interpret(texp TypedExp, venv VarEnv, gas uint64) (value.Value, uint64) {
 			return currentAmount(), gas
 		case value.CURRENT_GAS:
 			return value.KoinVal{gas}, gas
+		case value.CURRENT_SENDER:
+			return currentSenderKey(), gas
 		case value.CURRENT_FAILWITH:
 			failmessage_, gas := interpret(exp.ExpList[1].(TypedExp), venv, gas)
 			failmessage := failmessage_.(value.StringVal)
@@ -640,6 +745,15 @@ func interpret(texp TypedExp, venv VarEnv, gas uint64) (value.Value, uint64) {
 			entry := entry_.(value.StringVal)
 			param, gas := interpret(exp.ExpList[4].(TypedExp), venv, gas)
 			return contractCall(address, amount, entry, param, gas), gas
+		case value.CONTRACT_SCHEDULE:
+			slot_, gas := interpret(exp.ExpList[1].(TypedExp), venv, gas)
+			slot := slot_.(value.NatVal)
+			address_, gas := interpret(exp.ExpList[2].(TypedExp), venv, gas)
+			address := address_.(value.AddressVal)
+			entry_, gas := interpret(exp.ExpList[3].(TypedExp), venv, gas)
+			entry := entry_.(value.StringVal)
+			param, gas := interpret(exp.ExpList[4].(TypedExp), venv, gas)
+			return contractSchedule(slot, address, entry, param, gas), gas
 		case value.ACCOUNT_TRANSFER:
 			key_, gas := interpret(exp.ExpList[1].(TypedExp), venv, gas)
 			key := key_.(value.KeyVal)
@@ -650,6 +764,28 @@ func interpret(texp TypedExp, venv VarEnv, gas uint64) (value.Value, uint64) {
 			key_, gas := interpret(exp.ExpList[1].(TypedExp), venv, gas)
 			key := key_.(value.KeyVal)
 			return accountDefault(key), gas
+		case value.MAP_EMPTY:
+			return mapEmpty(), gas
+		case value.MAP_MEM:
+			key, gas := interpret(exp.ExpList[1].(TypedExp), venv, gas)
+			m, gas := interpret(exp.ExpList[2].(TypedExp), venv, gas)
+			return mapMem(key, m.(value.MapVal)), gas
+		case value.MAP_FIND:
+			key, gas := interpret(exp.ExpList[1].(TypedExp), venv, gas)
+			m, gas := interpret(exp.ExpList[2].(TypedExp), venv, gas)
+			return mapFind(key, m.(value.MapVal), gas), gas
+		case value.MAP_ADD:
+			key, gas := interpret(exp.ExpList[1].(TypedExp), venv, gas)
+			val, gas := interpret(exp.ExpList[2].(TypedExp), venv, gas)
+			m, gas := interpret(exp.ExpList[3].(TypedExp), venv, gas)
+			return mapAdd(key, val, m.(value.MapVal)), gas
+		case value.MAP_REMOVE:
+			key, gas := interpret(exp.ExpList[1].(TypedExp), venv, gas)
+			m, gas := interpret(exp.ExpList[2].(TypedExp), venv, gas)
+			return mapRemove(key, m.(value.MapVal)), gas
+		case value.MAP_SIZE:
+			m, gas := interpret(exp.ExpList[1].(TypedExp), venv, gas)
+			return value.NatVal{uint64(len(m.(value.MapVal).Values))}, gas
 		default:
 			return todo(20, gas), gas
 		}
@@ -708,6 +844,8 @@ func interpret(texp TypedExp, venv VarEnv, gas uint64) (value.Value, uint64) {
 				return value.LambdaVal{value.CURRENT_GAS}, gas
 			case "failwith":
 				return value.LambdaVal{value.CURRENT_FAILWITH}, gas
+			case "sender":
+				return value.LambdaVal{value.CURRENT_SENDER}, gas
 			default:
 				return todo(22, gas), gas
 			}
@@ -715,6 +853,8 @@ func interpret(texp TypedExp, venv VarEnv, gas uint64) (value.Value, uint64) {
 			switch exp.FieldId {
 			case "call":
 				return value.LambdaVal{value.CONTRACT_CALL}, gas
+			case "schedule":
+				return value.LambdaVal{value.CONTRACT_SCHEDULE}, gas
 			default:
 				return todo(23, gas), gas
 			}
@@ -727,8 +867,25 @@ func interpret(texp TypedExp, venv VarEnv, gas uint64) (value.Value, uint64) {
 			default:
 				return todo(24, gas), gas
 			}
+		case "Map":
+			switch exp.FieldId {
+			case "empty":
+				return value.LambdaVal{value.MAP_EMPTY}, gas
+			case "mem":
+				return value.LambdaVal{value.MAP_MEM}, gas
+			case "find":
+				return value.LambdaVal{value.MAP_FIND}, gas
+			case "add":
+				return value.LambdaVal{value.MAP_ADD}, gas
+			case "remove":
+				return value.LambdaVal{value.MAP_REMOVE}, gas
+			case "size":
+				return value.LambdaVal{value.MAP_SIZE}, gas
+			default:
+				return todo(28, gas), gas
+			}
 		default:
-			return todo(25, gas), gas
+			return lookupHelper(exp.ModId, exp.FieldId, venv, gas), gas
 		}
 
 	case LookupExp:
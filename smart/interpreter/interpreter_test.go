@@ -2,6 +2,7 @@ package interpreter
 
 import (
 	"fmt"
+	"github.com/nfk93/blockchain/crypto"
 	. "github.com/nfk93/blockchain/smart/interpreter/ast"
 	"github.com/nfk93/blockchain/smart/interpreter/lexer"
 	"github.com/nfk93/blockchain/smart/interpreter/parser"
@@ -116,6 +117,18 @@ func TestStructInStruct(t *testing.T) {
 	testFileNoError(t, "test_cases/structinstruct_semant")
 }
 
+func TestMapExp(t *testing.T) {
+	testFileNoError(t, "test_cases/map_semant")
+}
+
+func TestMapExpFail1(t *testing.T) {
+	testFileError(t, "test_cases/map1_semant")
+}
+
+func TestMapExpFail2(t *testing.T) {
+	testFileError(t, "test_cases/map2_semant")
+}
+
 func TestUpdateStruct(t *testing.T) {
 	testFileNoError(t, "test_cases/updatestruct_interp")
 }
@@ -132,7 +145,7 @@ func TestIntConstant(t *testing.T) {
 		return
 	}
 	unitval := value.UnitVal{}
-	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.IntVal{13}, 0, 0,
+	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.IntVal{13}, 0, 0, "",
 		99999999999)
 	switch sto.(type) {
 	case value.IntVal:
@@ -156,7 +169,7 @@ func TestAddressConstant(t *testing.T) {
 	}
 	unitval := value.UnitVal{}
 	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.AddressVal{"123123aA"}, 0,
-		0, 9999999999)
+		0, "", 9999999999)
 	switch sto.(type) {
 	case value.AddressVal:
 		if sto.(value.AddressVal).Value != "3132141abba3132141abba3132141abb3132141abba3132141abba3132141abb" {
@@ -179,7 +192,7 @@ func TestBoolConstant(t *testing.T) {
 	}
 	unitval := value.UnitVal{}
 	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.BoolVal{true}, 0,
-		0, 999999999999999999)
+		0, "", 999999999999999999)
 	switch sto.(type) {
 	case value.BoolVal:
 		if sto.(value.BoolVal).Value != false {
@@ -203,7 +216,7 @@ func TestDeclaredConstant(t *testing.T) {
 	unitval := value.UnitVal{}
 	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main",
 		value.TupleVal{[]value.Value{value.IntVal{123}, value.TupleVal{[]value.Value{value.IntVal{2}, value.StringVal{"serser"}}}}},
-		0, 0, 9999999)
+		0, 0, "", 9999999)
 	switch sto.(type) {
 	case value.TupleVal:
 		sto := sto.(value.TupleVal)
@@ -234,7 +247,7 @@ func TestKeyConstant(t *testing.T) {
 	}
 	unitval := value.UnitVal{}
 	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.KeyVal{"1212Ddd"}, 0,
-		0, 999999999)
+		0, "", 999999999)
 	switch sto.(type) {
 	case value.KeyVal:
 		if sto.(value.KeyVal).Value != "aaffaafaaffaafaaffaafaaffaafaaffaaffaafaaffaafaaffaafaaffaafaaff" {
@@ -248,6 +261,19 @@ func TestKeyConstant(t *testing.T) {
 	}
 }
 
+func TestKeyChecksum(t *testing.T) {
+	for _, key := range []string{
+		"kn12wkBET2rRgE8pahuaczxKbmv7ciehqsne57F9gtzf1PVgytWuR",
+		"kn1aaffaafaaffaafaaffaafaaffaafaaffaaffaafaaffaafaaffaafaaffaafaaff",
+		"kn22wkBET2rRgE8pahuaczxKbmv7ciehqsne57F9gtzf1PVgytWuQ",
+	} {
+		code := "type storage = unit\nlet%init storage = ()\nlet%entry main () s = (([] : operation list), " + key + ")"
+		if _, err := parser.NewParser().Parse(lexer.NewLexer([]byte(code))); err == nil {
+			t.Errorf("%s should be rejected", key)
+		}
+	}
+}
+
 func TestKoinConstant(t *testing.T) {
 	testpath := "test_cases/constants/koin"
 	texp, err := getTypedAST(t, testpath)
@@ -257,7 +283,7 @@ func TestKoinConstant(t *testing.T) {
 	}
 	unitval := value.UnitVal{}
 	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.KoinVal{uint64(110000)}, 0,
-		0, 99999999999)
+		0, "", 99999999999)
 	switch sto.(type) {
 	case value.KoinVal:
 		if sto.(value.KoinVal).Value != 13355000 {
@@ -280,7 +306,7 @@ func TestListConstant(t *testing.T) {
 	}
 	unitval := value.UnitVal{}
 	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.ListVal{[]value.Value{value.IntVal{2}}},
-		0, 0, 9999999999)
+		0, 0, "", 9999999999)
 	switch sto.(type) {
 	case value.ListVal:
 		sto := sto.(value.ListVal)
@@ -309,7 +335,7 @@ func TestNatConstant(t *testing.T) {
 		return
 	}
 	unitval := value.UnitVal{}
-	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.NatVal{13}, 0, 0,
+	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.NatVal{13}, 0, 0, "",
 		99999999999)
 	switch sto.(type) {
 	case value.NatVal:
@@ -333,7 +359,7 @@ func TestStringConstant(t *testing.T) {
 	}
 	unitval := value.UnitVal{}
 	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.StringVal{"eymom"}, 0,
-		0, 99999999999)
+		0, "", 99999999999)
 	switch sto.(type) {
 	case value.StringVal:
 		if !(sto.(value.StringVal).Value == "dank") {
@@ -356,7 +382,7 @@ func TestUnitConstant(t *testing.T) {
 	}
 	unitval := value.UnitVal{}
 	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.UnitVal{}, 0,
-		0, 99999999999)
+		0, "", 99999999999)
 	switch sto.(type) {
 	case value.UnitVal:
 	default:
@@ -379,7 +405,7 @@ func TestStructConstant(t *testing.T) {
 	storageinit.Field["a"] = value.IntVal{1213}
 	storageinit.Field["b"] = value.TupleVal{[]value.Value{value.IntVal{5}, value.IntVal{6}}}
 	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", storageinit, 0,
-		0, 99999999999)
+		0, "", 99999999999)
 	switch sto.(type) {
 	case value.StructVal:
 		sto, oksto := sto.(value.StructVal)
@@ -418,7 +444,7 @@ func TestCurrentModule(t *testing.T) {
 	}
 	unitval := value.UnitVal{}
 	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.KoinVal{1000000}, 0,
-		0, 999999999)
+		0, "", 999999999)
 	switch sto.(type) {
 	case value.KoinVal:
 		sto := sto.(value.KoinVal)
@@ -433,6 +459,69 @@ func TestCurrentModule(t *testing.T) {
 	}
 }
 
+func TestCurrentSender(t *testing.T) {
+	dat, err := ioutil.ReadFile("test_cases/currentsender_interp")
+	if err != nil {
+		t.Error("Error reading test_cases/currentsender_interp")
+	}
+	creator := "1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"
+	texp, sto, _, err := InitiateContract(dat, creator, 99999999)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	if key, ok := sto.(value.KeyVal); !ok || key.Value != creator {
+		t.Errorf("init storage should be the creator but was %s", sto)
+	}
+	caller := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	_, sto, _, _ = InterpretContractCall(texp, value.UnitVal{}, "main", sto, 0, 0, caller, 999999999)
+	if key, ok := sto.(value.KeyVal); !ok || key.Value != caller {
+		t.Errorf("storage should be the caller but was %s", sto)
+	}
+}
+
+func TestMapModule(t *testing.T) {
+	texp, err := getTypedAST(t, "test_cases/map_interp")
+	if err != nil {
+		t.Errorf("Semant error: %s", err.Error())
+		return
+	}
+	add := func(k string, v int64) value.Value {
+		return value.TupleVal{[]value.Value{value.StringVal{k}, value.IntVal{v}}}
+	}
+	sto := value.Value(value.MapVal{make(map[value.Value]value.Value)})
+	_, sto, _, _ = InterpretContractCall(texp, add("a", 5), "main", sto, 0, 0, "", 999999999)
+	_, sto, _, _ = InterpretContractCall(texp, add("b", 2), "main", sto, 0, 0, "", 999999999)
+	_, sto, _, _ = InterpretContractCall(texp, add("a", 3), "main", sto, 0, 0, "", 999999999)
+	m, ok := sto.(value.MapVal)
+	if !ok {
+		t.Errorf("storage isn't expected type. It is type %s", reflect.TypeOf(sto).String())
+		return
+	}
+	if len(m.Values) != 2 || m.Values[value.StringVal{"a"}] != (value.IntVal{8}) ||
+		m.Values[value.StringVal{"b"}] != (value.IntVal{2}) {
+		t.Errorf("storage has unexpected value %s", m.Values)
+	}
+
+	_, removed, _, _ := InterpretContractCall(texp, value.StringVal{"a"}, "remove", sto, 0, 0, "", 999999999)
+	if len(removed.(value.MapVal).Values) != 1 {
+		t.Errorf("key wasn't removed from map")
+	}
+	if len(m.Values) != 2 {
+		t.Errorf("removing a key mutated the original map")
+	}
+
+	oplist, sto, _, _ := InterpretContractCall(texp, value.StringVal{"c"}, "find", sto, 0, 0, "", 999999999)
+	if len(oplist) != 1 {
+		t.Errorf("oplist should have 1 operation but had %d", len(oplist))
+	} else if failwith, ok := oplist[0].(value.FailWith); !ok || failwith.Msg != "key not found in map" {
+		t.Errorf("unexpected returned operation %s", oplist[0])
+	}
+	if !value.Equals(sto, m) {
+		t.Errorf("failed call changed the storage")
+	}
+}
+
 func TestCheckParams(t *testing.T) {
 	stringval := value.StringVal{"ey"}
 	stringtype := StringType{}
@@ -519,7 +608,7 @@ func TestInterpretUpdateStruct(t *testing.T) {
 	storage.Field["b"] = inner
 
 	oplist, sto, _, _ := InterpretContractCall(texp, params, "main", storage, 0,
-		0, 999999999999)
+		0, "", 999999999999)
 	switch sto.(type) {
 	case value.StructVal:
 		sto := sto.(value.StructVal)
@@ -566,7 +655,7 @@ func TestInterpretBinOps(t *testing.T) {
 	params := value.TupleVal{[]value.Value{value.IntVal{13}, value.IntVal{17}}}
 	storage := value.IntVal{19}
 	oplist, sto, _, _ := InterpretContractCall(texp, params, "main", storage, 0,
-		0, 999999999999)
+		0, "", 999999999999)
 	switch sto.(type) {
 	case value.IntVal:
 		if sto.(value.IntVal).Value != 13+17+19 {
@@ -590,7 +679,7 @@ func TestIntDivision(t *testing.T) {
 	params := value.TupleVal{[]value.Value{value.IntVal{13}, value.IntVal{17}}}
 	storage := value.TupleVal{[]value.Value{value.IntVal{19}, value.NatVal{0}}}
 	oplist, sto, _, _ := InterpretContractCall(texp, params, "main", storage, 0,
-		0, 100000)
+		0, "", 100000)
 
 	switch sto.(type) {
 	case value.TupleVal:
@@ -622,7 +711,7 @@ func TestIntDivision(t *testing.T) {
 
 	params = value.TupleVal{[]value.Value{value.IntVal{0}, value.IntVal{0}}}
 	oplist, sto, _, _ = InterpretContractCall(texp, params, "main", storage, 0,
-		0, 100000)
+		0, "", 100000)
 	if len(oplist) != 1 {
 		t.Errorf("oplist is len %d should be 1", len(oplist))
 	}
@@ -644,7 +733,7 @@ func TestKoinDivision(t *testing.T) {
 	params := value.TupleVal{[]value.Value{value.KoinVal{NatToKoin(5)}, value.KoinVal{NatToKoin(2)}}}
 	storage := value.TupleVal{[]value.Value{value.NatVal{10}, value.KoinVal{NatToKoin(2)}}}
 	oplist, sto, _, _ := InterpretContractCall(texp, params, "main", storage, 0,
-		0, 100000)
+		0, "", 100000)
 
 	switch sto.(type) {
 	case value.TupleVal:
@@ -686,7 +775,7 @@ func TestInterpretFailwithGas(t *testing.T) {
 	unitval := value.UnitVal{}
 	initgas := NatToKoin(100)
 	_, _, _, gas := InterpretContractCall(texp, unitval, "main", value.KoinVal{1000000}, 0,
-		0, initgas)
+		0, "", initgas)
 	if gas != initgas-6000 {
 		t.Errorf("remaining gas is %d, expected %d", gas, initgas-6000)
 	}
@@ -700,7 +789,7 @@ func TestInterpretFailwith(t *testing.T) {
 	}
 	unitval := value.UnitVal{}
 	oplist, sto, _, _ := InterpretContractCall(texp, unitval, "main", value.KoinVal{1000000}, 0,
-		0, 99999999999)
+		0, "", 99999999999)
 	switch sto.(type) {
 	case value.KoinVal:
 		sto := sto.(value.KoinVal)
@@ -724,7 +813,7 @@ func TestInterpretLetexps(t *testing.T) {
 	params := value.TupleVal{[]value.Value{value.IntVal{7}, value.StringVal{"not imporatnt"}, value.NatVal{13}}}
 	storage := value.IntVal{19}
 	oplist, sto, _, _ := InterpretContractCall(texp, params, "main", storage, 0,
-		0, 999999999999)
+		0, "", 999999999999)
 	switch sto.(type) {
 	case value.IntVal:
 		if sto.(value.IntVal).Value != 19-(15+7+13) {
@@ -743,7 +832,7 @@ func TestInitStorage(t *testing.T) {
 	if err != nil {
 		t.Error("Error reading testfile_noerror")
 	}
-	_, init, _, err := InitiateContract(dat, 999999999999999)
+	_, init, _, err := InitiateContract(dat, "", 999999999999999)
 	if err != nil {
 		t.Error(err)
 		return
@@ -773,7 +862,7 @@ func TestRunFundme(t *testing.T) {
 	if err != nil {
 		t.Error("Error reading testfile_noerror")
 	}
-	texp, stor, _, err := InitiateContract(dat, 999999999)
+	texp, stor, _, err := InitiateContract(dat, "", 999999999)
 	if err != nil {
 		t.Error(err)
 		return
@@ -802,20 +891,20 @@ func TestRunFundme(t *testing.T) {
 	otherkey := "asdasdasd"
 	param1 := value.KeyVal{otherkey}
 	oplist, stor, _, _ := InterpretContractCall(texp, param1, "main", stor, 900000,
-		0, 999999)
+		0, "", 999999)
 	checkstorage("call1", stor, ownerkey, 1100000, 900000)
 	if len(oplist) != 0 {
 		t.Errorf("oplist isn't empty. It is %s", oplist)
 	}
 	oplist, stor, _, _ = InterpretContractCall(texp, param1, "main", stor, 100000,
-		0, 9999999)
+		0, "", 9999999)
 	checkstorage("call2", stor, ownerkey, 1100000, 1000000)
 	if len(oplist) != 0 {
 		t.Errorf("oplist isn't empty. It is %s", oplist)
 	}
 
 	oplist, stor, _, _ = InterpretContractCall(texp, param1, "main", stor, 500000,
-		1000000, 999999)
+		1000000, "", 999999)
 	checkstorage("call3", stor, ownerkey, 1100000, 1100000)
 	if len(oplist) != 1 {
 		t.Errorf("oplist should have 1 operation but had %d", len(oplist))
@@ -830,7 +919,7 @@ func TestRunFundme(t *testing.T) {
 	}
 
 	oplist, stor, _, _ = InterpretContractCall(texp, param1, "main", stor, 900000,
-		0, 9999999)
+		0, "", 9999999)
 	checkstorage("call4", stor, ownerkey, 1100000, 1100000)
 	if len(oplist) != 1 {
 		t.Errorf("oplist should have 1 operation but had %d", len(oplist))
@@ -842,7 +931,7 @@ func TestRunFundme(t *testing.T) {
 	}
 
 	oplist, stor, _, _ = InterpretContractCall(texp, value.KeyVal{ownerkey}, "main", stor, 0,
-		1100000, 999999)
+		1100000, "", 999999)
 	checkstorage("call5", stor, ownerkey, 1100000, 1100000)
 	if len(oplist) != 1 {
 		t.Errorf("oplist should have 1 operation but had %d", len(oplist))
@@ -862,7 +951,7 @@ func TestRunOutOfGas(t *testing.T) {
 	if err != nil {
 		t.Error("Error reading testfile_noerror")
 	}
-	_, _, remaining, err := InitiateContract(dat, 200000)
+	_, _, remaining, err := InitiateContract(dat, "", 200000)
 	if err == nil {
 		t.Error("should've run out of gas")
 		return
@@ -874,13 +963,13 @@ func TestRunOutOfGas(t *testing.T) {
 	ownerkey := "1234567890abcdef1234567890abcdef"
 	param1 := value.KeyVal{ownerkey}
 
-	texp, stor, remaining, err := InitiateContract(dat, 207000)
+	texp, stor, remaining, err := InitiateContract(dat, "", 207000)
 	if err != nil {
 		t.Errorf("error initiating contract")
 		return
 	}
 	oplist, stor, _, remaining := InterpretContractCall(texp, param1, "main", stor, 900000,
-		0, remaining)
+		0, "", remaining)
 	if len(oplist) != 1 {
 		t.Errorf("oplist should have 1 operation but had %d", len(oplist))
 	} else {
@@ -899,7 +988,7 @@ func TestCallContract(t *testing.T) {
 	if err != nil {
 		t.Error("Error reading testfile_noerror")
 	}
-	texp, sto, _, err := InitiateContract(dat, 20000000)
+	texp, sto, _, err := InitiateContract(dat, "", 20000000)
 	if err != nil {
 		t.Errorf(err.Error())
 		return
@@ -908,7 +997,7 @@ func TestCallContract(t *testing.T) {
 	param := value.UnitVal{}
 
 	oplist, sto, _, _ := InterpretContractCall(texp, param, "second", sto, 900000,
-		0, 100000)
+		0, "", 100000)
 	if len(oplist) != 1 {
 		t.Errorf("oplist should have length 1")
 		return
@@ -923,7 +1012,7 @@ func TestCallContract(t *testing.T) {
 	}
 
 	oplist, sto, _, _ = InterpretContractCall(texp, param, "second", sto, 90000000,
-		0, 100000)
+		0, "", 100000)
 	call, ok := oplist[0].(value.ContractCall)
 	if !ok {
 		t.Errorf("op[0] should be failwith operation")
@@ -944,8 +1033,101 @@ func TestCallContract(t *testing.T) {
 	}
 }
 
+func TestImport(t *testing.T) {
+	testFileNoError(t, "test_cases/import_semant")
+}
+
+func TestImportImpureHelper(t *testing.T) {
+	testFileError(t, "test_cases/import1_semant")
+}
+
+func TestImportLibraryWithEntry(t *testing.T) {
+	testFileError(t, "test_cases/import2_semant")
+}
+
+func TestImportUnbundledFile(t *testing.T) {
+	testFileError(t, "test_cases/import3_semant")
+}
+
+func TestImportFile(t *testing.T) {
+	dat, err := ioutil.ReadFile("test_cases/import_interp")
+	if err != nil {
+		t.Error("Error reading test_cases/import_interp")
+	}
+	bundled, err := BundleImports(dat, "test_cases")
+	if err != nil {
+		t.Errorf("unexpected error bundling imports: %s", err.Error())
+		return
+	}
+	texp, sto, _, err := InitiateContract(bundled, "", 99999999)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	checkPoint(t, sto, 0, 0)
+	params := value.TupleVal{[]value.Value{value.IntVal{1}, value.IntVal{2}}}
+	_, sto, _, _ = InterpretContractCall(texp, params, "main", sto, 0, 0, "", 99999999)
+	checkPoint(t, sto, 2, 4)
+	_, sto, _, _ = InterpretContractCall(texp, params, "main", sto, 0, 0, "", 99999999)
+	checkPoint(t, sto, 6, 12)
+}
+
+func TestImportAddress(t *testing.T) {
+	library, err := ioutil.ReadFile("test_cases/geometry_lib")
+	if err != nil {
+		t.Error("Error reading test_cases/geometry_lib")
+	}
+	libaddr := "aaba1231333aaba1231333aaba123133aaba1231333aaba1231333aaba123133"
+	resolver := func(address string) ([]byte, bool) {
+		return library, address == libaddr
+	}
+	address, _ := crypto.EncodeContract(libaddr)
+	contract := "import Geo = " + address + `
+type storage = Geo.point
+let%init storage = Geo.add (Geo.origin ()) {x = 3; y = 4;}
+let%entry main () s = (([]: operation list), s)`
+	_, sto, _, err := InitiateContractWithLibraries([]byte(contract), "", 99999999, resolver)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	checkPoint(t, sto, 3, 4)
+
+	if _, _, _, err := InitiateContract([]byte(contract), "", 99999999); err == nil {
+		t.Errorf("import of an unknown library should fail")
+	}
+}
+
+func TestImportChargesGas(t *testing.T) {
+	dat, _ := ioutil.ReadFile("test_cases/import_interp")
+	bundled, _ := BundleImports(dat, "test_cases")
+	texp, sto, _, err := InitiateContract(bundled, "", 99999999)
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	params := value.TupleVal{[]value.Value{value.IntVal{1}, value.IntVal{2}}}
+	oplist, _, _, _ := InterpretContractCall(texp, params, "main", sto, 0, 0, "", 20000)
+	if len(oplist) != 1 {
+		t.Errorf("should run out of gas in the library helpers")
+	} else if failWith, ok := oplist[0].(value.FailWith); !ok || failWith.Msg != "ran out of gas!" {
+		t.Errorf("unexpected returned operation")
+	}
+}
+
 /* Helper functions */
 
+func checkPoint(t *testing.T, sto value.Value, x, y int64) {
+	point, ok := sto.(value.StructVal)
+	if !ok {
+		t.Errorf("storage should be a point but was %s", sto)
+		return
+	}
+	if !value.Equals(point.Field["x"], value.IntVal{x}) || !value.Equals(point.Field["y"], value.IntVal{y}) {
+		t.Errorf("storage should be {x = %d; y = %d} but was %s", x, y, sto)
+	}
+}
+
 func testFileNoError(t *testing.T, testpath string) {
 	testFile(t, testpath, false)
 }
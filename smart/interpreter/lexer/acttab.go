@@ -33,55 +33,55 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S3
-		Accept: 23,
+		Accept: 26,
 		Ignore: "",
 	},
 	ActionRow{ // S4
-		Accept: 19,
+		Accept: 22,
 		Ignore: "",
 	},
 	ActionRow{ // S5
-		Accept: 20,
+		Accept: 23,
 		Ignore: "",
 	},
 	ActionRow{ // S6
-		Accept: 26,
+		Accept: 29,
 		Ignore: "",
 	},
 	ActionRow{ // S7
-		Accept: 24,
+		Accept: 27,
 		Ignore: "",
 	},
 	ActionRow{ // S8
-		Accept: 35,
+		Accept: 38,
 		Ignore: "",
 	},
 	ActionRow{ // S9
-		Accept: 25,
+		Accept: 28,
 		Ignore: "",
 	},
 	ActionRow{ // S10
-		Accept: 18,
+		Accept: 21,
 		Ignore: "",
 	},
 	ActionRow{ // S11
-		Accept: 27,
+		Accept: 30,
 		Ignore: "",
 	},
 	ActionRow{ // S12
-		Accept: 51,
+		Accept: 54,
 		Ignore: "",
 	},
 	ActionRow{ // S13
-		Accept: 9,
+		Accept: 14,
 		Ignore: "",
 	},
 	ActionRow{ // S14
-		Accept: 10,
+		Accept: 15,
 		Ignore: "",
 	},
 	ActionRow{ // S15
-		Accept: 31,
+		Accept: 34,
 		Ignore: "",
 	},
 	ActionRow{ // S16
@@ -89,19 +89,19 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S17
-		Accept: 32,
+		Accept: 35,
 		Ignore: "",
 	},
 	ActionRow{ // S18
-		Accept: 17,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S19
-		Accept: 55,
+		Accept: 57,
 		Ignore: "",
 	},
 	ActionRow{ // S20
-		Accept: 56,
+		Accept: 58,
 		Ignore: "",
 	},
 	ActionRow{ // S21
@@ -157,31 +157,31 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S34
-		Accept: 7,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S35
-		Accept: 0,
+		Accept: 11,
 		Ignore: "",
 	},
 	ActionRow{ // S36
-		Accept: 8,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S37
-		Accept: 0,
+		Accept: 12,
 		Ignore: "",
 	},
 	ActionRow{ // S38
-		Accept: 54,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S39
-		Accept: 23,
+		Accept: 10,
 		Ignore: "",
 	},
 	ActionRow{ // S40
-		Accept: 0,
+		Accept: 26,
 		Ignore: "",
 	},
 	ActionRow{ // S41
@@ -189,11 +189,11 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S42
-		Accept: 51,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S43
-		Accept: 0,
+		Accept: 54,
 		Ignore: "",
 	},
 	ActionRow{ // S44
@@ -201,39 +201,39 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S45
-		Accept: 52,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S46
-		Accept: 14,
+		Accept: 55,
 		Ignore: "",
 	},
 	ActionRow{ // S47
-		Accept: 21,
+		Accept: 19,
 		Ignore: "",
 	},
 	ActionRow{ // S48
-		Accept: 30,
+		Accept: 24,
 		Ignore: "",
 	},
 	ActionRow{ // S49
-		Accept: 28,
+		Accept: 33,
 		Ignore: "",
 	},
 	ActionRow{ // S50
-		Accept: 29,
+		Accept: 31,
 		Ignore: "",
 	},
 	ActionRow{ // S51
-		Accept: 17,
+		Accept: 32,
 		Ignore: "",
 	},
 	ActionRow{ // S52
-		Accept: 0,
+		Accept: 8,
 		Ignore: "",
 	},
 	ActionRow{ // S53
-		Accept: 3,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S54
@@ -253,7 +253,7 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S58
-		Accept: 11,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S59
@@ -265,7 +265,7 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S61
-		Accept: 3,
+		Accept: 20,
 		Ignore: "",
 	},
 	ActionRow{ // S62
@@ -301,7 +301,7 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S70
-		Accept: 22,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S71
@@ -313,7 +313,7 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S73
-		Accept: 3,
+		Accept: 25,
 		Ignore: "",
 	},
 	ActionRow{ // S74
@@ -325,23 +325,23 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S76
-		Accept: 22,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S77
-		Accept: 33,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S78
-		Accept: 0,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S79
-		Accept: 0,
+		Accept: 25,
 		Ignore: "",
 	},
 	ActionRow{ // S80
-		Accept: 53,
+		Accept: 36,
 		Ignore: "",
 	},
 	ActionRow{ // S81
@@ -349,15 +349,15 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S82
-		Accept: 3,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S83
-		Accept: 3,
+		Accept: 56,
 		Ignore: "",
 	},
 	ActionRow{ // S84
-		Accept: 3,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S85
@@ -365,11 +365,11 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S86
-		Accept: 37,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S87
-		Accept: 42,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S88
@@ -381,15 +381,15 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S90
-		Accept: 3,
+		Accept: 40,
 		Ignore: "",
 	},
 	ActionRow{ // S91
-		Accept: 3,
+		Accept: 45,
 		Ignore: "",
 	},
 	ActionRow{ // S92
-		Accept: 15,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S93
@@ -397,15 +397,15 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S94
-		Accept: 38,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S95
-		Accept: 34,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S96
-		Accept: 3,
+		Accept: 6,
 		Ignore: "",
 	},
 	ActionRow{ // S97
@@ -413,15 +413,15 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S98
-		Accept: 3,
+		Accept: 50,
 		Ignore: "",
 	},
 	ActionRow{ // S99
-		Accept: 3,
+		Accept: 41,
 		Ignore: "",
 	},
 	ActionRow{ // S100
-		Accept: 3,
+		Accept: 37,
 		Ignore: "",
 	},
 	ActionRow{ // S101
@@ -433,15 +433,15 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S103
-		Accept: -1,
-		Ignore: "!whitespace",
+		Accept: 3,
+		Ignore: "",
 	},
 	ActionRow{ // S104
-		Accept: 0,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S105
-		Accept: 0,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S106
@@ -449,71 +449,71 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S107
-		Accept: 36,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S108
-		Accept: 13,
-		Ignore: "",
+		Accept: -1,
+		Ignore: "!whitespace",
 	},
 	ActionRow{ // S109
-		Accept: 3,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S110
-		Accept: 47,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S111
-		Accept: 48,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S112
-		Accept: 40,
+		Accept: 39,
 		Ignore: "",
 	},
 	ActionRow{ // S113
-		Accept: 23,
+		Accept: 18,
 		Ignore: "",
 	},
 	ActionRow{ // S114
-		Accept: 0,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S115
-		Accept: 46,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S116
-		Accept: 3,
+		Accept: 51,
 		Ignore: "",
 	},
 	ActionRow{ // S117
-		Accept: 3,
+		Accept: 9,
 		Ignore: "",
 	},
 	ActionRow{ // S118
-		Accept: 3,
+		Accept: 43,
 		Ignore: "",
 	},
 	ActionRow{ // S119
-		Accept: 12,
+		Accept: 26,
 		Ignore: "",
 	},
 	ActionRow{ // S120
-		Accept: 49,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S121
-		Accept: 6,
+		Accept: 49,
 		Ignore: "",
 	},
 	ActionRow{ // S122
-		Accept: 39,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S123
-		Accept: 0,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S124
@@ -521,23 +521,23 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S125
-		Accept: 50,
+		Accept: 17,
 		Ignore: "",
 	},
 	ActionRow{ // S126
-		Accept: 0,
+		Accept: 52,
 		Ignore: "",
 	},
 	ActionRow{ // S127
-		Accept: 0,
+		Accept: 13,
 		Ignore: "",
 	},
 	ActionRow{ // S128
-		Accept: 3,
+		Accept: 42,
 		Ignore: "",
 	},
 	ActionRow{ // S129
-		Accept: 3,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S130
@@ -545,7 +545,7 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S131
-		Accept: 0,
+		Accept: 53,
 		Ignore: "",
 	},
 	ActionRow{ // S132
@@ -565,11 +565,11 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S136
-		Accept: 45,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S137
-		Accept: 41,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S138
@@ -577,11 +577,11 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S139
-		Accept: 44,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S140
-		Accept: 0,
+		Accept: 7,
 		Ignore: "",
 	},
 	ActionRow{ // S141
@@ -589,39 +589,39 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S142
-		Accept: 3,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S143
-		Accept: 0,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S144
-		Accept: 0,
+		Accept: 48,
 		Ignore: "",
 	},
 	ActionRow{ // S145
-		Accept: 2,
+		Accept: 44,
 		Ignore: "",
 	},
 	ActionRow{ // S146
-		Accept: 3,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S147
-		Accept: 0,
+		Accept: 47,
 		Ignore: "",
 	},
 	ActionRow{ // S148
-		Accept: 5,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S149
-		Accept: 43,
+		Accept: 0,
 		Ignore: "",
 	},
 	ActionRow{ // S150
-		Accept: 0,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S151
@@ -629,6 +629,38 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S152
+		Accept: 0,
+		Ignore: "",
+	},
+	ActionRow{ // S153
+		Accept: 2,
+		Ignore: "",
+	},
+	ActionRow{ // S154
+		Accept: 3,
+		Ignore: "",
+	},
+	ActionRow{ // S155
+		Accept: 0,
+		Ignore: "",
+	},
+	ActionRow{ // S156
+		Accept: 5,
+		Ignore: "",
+	},
+	ActionRow{ // S157
+		Accept: 46,
+		Ignore: "",
+	},
+	ActionRow{ // S158
+		Accept: 0,
+		Ignore: "",
+	},
+	ActionRow{ // S159
+		Accept: 0,
+		Ignore: "",
+	},
+	ActionRow{ // S160
 		Accept: -1,
 		Ignore: "!whitespace",
 	},
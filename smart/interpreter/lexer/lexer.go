@@ -11,8 +11,8 @@ import (
 
 const (
 	NoState    = -1
-	NumStates  = 153
-	NumSymbols = 190
+	NumStates  = 161
+	NumSymbols = 203
 )
 
 type Lexer struct {
@@ -82,29 +82,29 @@ var TransTab = TransitionTable{
 		case r == 108: // ['l','l']
 			return 28
 		case r == 109: // ['m','m']
-			return 21
-		case r == 110: // ['n','n']
 			return 29
-		case r == 111: // ['o','o']
+		case r == 110: // ['n','n']
 			return 30
+		case r == 111: // ['o','o']
+			return 31
 		case 112 <= r && r <= 114: // ['p','r']
 			return 21
 		case r == 115: // ['s','s']
-			return 31
-		case r == 116: // ['t','t']
 			return 32
-		case r == 117: // ['u','u']
+		case r == 116: // ['t','t']
 			return 33
+		case r == 117: // ['u','u']
+			return 34
 		case 118 <= r && r <= 122: // ['v','z']
 			return 21
 		case r == 123: // ['{','{']
-			return 34
-		case r == 124: // ['|','|']
 			return 35
-		case r == 125: // ['}','}']
+		case r == 124: // ['|','|']
 			return 36
-		case r == 126: // ['~','~']
+		case r == 125: // ['}','}']
 			return 37
+		case r == 126: // ['~','~']
+			return 38
 		}
 		return NoState
 	},
@@ -118,7 +118,7 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case r == 34: // ['"','"']
-			return 38
+			return 39
 		default:
 			return 2
 		}
@@ -127,7 +127,7 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case r == 38: // ['&','&']
-			return 39
+			return 40
 		}
 		return NoState
 	},
@@ -135,7 +135,7 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case r == 42: // ['*','*']
-			return 40
+			return 41
 		}
 		return NoState
 	},
@@ -167,9 +167,9 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case r == 45: // ['-','-']
-			return 41
-		case 48 <= r && r <= 57: // ['0','9']
 			return 42
+		case 48 <= r && r <= 57: // ['0','9']
+			return 43
 		}
 		return NoState
 	},
@@ -189,13 +189,13 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case r == 46: // ['.','.']
-			return 43
+			return 44
 		case 48 <= r && r <= 57: // ['0','9']
 			return 12
 		case r == 107: // ['k','k']
-			return 44
-		case r == 112: // ['p','p']
 			return 45
+		case r == 112: // ['p','p']
+			return 46
 		}
 		return NoState
 	},
@@ -203,7 +203,7 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case r == 58: // [':',':']
-			return 46
+			return 47
 		}
 		return NoState
 	},
@@ -217,11 +217,11 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case r == 45: // ['-','-']
-			return 47
-		case r == 61: // ['=','=']
 			return 48
-		case r == 62: // ['>','>']
+		case r == 61: // ['=','=']
 			return 49
+		case r == 62: // ['>','>']
+			return 50
 		}
 		return NoState
 	},
@@ -235,7 +235,7 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case r == 61: // ['=','=']
-			return 50
+			return 51
 		}
 		return NoState
 	},
@@ -243,13 +243,13 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 51
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 51
+			return 52
 		case r == 95: // ['_','_']
-			return 51
+			return 52
 		case 97 <= r && r <= 122: // ['a','z']
-			return 51
+			return 52
 		}
 		return NoState
 	},
@@ -257,7 +257,7 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case r == 37: // ['%','%']
-			return 52
+			return 53
 		}
 		return NoState
 	},
@@ -271,13 +271,13 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
@@ -285,17 +285,17 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 99: // ['a','c']
-			return 53
-		case r == 100: // ['d','d']
 			return 54
+		case r == 100: // ['d','d']
+			return 55
 		case 101 <= r && r <= 122: // ['e','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
@@ -303,17 +303,17 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 110: // ['a','n']
-			return 53
+			return 54
 		case r == 111: // ['o','o']
-			return 55
+			return 56
 		case 112 <= r && r <= 122: // ['p','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
@@ -321,17 +321,17 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 107: // ['a','k']
-			return 53
+			return 54
 		case r == 108: // ['l','l']
-			return 56
+			return 57
 		case 109 <= r && r <= 122: // ['m','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
@@ -339,15 +339,15 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case r == 97: // ['a','a']
-			return 57
+			return 58
 		case 98 <= r && r <= 122: // ['b','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
@@ -355,21 +355,23 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 101: // ['a','e']
-			return 53
+			return 54
 		case r == 102: // ['f','f']
-			return 58
-		case 103 <= r && r <= 109: // ['g','m']
-			return 53
-		case r == 110: // ['n','n']
 			return 59
+		case 103 <= r && r <= 108: // ['g','l']
+			return 54
+		case r == 109: // ['m','m']
+			return 60
+		case r == 110: // ['n','n']
+			return 61
 		case 111 <= r && r <= 122: // ['o','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
@@ -377,23 +379,23 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 100: // ['a','d']
-			return 53
+			return 54
 		case r == 101: // ['e','e']
-			return 60
+			return 62
 		case 102 <= r && r <= 109: // ['f','m']
-			return 53
+			return 54
 		case r == 110: // ['n','n']
-			return 61
+			return 63
 		case r == 111: // ['o','o']
-			return 62
+			return 64
 		case 112 <= r && r <= 122: // ['p','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
@@ -401,27 +403,27 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case r == 97: // ['a','a']
-			return 63
+			return 65
 		case 98 <= r && r <= 100: // ['b','d']
-			return 53
+			return 54
 		case r == 101: // ['e','e']
-			return 64
+			return 66
 		case 102 <= r && r <= 104: // ['f','h']
-			return 53
+			return 54
 		case r == 105: // ['i','i']
-			return 65
+			return 67
 		case 106 <= r && r <= 110: // ['j','n']
-			return 53
+			return 54
 		case r == 111: // ['o','o']
-			return 66
+			return 68
 		case 112 <= r && r <= 122: // ['p','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
@@ -429,137 +431,147 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case r == 97: // ['a','a']
-			return 67
+			return 69
+		case 98 <= r && r <= 122: // ['b','z']
+			return 54
+		}
+		return NoState
+	},
+	// S30
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 54
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 54
+		case r == 95: // ['_','_']
+			return 54
+		case r == 97: // ['a','a']
+			return 70
 		case 98 <= r && r <= 110: // ['b','n']
-			return 53
+			return 54
 		case r == 111: // ['o','o']
-			return 68
+			return 71
 		case 112 <= r && r <= 122: // ['p','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S30
+	// S31
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 111: // ['a','o']
-			return 53
+			return 54
 		case r == 112: // ['p','p']
-			return 69
+			return 72
 		case r == 113: // ['q','q']
-			return 53
+			return 54
 		case r == 114: // ['r','r']
-			return 70
+			return 73
 		case 115 <= r && r <= 122: // ['s','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S31
+	// S32
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 115: // ['a','s']
-			return 53
+			return 54
 		case r == 116: // ['t','t']
-			return 71
+			return 74
 		case 117 <= r && r <= 122: // ['u','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S32
+	// S33
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 103: // ['a','g']
-			return 53
+			return 54
 		case r == 104: // ['h','h']
-			return 72
+			return 75
 		case 105 <= r && r <= 113: // ['i','q']
-			return 53
+			return 54
 		case r == 114: // ['r','r']
-			return 73
+			return 76
 		case 115 <= r && r <= 120: // ['s','x']
-			return 53
+			return 54
 		case r == 121: // ['y','y']
-			return 74
+			return 77
 		case r == 122: // ['z','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S33
+	// S34
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 109: // ['a','m']
-			return 53
+			return 54
 		case r == 110: // ['n','n']
-			return 75
+			return 78
 		case 111 <= r && r <= 122: // ['o','z']
-			return 53
-		}
-		return NoState
-	},
-	// S34
-	func(r rune) int {
-		switch {
+			return 54
 		}
 		return NoState
 	},
 	// S35
 	func(r rune) int {
 		switch {
-		case r == 124: // ['|','|']
-			return 76
 		}
 		return NoState
 	},
 	// S36
 	func(r rune) int {
 		switch {
+		case r == 124: // ['|','|']
+			return 79
 		}
 		return NoState
 	},
 	// S37
 	func(r rune) int {
 		switch {
-		case r == 45: // ['-','-']
-			return 77
 		}
 		return NoState
 	},
 	// S38
 	func(r rune) int {
 		switch {
+		case r == 45: // ['-','-']
+			return 80
 		}
 		return NoState
 	},
@@ -572,27 +584,25 @@ var TransTab = TransitionTable{
 	// S40
 	func(r rune) int {
 		switch {
-		case r == 42: // ['*','*']
-			return 78
-		default:
-			return 40
 		}
+		return NoState
 	},
 	// S41
 	func(r rune) int {
 		switch {
-		case r == 45: // ['-','-']
+		case r == 42: // ['*','*']
+			return 81
+		default:
 			return 41
-		case 48 <= r && r <= 57: // ['0','9']
-			return 42
 		}
-		return NoState
 	},
 	// S42
 	func(r rune) int {
 		switch {
-		case 48 <= r && r <= 57: // ['0','9']
+		case r == 45: // ['-','-']
 			return 42
+		case 48 <= r && r <= 57: // ['0','9']
+			return 43
 		}
 		return NoState
 	},
@@ -600,21 +610,23 @@ var TransTab = TransitionTable{
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 79
+			return 43
 		}
 		return NoState
 	},
 	// S44
 	func(r rune) int {
 		switch {
-		case r == 110: // ['n','n']
-			return 80
+		case 48 <= r && r <= 57: // ['0','9']
+			return 82
 		}
 		return NoState
 	},
 	// S45
 	func(r rune) int {
 		switch {
+		case r == 110: // ['n','n']
+			return 83
 		}
 		return NoState
 	},
@@ -649,1453 +661,1625 @@ var TransTab = TransitionTable{
 		return NoState
 	},
 	// S51
+	func(r rune) int {
+		switch {
+		}
+		return NoState
+	},
+	// S52
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 51
+			return 52
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 51
+			return 52
 		case r == 95: // ['_','_']
-			return 51
+			return 52
 		case 97 <= r && r <= 122: // ['a','z']
-			return 51
+			return 52
 		}
 		return NoState
 	},
-	// S52
+	// S53
 	func(r rune) int {
 		switch {
 		case r == 37: // ['%','%']
-			return 81
+			return 84
 		}
 		return NoState
 	},
-	// S53
+	// S54
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S54
+	// S55
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 99: // ['a','c']
-			return 53
+			return 54
 		case r == 100: // ['d','d']
-			return 82
+			return 85
 		case 101 <= r && r <= 122: // ['e','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S55
+	// S56
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 110: // ['a','n']
-			return 53
+			return 54
 		case r == 111: // ['o','o']
-			return 83
+			return 86
 		case 112 <= r && r <= 122: // ['p','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S56
+	// S57
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 114: // ['a','r']
-			return 53
+			return 54
 		case r == 115: // ['s','s']
-			return 84
+			return 87
 		case 116 <= r && r <= 122: // ['t','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S57
+	// S58
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 107: // ['a','k']
-			return 53
+			return 54
 		case r == 108: // ['l','l']
-			return 85
+			return 88
 		case 109 <= r && r <= 122: // ['m','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S58
+	// S59
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S59
+	// S60
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
+		case 97 <= r && r <= 111: // ['a','o']
+			return 54
+		case r == 112: // ['p','p']
+			return 89
+		case 113 <= r && r <= 122: // ['q','z']
+			return 54
+		}
+		return NoState
+	},
+	// S61
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 54
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 54
+		case r == 95: // ['_','_']
+			return 54
 		case 97 <= r && r <= 115: // ['a','s']
-			return 53
+			return 54
 		case r == 116: // ['t','t']
-			return 86
+			return 90
 		case 117 <= r && r <= 122: // ['u','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S60
+	// S62
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 120: // ['a','x']
-			return 53
+			return 54
 		case r == 121: // ['y','y']
-			return 87
+			return 91
 		case r == 122: // ['z','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S61
+	// S63
 	func(r rune) int {
 		switch {
 		case r == 48: // ['0','0']
-			return 53
+			return 54
 		case r == 49: // ['1','1']
-			return 88
+			return 92
 		case r == 50: // ['2','2']
-			return 89
+			return 93
 		case 51 <= r && r <= 57: // ['3','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S62
+	// S64
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 104: // ['a','h']
-			return 53
+			return 54
 		case r == 105: // ['i','i']
-			return 90
+			return 94
 		case 106 <= r && r <= 122: // ['j','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S63
+	// S65
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 109: // ['a','m']
-			return 53
+			return 54
 		case r == 110: // ['n','n']
-			return 91
+			return 95
 		case 111 <= r && r <= 122: // ['o','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S64
+	// S66
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 115: // ['a','s']
-			return 53
+			return 54
 		case r == 116: // ['t','t']
-			return 92
+			return 96
 		case 117 <= r && r <= 122: // ['u','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S65
+	// S67
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 114: // ['a','r']
-			return 53
+			return 54
 		case r == 115: // ['s','s']
-			return 93
+			return 97
 		case 116 <= r && r <= 122: // ['t','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S66
+	// S68
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 113: // ['a','q']
-			return 53
+			return 54
 		case r == 114: // ['r','r']
-			return 70
+			return 73
 		case 115 <= r && r <= 122: // ['s','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S67
+	// S69
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
+		case 97 <= r && r <= 111: // ['a','o']
+			return 54
+		case r == 112: // ['p','p']
+			return 98
+		case 113 <= r && r <= 122: // ['q','z']
+			return 54
+		}
+		return NoState
+	},
+	// S70
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 54
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 54
+		case r == 95: // ['_','_']
+			return 54
 		case 97 <= r && r <= 115: // ['a','s']
-			return 53
+			return 54
 		case r == 116: // ['t','t']
-			return 94
+			return 99
 		case 117 <= r && r <= 122: // ['u','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S68
+	// S71
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 115: // ['a','s']
-			return 53
+			return 54
 		case r == 116: // ['t','t']
-			return 95
+			return 100
 		case 117 <= r && r <= 122: // ['u','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S69
+	// S72
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 100: // ['a','d']
-			return 53
+			return 54
 		case r == 101: // ['e','e']
-			return 96
+			return 101
 		case 102 <= r && r <= 115: // ['f','s']
-			return 53
+			return 54
 		case r == 116: // ['t','t']
-			return 97
+			return 102
 		case 117 <= r && r <= 122: // ['u','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S70
+	// S73
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S71
+	// S74
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 113: // ['a','q']
-			return 53
+			return 54
 		case r == 114: // ['r','r']
-			return 98
+			return 103
 		case 115 <= r && r <= 122: // ['s','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S72
+	// S75
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 100: // ['a','d']
-			return 53
+			return 54
 		case r == 101: // ['e','e']
-			return 99
+			return 104
 		case 102 <= r && r <= 122: // ['f','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S73
+	// S76
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 116: // ['a','t']
-			return 53
+			return 54
 		case r == 117: // ['u','u']
-			return 100
+			return 105
 		case 118 <= r && r <= 122: // ['v','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S74
+	// S77
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 111: // ['a','o']
-			return 53
+			return 54
 		case r == 112: // ['p','p']
-			return 101
+			return 106
 		case 113 <= r && r <= 122: // ['q','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S75
+	// S78
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 104: // ['a','h']
-			return 53
+			return 54
 		case r == 105: // ['i','i']
-			return 102
+			return 107
 		case 106 <= r && r <= 122: // ['j','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S76
+	// S79
 	func(r rune) int {
 		switch {
 		}
 		return NoState
 	},
-	// S77
+	// S80
 	func(r rune) int {
 		switch {
 		}
 		return NoState
 	},
-	// S78
+	// S81
 	func(r rune) int {
 		switch {
 		case r == 41: // [')',')']
-			return 103
+			return 108
 		}
 		return NoState
 	},
-	// S79
+	// S82
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 79
+			return 82
 		case r == 107: // ['k','k']
-			return 104
+			return 109
 		}
 		return NoState
 	},
-	// S80
+	// S83
 	func(r rune) int {
 		switch {
 		}
 		return NoState
 	},
-	// S81
+	// S84
 	func(r rune) int {
 		switch {
 		case r == 118: // ['v','v']
-			return 105
+			return 110
 		}
 		return NoState
 	},
-	// S82
+	// S85
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 113: // ['a','q']
-			return 53
+			return 54
 		case r == 114: // ['r','r']
-			return 106
+			return 111
 		case 115 <= r && r <= 122: // ['s','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S83
+	// S86
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 107: // ['a','k']
-			return 53
+			return 54
 		case r == 108: // ['l','l']
-			return 107
+			return 112
 		case 109 <= r && r <= 122: // ['m','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S84
+	// S87
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 100: // ['a','d']
-			return 53
+			return 54
 		case r == 101: // ['e','e']
-			return 108
+			return 113
 		case 102 <= r && r <= 122: // ['f','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S85
+	// S88
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 114: // ['a','r']
-			return 53
+			return 54
 		case r == 115: // ['s','s']
-			return 109
+			return 114
 		case 116 <= r && r <= 122: // ['t','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S86
+	// S89
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
-		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
+		case 97 <= r && r <= 110: // ['a','n']
+			return 54
+		case r == 111: // ['o','o']
+			return 115
+		case 112 <= r && r <= 122: // ['p','z']
+			return 54
 		}
 		return NoState
 	},
-	// S87
+	// S90
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S88
+	// S91
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 110
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
-		case 97 <= r && r <= 102: // ['a','f']
-			return 110
-		case 103 <= r && r <= 122: // ['g','z']
-			return 53
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 54
 		}
 		return NoState
 	},
-	// S89
+	// S92
 	func(r rune) int {
 		switch {
-		case 48 <= r && r <= 57: // ['0','9']
-			return 111
-		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+		case r == 48: // ['0','0']
+			return 54
+		case 49 <= r && r <= 57: // ['1','9']
+			return 116
+		case 65 <= r && r <= 72: // ['A','H']
+			return 116
+		case r == 73: // ['I','I']
+			return 54
+		case 74 <= r && r <= 78: // ['J','N']
+			return 116
+		case r == 79: // ['O','O']
+			return 54
+		case 80 <= r && r <= 90: // ['P','Z']
+			return 116
 		case r == 95: // ['_','_']
-			return 53
-		case 97 <= r && r <= 102: // ['a','f']
-			return 111
-		case 103 <= r && r <= 122: // ['g','z']
-			return 53
+			return 54
+		case 97 <= r && r <= 107: // ['a','k']
+			return 116
+		case r == 108: // ['l','l']
+			return 54
+		case 109 <= r && r <= 122: // ['m','z']
+			return 116
 		}
 		return NoState
 	},
-	// S90
+	// S93
+	func(r rune) int {
+		switch {
+		case r == 48: // ['0','0']
+			return 54
+		case 49 <= r && r <= 57: // ['1','9']
+			return 117
+		case 65 <= r && r <= 72: // ['A','H']
+			return 117
+		case r == 73: // ['I','I']
+			return 54
+		case 74 <= r && r <= 78: // ['J','N']
+			return 117
+		case r == 79: // ['O','O']
+			return 54
+		case 80 <= r && r <= 90: // ['P','Z']
+			return 117
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 107: // ['a','k']
+			return 117
+		case r == 108: // ['l','l']
+			return 54
+		case 109 <= r && r <= 122: // ['m','z']
+			return 117
+		}
+		return NoState
+	},
+	// S94
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 109: // ['a','m']
-			return 53
+			return 54
 		case r == 110: // ['n','n']
-			return 112
+			return 118
 		case 111 <= r && r <= 122: // ['o','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S91
+	// S95
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 99: // ['a','c']
-			return 53
+			return 54
 		case r == 100: // ['d','d']
-			return 113
+			return 119
 		case 101 <= r && r <= 122: // ['e','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S92
+	// S96
 	func(r rune) int {
 		switch {
 		case r == 37: // ['%','%']
-			return 114
+			return 120
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S93
+	// S97
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 115: // ['a','s']
-			return 53
+			return 54
 		case r == 116: // ['t','t']
-			return 115
+			return 121
 		case 117 <= r && r <= 122: // ['u','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S94
+	// S98
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S95
+	// S99
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S96
+	// S100
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 54
+		}
+		return NoState
+	},
+	// S101
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 54
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 54
+		case r == 95: // ['_','_']
+			return 54
 		case 97 <= r && r <= 113: // ['a','q']
-			return 53
+			return 54
 		case r == 114: // ['r','r']
-			return 116
+			return 122
 		case 115 <= r && r <= 122: // ['s','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S97
+	// S102
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 104: // ['a','h']
-			return 53
+			return 54
 		case r == 105: // ['i','i']
-			return 117
+			return 123
 		case 106 <= r && r <= 122: // ['j','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S98
+	// S103
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 104: // ['a','h']
-			return 53
+			return 54
 		case r == 105: // ['i','i']
-			return 118
+			return 124
 		case 106 <= r && r <= 122: // ['j','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S99
+	// S104
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 109: // ['a','m']
-			return 53
+			return 54
 		case r == 110: // ['n','n']
-			return 119
+			return 125
 		case 111 <= r && r <= 122: // ['o','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S100
+	// S105
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 100: // ['a','d']
-			return 53
+			return 54
 		case r == 101: // ['e','e']
-			return 120
+			return 126
 		case 102 <= r && r <= 122: // ['f','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S101
+	// S106
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 100: // ['a','d']
-			return 53
+			return 54
 		case r == 101: // ['e','e']
-			return 121
+			return 127
 		case 102 <= r && r <= 122: // ['f','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S102
+	// S107
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 115: // ['a','s']
-			return 53
+			return 54
 		case r == 116: // ['t','t']
-			return 122
+			return 128
 		case 117 <= r && r <= 122: // ['u','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S103
+	// S108
 	func(r rune) int {
 		switch {
 		}
 		return NoState
 	},
-	// S104
+	// S109
 	func(r rune) int {
 		switch {
 		case r == 110: // ['n','n']
-			return 80
+			return 83
 		}
 		return NoState
 	},
-	// S105
+	// S110
 	func(r rune) int {
 		switch {
 		case r == 101: // ['e','e']
-			return 123
+			return 129
 		}
 		return NoState
 	},
-	// S106
+	// S111
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 100: // ['a','d']
-			return 53
+			return 54
 		case r == 101: // ['e','e']
-			return 124
+			return 130
 		case 102 <= r && r <= 122: // ['f','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S107
+	// S112
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S108
+	// S113
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S109
+	// S114
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 100: // ['a','d']
-			return 53
+			return 54
 		case r == 101: // ['e','e']
-			return 125
+			return 131
 		case 102 <= r && r <= 122: // ['f','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S110
+	// S115
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 110
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
-		case 97 <= r && r <= 102: // ['a','f']
-			return 110
-		case 103 <= r && r <= 122: // ['g','z']
-			return 53
+			return 54
+		case 97 <= r && r <= 113: // ['a','q']
+			return 54
+		case r == 114: // ['r','r']
+			return 132
+		case 115 <= r && r <= 122: // ['s','z']
+			return 54
 		}
 		return NoState
 	},
-	// S111
+	// S116
 	func(r rune) int {
 		switch {
-		case 48 <= r && r <= 57: // ['0','9']
-			return 111
-		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+		case r == 48: // ['0','0']
+			return 54
+		case 49 <= r && r <= 57: // ['1','9']
+			return 116
+		case 65 <= r && r <= 72: // ['A','H']
+			return 116
+		case r == 73: // ['I','I']
+			return 54
+		case 74 <= r && r <= 78: // ['J','N']
+			return 116
+		case r == 79: // ['O','O']
+			return 54
+		case 80 <= r && r <= 90: // ['P','Z']
+			return 116
 		case r == 95: // ['_','_']
-			return 53
-		case 97 <= r && r <= 102: // ['a','f']
-			return 111
-		case 103 <= r && r <= 122: // ['g','z']
-			return 53
+			return 54
+		case 97 <= r && r <= 107: // ['a','k']
+			return 116
+		case r == 108: // ['l','l']
+			return 54
+		case 109 <= r && r <= 122: // ['m','z']
+			return 116
 		}
 		return NoState
 	},
-	// S112
+	// S117
+	func(r rune) int {
+		switch {
+		case r == 48: // ['0','0']
+			return 54
+		case 49 <= r && r <= 57: // ['1','9']
+			return 117
+		case 65 <= r && r <= 72: // ['A','H']
+			return 117
+		case r == 73: // ['I','I']
+			return 54
+		case 74 <= r && r <= 78: // ['J','N']
+			return 117
+		case r == 79: // ['O','O']
+			return 54
+		case 80 <= r && r <= 90: // ['P','Z']
+			return 117
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 107: // ['a','k']
+			return 117
+		case r == 108: // ['l','l']
+			return 54
+		case 109 <= r && r <= 122: // ['m','z']
+			return 117
+		}
+		return NoState
+	},
+	// S118
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S113
+	// S119
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S114
+	// S120
 	func(r rune) int {
 		switch {
 		case r == 101: // ['e','e']
-			return 126
+			return 133
 		case r == 105: // ['i','i']
-			return 127
+			return 134
 		}
 		return NoState
 	},
-	// S115
+	// S121
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S116
+	// S122
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case r == 97: // ['a','a']
-			return 128
+			return 135
 		case 98 <= r && r <= 122: // ['b','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S117
+	// S123
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 110: // ['a','n']
-			return 53
+			return 54
 		case r == 111: // ['o','o']
-			return 129
+			return 136
 		case 112 <= r && r <= 122: // ['p','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S118
+	// S124
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 109: // ['a','m']
-			return 53
+			return 54
 		case r == 110: // ['n','n']
-			return 130
+			return 137
 		case 111 <= r && r <= 122: // ['o','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S119
+	// S125
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S120
+	// S126
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S121
+	// S127
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S122
+	// S128
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S123
+	// S129
 	func(r rune) int {
 		switch {
 		case r == 114: // ['r','r']
-			return 131
+			return 138
 		}
 		return NoState
 	},
-	// S124
+	// S130
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 114: // ['a','r']
-			return 53
+			return 54
 		case r == 115: // ['s','s']
-			return 132
+			return 139
 		case 116 <= r && r <= 122: // ['t','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S125
+	// S131
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S126
+	// S132
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 54
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 54
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 115: // ['a','s']
+			return 54
+		case r == 116: // ['t','t']
+			return 140
+		case 117 <= r && r <= 122: // ['u','z']
+			return 54
+		}
+		return NoState
+	},
+	// S133
 	func(r rune) int {
 		switch {
 		case r == 110: // ['n','n']
-			return 133
+			return 141
 		}
 		return NoState
 	},
-	// S127
+	// S134
 	func(r rune) int {
 		switch {
 		case r == 110: // ['n','n']
-			return 134
+			return 142
 		}
 		return NoState
 	},
-	// S128
+	// S135
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 115: // ['a','s']
-			return 53
+			return 54
 		case r == 116: // ['t','t']
-			return 135
+			return 143
 		case 117 <= r && r <= 122: // ['u','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S129
+	// S136
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 109: // ['a','m']
-			return 53
+			return 54
 		case r == 110: // ['n','n']
-			return 136
+			return 144
 		case 111 <= r && r <= 122: // ['o','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S130
+	// S137
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 102: // ['a','f']
-			return 53
+			return 54
 		case r == 103: // ['g','g']
-			return 137
+			return 145
 		case 104 <= r && r <= 122: // ['h','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S131
+	// S138
 	func(r rune) int {
 		switch {
 		case r == 115: // ['s','s']
-			return 138
+			return 146
 		}
 		return NoState
 	},
-	// S132
+	// S139
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 114: // ['a','r']
-			return 53
+			return 54
 		case r == 115: // ['s','s']
-			return 139
+			return 147
 		case 116 <= r && r <= 122: // ['t','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S133
+	// S140
+	func(r rune) int {
+		switch {
+		case 48 <= r && r <= 57: // ['0','9']
+			return 54
+		case 65 <= r && r <= 90: // ['A','Z']
+			return 54
+		case r == 95: // ['_','_']
+			return 54
+		case 97 <= r && r <= 122: // ['a','z']
+			return 54
+		}
+		return NoState
+	},
+	// S141
 	func(r rune) int {
 		switch {
 		case r == 116: // ['t','t']
-			return 140
+			return 148
 		}
 		return NoState
 	},
-	// S134
+	// S142
 	func(r rune) int {
 		switch {
 		case r == 105: // ['i','i']
-			return 141
+			return 149
 		}
 		return NoState
 	},
-	// S135
+	// S143
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 104: // ['a','h']
-			return 53
+			return 54
 		case r == 105: // ['i','i']
-			return 142
+			return 150
 		case 106 <= r && r <= 122: // ['j','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S136
+	// S144
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S137
+	// S145
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S138
+	// S146
 	func(r rune) int {
 		switch {
 		case r == 105: // ['i','i']
-			return 143
+			return 151
 		}
 		return NoState
 	},
-	// S139
+	// S147
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S140
+	// S148
 	func(r rune) int {
 		switch {
 		case r == 114: // ['r','r']
-			return 144
+			return 152
 		}
 		return NoState
 	},
-	// S141
+	// S149
 	func(r rune) int {
 		switch {
 		case r == 116: // ['t','t']
-			return 145
+			return 153
 		}
 		return NoState
 	},
-	// S142
+	// S150
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 110: // ['a','n']
-			return 53
+			return 54
 		case r == 111: // ['o','o']
-			return 146
+			return 154
 		case 112 <= r && r <= 122: // ['p','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S143
+	// S151
 	func(r rune) int {
 		switch {
 		case r == 111: // ['o','o']
-			return 147
+			return 155
 		}
 		return NoState
 	},
-	// S144
+	// S152
 	func(r rune) int {
 		switch {
 		case r == 121: // ['y','y']
-			return 148
+			return 156
 		}
 		return NoState
 	},
-	// S145
+	// S153
 	func(r rune) int {
 		switch {
 		}
 		return NoState
 	},
-	// S146
+	// S154
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 109: // ['a','m']
-			return 53
+			return 54
 		case r == 110: // ['n','n']
-			return 149
+			return 157
 		case 111 <= r && r <= 122: // ['o','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S147
+	// S155
 	func(r rune) int {
 		switch {
 		case r == 110: // ['n','n']
-			return 150
+			return 158
 		}
 		return NoState
 	},
-	// S148
+	// S156
 	func(r rune) int {
 		switch {
 		}
 		return NoState
 	},
-	// S149
+	// S157
 	func(r rune) int {
 		switch {
 		case 48 <= r && r <= 57: // ['0','9']
-			return 53
+			return 54
 		case 65 <= r && r <= 90: // ['A','Z']
-			return 53
+			return 54
 		case r == 95: // ['_','_']
-			return 53
+			return 54
 		case 97 <= r && r <= 122: // ['a','z']
-			return 53
+			return 54
 		}
 		return NoState
 	},
-	// S150
+	// S158
 	func(r rune) int {
 		switch {
 		case r == 32: // [' ',' ']
-			return 151
+			return 159
 		}
 		return NoState
 	},
-	// S151
+	// S159
 	func(r rune) int {
 		switch {
 		case r == 93: // [']',']']
-			return 152
+			return 160
 		default:
-			return 151
+			return 159
 		}
 	},
-	// S152
+	// S160
 	func(r rune) int {
 		switch {
 		}
@@ -0,0 +1,190 @@
+package interpreter
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/mndrix/ps"
+	. "github.com/nfk93/blockchain/smart/interpreter/ast"
+	"github.com/nfk93/blockchain/smart/interpreter/lexer"
+	"github.com/nfk93/blockchain/smart/interpreter/parser"
+	"github.com/nfk93/blockchain/smart/interpreter/token"
+	"github.com/nfk93/blockchain/smart/interpreter/value"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// maxImportDepth limits how deep libraries can import other libraries
+const maxImportDepth = 8
+
+// LibraryResolver returns the code of the contract deployed at address, if it exists
+type LibraryResolver func(address string) ([]byte, bool)
+
+// libraryModule is the value of an imported module. env holds the helpers of the library
+type libraryModule struct {
+	env VarEnv
+}
+
+func (m libraryModule) Size() uint64 {
+	return 0
+}
+
+// helperVal is a helper function of a library, together with the environment it was declared in
+type helperVal struct {
+	helper HelperDecl
+	env    VarEnv
+}
+
+func (h helperVal) Size() uint64 {
+	return 0
+}
+
+// moduleEnv returns a variable environment containing the modules imported by the given toplevel roots
+func moduleEnv(roots []Exp) VarEnv {
+	venv := ps.NewMap()
+	for _, root := range roots {
+		switch e := root.(TypedExp).Exp.(type) {
+		case ImportDecl:
+			venv = venv.Set(e.ModId, libraryModule{libraryEnv(e.Library.(TypedExp).Exp.(TopLevel).Roots)})
+		}
+	}
+	return venv
+}
+
+// libraryEnv returns the environment of a library, i.e. its helpers and the modules it imports itself. Every helper
+// is bound to the modules imported by the library, never the ones of the importer.
+func libraryEnv(roots []Exp) VarEnv {
+	modules := moduleEnv(roots)
+	venv := modules
+	for _, root := range roots {
+		switch e := root.(TypedExp).Exp.(type) {
+		case HelperDecl:
+			venv = venv.Set(e.Id, helperVal{e, modules})
+		}
+	}
+	return venv
+}
+
+func lookupHelper(modId, fieldId string, venv VarEnv, gas uint64) helperVal {
+	module, ok := lookupVar(modId, venv).(libraryModule)
+	if !ok {
+		interpPanic(fmt.Sprintf("module %s does not exist", modId), gas)
+	}
+	helper, ok := lookupVar(fieldId, module.env).(helperVal)
+	if !ok {
+		interpPanic(fmt.Sprintf("module %s has no helper %s", modId, fieldId), gas)
+	}
+	return helper
+}
+
+// callHelper interprets the body of a helper with the arguments bound to its parameters. Library code is charged
+// gas exactly like the code of the calling contract
+func callHelper(helper helperVal, args []Exp, venv VarEnv, gas uint64) (value.Value, uint64) {
+	argvals := make([]value.Value, 0)
+	for _, arg := range args {
+		argval, gas_ := interpret(arg.(TypedExp), venv, gas)
+		gas = gas_
+		argvals = append(argvals, argval)
+	}
+	var params value.Value
+	if len(argvals) == 1 {
+		params = argvals[0]
+	} else {
+		params = value.TupleVal{argvals}
+	}
+	helperEnv, err := applyParams(params, helper.helper.Params, helper.env)
+	if err != nil {
+		interpPanic(err.Error(), gas)
+	}
+	return interpret(helper.helper.Body.(TypedExp), helperEnv, gas)
+}
+
+// resolveImports replaces imports of deployed libraries with the parsed code of the library
+func resolveImports(exp Exp, libraries LibraryResolver, depth int) (Exp, error) {
+	toplevel := exp.(TopLevel)
+	roots := make([]Exp, len(toplevel.Roots))
+	for i, root := range toplevel.Roots {
+		roots[i] = root
+		imp, ok := root.(ImportDecl)
+		if !ok {
+			continue
+		}
+		if depth >= maxImportDepth {
+			return nil, fmt.Errorf("imports are nested too deep")
+		}
+		if imp.Library == nil && imp.Address != "" {
+			var code []byte
+			var exists bool
+			if libraries != nil {
+				code, exists = libraries(imp.Address)
+			}
+			if !exists {
+				return nil, fmt.Errorf("library %s imported as %s does not exist", imp.Address, imp.ModId)
+			}
+			library, err := parser.NewParser().Parse(lexer.NewLexer(code))
+			if err != nil {
+				return nil, fmt.Errorf("syntax error in library %s: %s", imp.Address, err.Error())
+			}
+			imp.Library = library.(Exp)
+		}
+		if imp.Library != nil {
+			library, err := resolveImports(imp.Library, libraries, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			imp.Library = library
+		}
+		roots[i] = imp
+	}
+	return TopLevel{roots}, nil
+}
+
+// BundleImports replaces every file import in code by the inline library read from the file, so the contract can be
+// deployed. Paths are relative to dir, and files imported by a bundled file are relative to that file.
+func BundleImports(code []byte, dir string) ([]byte, error) {
+	return bundleImports(code, dir, make(map[string]bool))
+}
+
+func bundleImports(code []byte, dir string, importing map[string]bool) ([]byte, error) {
+	importkw := token.TokMap.Type("importkw")
+	uident := token.TokMap.Type("uident")
+	eq := token.TokMap.Type("eq")
+	stringLit := token.TokMap.Type("string_lit")
+
+	tokens := make([]*token.Token, 0)
+	lex := lexer.NewLexer(code)
+	for tok := lex.Scan(); tok.Type != token.EOF; tok = lex.Scan() {
+		tokens = append(tokens, tok)
+	}
+
+	var bundled bytes.Buffer
+	last := 0
+	for i := 0; i+3 < len(tokens); i++ {
+		if tokens[i].Type != importkw || tokens[i+1].Type != uident || tokens[i+2].Type != eq ||
+			tokens[i+3].Type != stringLit {
+			continue
+		}
+		file := string(tokens[i+3].Lit)
+		path := filepath.Join(dir, file[1:len(file)-1])
+		if importing[path] {
+			return nil, fmt.Errorf("%s imports itself", path)
+		}
+		librarycode, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("can't read library %s: %s", path, err.Error())
+		}
+		importing[path] = true
+		library, err := bundleImports(librarycode, filepath.Dir(path), importing)
+		delete(importing, path)
+		if err != nil {
+			return nil, err
+		}
+		offset := tokens[i+3].Pos.Offset
+		bundled.Write(code[last:offset])
+		bundled.WriteString("{\n")
+		bundled.Write(library)
+		bundled.WriteString("\n}")
+		last = offset + len(tokens[i+3].Lit)
+	}
+	bundled.Write(code[last:])
+	return bundled.Bytes(), nil
+}
@@ -11,9 +11,9 @@ import (
 )
 
 const (
-	numProductions = 114
-	numStates      = 3654
-	numSymbols     = 94
+	numProductions = 121
+	numStates      = 4130
+	numSymbols     = 97
 )
 
 // Stack
@@ -81,11 +81,61 @@ var productionsTable = ProdTab{
 			return ast.NewEntryExpression(util.ParseId(X[1]), X[2], X[3], X[5]) //
 		},
 	},
+	ProdTabEntry{
+		String: `Structure : let lident Pattern eq Exp	<< ast.NewHelperDecl(util.ParseId(X[1]), X[2], X[4]) // >>`,
+		Id:         "Structure",
+		NTType:     2,
+		Index:      6,
+		NumSymbols: 5,
+		ReduceFunc: func(X []Attrib) (Attrib, error) {
+			return ast.NewHelperDecl(util.ParseId(X[1]), X[2], X[4]) //
+		},
+	},
+	ProdTabEntry{
+		String: `Structure : Import	<< X[0], nil >>`,
+		Id:         "Structure",
+		NTType:     2,
+		Index:      7,
+		NumSymbols: 1,
+		ReduceFunc: func(X []Attrib) (Attrib, error) {
+			return X[0], nil
+		},
+	},
+	ProdTabEntry{
+		String: `Import : importkw uident eq address_lit	<< ast.NewAddressImport(util.ParseAddressImport(X[1], X[3])) // >>`,
+		Id:         "Import",
+		NTType:     3,
+		Index:      8,
+		NumSymbols: 4,
+		ReduceFunc: func(X []Attrib) (Attrib, error) {
+			return ast.NewAddressImport(util.ParseAddressImport(X[1], X[3])) //
+		},
+	},
+	ProdTabEntry{
+		String: `Import : importkw uident eq string_lit	<< ast.NewFileImport(util.ParseId(X[1]), util.ParseString(X[3])) // >>`,
+		Id:         "Import",
+		NTType:     3,
+		Index:      9,
+		NumSymbols: 4,
+		ReduceFunc: func(X []Attrib) (Attrib, error) {
+			return ast.NewFileImport(util.ParseId(X[1]), util.ParseString(X[3])) //
+		},
+	},
+	ProdTabEntry{
+		String: `Import : importkw uident eq lbrace Toplevel rbrace	<< ast.NewInlineImport(util.ParseId(X[1]), X[4]) // >>`,
+		Id:         "Import",
+		NTType:     3,
+		Index:      10,
+		NumSymbols: 6,
+		ReduceFunc: func(X []Attrib) (Attrib, error) {
+			return ast.NewInlineImport(util.ParseId(X[1]), X[4]) //
+		},
+	},
 	ProdTabEntry{
 		String: `ModStruct : type lident eq Type	<< ast.NewTypeDecl(util.ParseId(X[1]), X[3]) // >>`,
 		Id:         "ModStruct",
-		NTType:     3,
-		Index:      6,
+		NTType:     4,
+		Index:      11,
 		NumSymbols: 4,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewTypeDecl(util.ParseId(X[1]), X[3]) //
@@ -94,8 +144,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `ModStruct : type lident eq lbrace Struct rbrace	<< ast.NewTypeDecl(util.ParseId(X[1]), X[4]) // >>`,
 		Id:         "ModStruct",
-		NTType:     3,
-		Index:      7,
+		NTType:     4,
+		Index:      12,
 		NumSymbols: 6,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewTypeDecl(util.ParseId(X[1]), X[4]) //
@@ -104,8 +154,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Struct : lident colon Type semicolon Struct	<< ast.AddFieldToStruct(util.ParseId(X[0]), X[2], X[4]), nil // >>`,
 		Id:         "Struct",
-		NTType:     4,
-		Index:      8,
+		NTType:     5,
+		Index:      13,
 		NumSymbols: 5,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.AddFieldToStruct(util.ParseId(X[0]), X[2], X[4]), nil //
@@ -114,8 +164,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Struct : lident colon Type semicolon	<< ast.NewStructType(util.ParseId(X[0]), X[2]), nil // >>`,
 		Id:         "Struct",
-		NTType:     4,
-		Index:      9,
+		NTType:     5,
+		Index:      14,
 		NumSymbols: 4,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewStructType(util.ParseId(X[0]), X[2]), nil //
@@ -124,8 +174,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp : Exp1	<< X[0], nil >>`,
 		Id:         "Exp",
-		NTType:     5,
-		Index:      10,
+		NTType:     6,
+		Index:      15,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -134,8 +184,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp : Exp1 semicolon Exp	<< ast.NewExpSeq(X[0], X[2]) // seqexp >>`,
 		Id:         "Exp",
-		NTType:     5,
-		Index:      11,
+		NTType:     6,
+		Index:      16,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewExpSeq(X[0], X[2]) // seqexp
@@ -144,8 +194,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : CallExp	<< X[0], nil >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      12,
+		NTType:     7,
+		Index:      17,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -154,8 +204,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : VarExp	<< X[0], nil >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      13,
+		NTType:     7,
+		Index:      18,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -164,8 +214,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : AnnoExp	<< X[0], nil >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      14,
+		NTType:     7,
+		Index:      19,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -174,8 +224,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : ParenthExp	<< X[0], nil >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      15,
+		NTType:     7,
+		Index:      20,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -184,8 +234,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : UpdStruct	<< X[0], nil >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      16,
+		NTType:     7,
+		Index:      21,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -194,8 +244,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : LookupExp	<< X[0], nil >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      17,
+		NTType:     7,
+		Index:      22,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -204,8 +254,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : ModLookup	<< X[0], nil >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      18,
+		NTType:     7,
+		Index:      23,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -214,8 +264,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : if Exp1 then Exp1 else Exp1	<< ast.NewIfThenElseExp(X[1], X[3], X[5]) // ifthenelse exp >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      19,
+		NTType:     7,
+		Index:      24,
 		NumSymbols: 6,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewIfThenElseExp(X[1], X[3], X[5]) // ifthenelse exp
@@ -224,8 +274,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : if Exp1 then Exp1	<< ast.NewIfThenExp(X[1], X[3]) // ifthen exp >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      20,
+		NTType:     7,
+		Index:      25,
 		NumSymbols: 4,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewIfThenExp(X[1], X[3]) // ifthen exp
@@ -234,8 +284,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : Exp1 concat Exp1	<< ast.NewListConcat(X[0], X[2]) // >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      21,
+		NTType:     7,
+		Index:      26,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewListConcat(X[0], X[2]) //
@@ -244,8 +294,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : let Pattern eq Exp in Exp1	<< ast.NewLetExp(X[1], X[3], X[5]) // letexp >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      22,
+		NTType:     7,
+		Index:      27,
 		NumSymbols: 6,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewLetExp(X[1], X[3], X[5]) // letexp
@@ -254,8 +304,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : BinOpExp	<< X[0], nil >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      23,
+		NTType:     7,
+		Index:      28,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -264,8 +314,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : UnopExp	<< X[0], nil >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      24,
+		NTType:     7,
+		Index:      29,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -274,8 +324,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Exp1 : Constant	<< X[0], nil >>`,
 		Id:         "Exp1",
-		NTType:     6,
-		Index:      25,
+		NTType:     7,
+		Index:      30,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -284,8 +334,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `ModLookup : uident dot lident	<< ast.NewModuleLookupExp(util.ParseId(X[0]), util.ParseId(X[2])) // external lookup exp >>`,
 		Id:         "ModLookup",
-		NTType:     7,
-		Index:      26,
+		NTType:     8,
+		Index:      31,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewModuleLookupExp(util.ParseId(X[0]), util.ParseId(X[2])) // external lookup exp
@@ -294,8 +344,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `AnnoExp : lparen Exp1 colon Type rparen	<< ast.NewAnnoExp(X[1], X[3]) // annotatedExp >>`,
 		Id:         "AnnoExp",
-		NTType:     8,
-		Index:      27,
+		NTType:     9,
+		Index:      32,
 		NumSymbols: 5,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewAnnoExp(X[1], X[3]) // annotatedExp
@@ -304,8 +354,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `UpdStruct : Lookup lident larrow Exp	<< ast.NewUpdateStructExp(X[0], util.ParseId(X[1]), X[3]) // lookupexp >>`,
 		Id:         "UpdStruct",
-		NTType:     9,
-		Index:      28,
+		NTType:     10,
+		Index:      33,
 		NumSymbols: 4,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewUpdateStructExp(X[0], util.ParseId(X[1]), X[3]) // lookupexp
@@ -314,8 +364,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `VarExp : lident	<< ast.NewVarExp(util.ParseId(X[0])) // idexp >>`,
 		Id:         "VarExp",
-		NTType:     10,
-		Index:      29,
+		NTType:     11,
+		Index:      34,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewVarExp(util.ParseId(X[0])) // idexp
@@ -324,8 +374,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `CallExp : CallExp1	<< ast.NewCallExp(X[0]) >>`,
 		Id:         "CallExp",
-		NTType:     11,
-		Index:      30,
+		NTType:     12,
+		Index:      35,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewCallExp(X[0])
@@ -334,8 +384,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `CallExp1 : CallExp1 CallExp2	<< ast.ConcatExpList(X[0], X[1]) >>`,
 		Id:         "CallExp1",
-		NTType:     12,
-		Index:      31,
+		NTType:     13,
+		Index:      36,
 		NumSymbols: 2,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.ConcatExpList(X[0], X[1])
@@ -344,8 +394,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `CallExp1 : CallHead CallExp2	<< ast.NewExpList(X[0], X[1]) >>`,
 		Id:         "CallExp1",
-		NTType:     12,
-		Index:      32,
+		NTType:     13,
+		Index:      37,
 		NumSymbols: 2,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewExpList(X[0], X[1])
@@ -354,8 +404,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `CallHead : ModLookup	<< X[0], nil >>`,
 		Id:         "CallHead",
-		NTType:     13,
-		Index:      33,
+		NTType:     14,
+		Index:      38,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -364,8 +414,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `CallExp2 : VarExp	<< X[0], nil >>`,
 		Id:         "CallExp2",
-		NTType:     14,
-		Index:      34,
+		NTType:     15,
+		Index:      39,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -374,8 +424,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `CallExp2 : AnnoExp	<< X[0], nil >>`,
 		Id:         "CallExp2",
-		NTType:     14,
-		Index:      35,
+		NTType:     15,
+		Index:      40,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -384,8 +434,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `CallExp2 : ParenthExp	<< X[0], nil >>`,
 		Id:         "CallExp2",
-		NTType:     14,
-		Index:      36,
+		NTType:     15,
+		Index:      41,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -394,8 +444,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `CallExp2 : LookupExp	<< X[0], nil >>`,
 		Id:         "CallExp2",
-		NTType:     14,
-		Index:      37,
+		NTType:     15,
+		Index:      42,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -404,8 +454,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `CallExp2 : Constant	<< X[0], nil >>`,
 		Id:         "CallExp2",
-		NTType:     14,
-		Index:      38,
+		NTType:     15,
+		Index:      43,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -414,8 +464,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `ParenthExp : lparen Exp rparen	<< X[1], nil // parenthesis >>`,
 		Id:         "ParenthExp",
-		NTType:     15,
-		Index:      39,
+		NTType:     16,
+		Index:      44,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[1], nil // parenthesis
@@ -424,8 +474,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp : BinOpExp or BinOpExp1	<< ast.NewBinOpExp(X[0], ast.OR, X[2]) // BinopExp >>`,
 		Id:         "BinOpExp",
-		NTType:     16,
-		Index:      40,
+		NTType:     17,
+		Index:      45,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewBinOpExp(X[0], ast.OR, X[2]) // BinopExp
@@ -434,8 +484,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp : BinOpExp1	<< X[0], nil >>`,
 		Id:         "BinOpExp",
-		NTType:     16,
-		Index:      41,
+		NTType:     17,
+		Index:      46,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -444,8 +494,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp1 : BinOpExp1 and BinOpExp2	<< ast.NewBinOpExp(X[0], ast.AND, X[2]) // BinopExp >>`,
 		Id:         "BinOpExp1",
-		NTType:     17,
-		Index:      42,
+		NTType:     18,
+		Index:      47,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewBinOpExp(X[0], ast.AND, X[2]) // BinopExp
@@ -454,8 +504,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp1 : BinOpExp2	<< X[0], nil >>`,
 		Id:         "BinOpExp1",
-		NTType:     17,
-		Index:      43,
+		NTType:     18,
+		Index:      48,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -464,8 +514,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp2 : BinOpExp2 Cmp BinOpExp3	<< ast.NewBinOpExp(X[0], X[1], X[2]) // BinopExp >>`,
 		Id:         "BinOpExp2",
-		NTType:     18,
-		Index:      44,
+		NTType:     19,
+		Index:      49,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewBinOpExp(X[0], X[1], X[2]) // BinopExp
@@ -474,8 +524,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp2 : BinOpExp3	<< X[0], nil >>`,
 		Id:         "BinOpExp2",
-		NTType:     18,
-		Index:      45,
+		NTType:     19,
+		Index:      50,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -484,8 +534,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp3 : BinOpExp3 plus BinOpExp4	<< ast.NewBinOpExp(X[0], ast.PLUS, X[2]) // BinopExp >>`,
 		Id:         "BinOpExp3",
-		NTType:     19,
-		Index:      46,
+		NTType:     20,
+		Index:      51,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewBinOpExp(X[0], ast.PLUS, X[2]) // BinopExp
@@ -494,8 +544,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp3 : BinOpExp3 minus BinOpExp4	<< ast.NewBinOpExp(X[0], ast.MINUS, X[2]) // BinopExp >>`,
 		Id:         "BinOpExp3",
-		NTType:     19,
-		Index:      47,
+		NTType:     20,
+		Index:      52,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewBinOpExp(X[0], ast.MINUS, X[2]) // BinopExp
@@ -504,8 +554,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp3 : BinOpExp4	<< X[0], nil >>`,
 		Id:         "BinOpExp3",
-		NTType:     19,
-		Index:      48,
+		NTType:     20,
+		Index:      53,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -514,8 +564,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp4 : BinOpExp4 ast BinOpExp5	<< ast.NewBinOpExp(X[0], ast.TIMES, X[2]) // BinopExp >>`,
 		Id:         "BinOpExp4",
-		NTType:     20,
-		Index:      49,
+		NTType:     21,
+		Index:      54,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewBinOpExp(X[0], ast.TIMES, X[2]) // BinopExp
@@ -524,8 +574,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp4 : BinOpExp4 slash BinOpExp5	<< ast.NewBinOpExp(X[0], ast.DIVIDE, X[2]) // BinopExp >>`,
 		Id:         "BinOpExp4",
-		NTType:     20,
-		Index:      50,
+		NTType:     21,
+		Index:      55,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewBinOpExp(X[0], ast.DIVIDE, X[2]) // BinopExp
@@ -534,8 +584,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp4 : BinOpExp5	<< X[0], nil >>`,
 		Id:         "BinOpExp4",
-		NTType:     20,
-		Index:      51,
+		NTType:     21,
+		Index:      56,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -544,8 +594,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp5 : VarExp	<< X[0], nil >>`,
 		Id:         "BinOpExp5",
-		NTType:     21,
-		Index:      52,
+		NTType:     22,
+		Index:      57,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -554,8 +604,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp5 : AnnoExp	<< X[0], nil >>`,
 		Id:         "BinOpExp5",
-		NTType:     21,
-		Index:      53,
+		NTType:     22,
+		Index:      58,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -564,8 +614,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp5 : Constant	<< X[0], nil >>`,
 		Id:         "BinOpExp5",
-		NTType:     21,
-		Index:      54,
+		NTType:     22,
+		Index:      59,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -574,8 +624,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp5 : LookupExp	<< X[0], nil >>`,
 		Id:         "BinOpExp5",
-		NTType:     21,
-		Index:      55,
+		NTType:     22,
+		Index:      60,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -584,8 +634,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp5 : ParenthExp	<< X[0], nil >>`,
 		Id:         "BinOpExp5",
-		NTType:     21,
-		Index:      56,
+		NTType:     22,
+		Index:      61,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -594,8 +644,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp5 : UnopExp	<< X[0], nil >>`,
 		Id:         "BinOpExp5",
-		NTType:     21,
-		Index:      57,
+		NTType:     22,
+		Index:      62,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -604,8 +654,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `BinOpExp5 : CallExp	<< X[0], nil >>`,
 		Id:         "BinOpExp5",
-		NTType:     21,
-		Index:      58,
+		NTType:     22,
+		Index:      63,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -614,8 +664,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Cmp : eq eq	<< ast.EQ, nil >>`,
 		Id:         "Cmp",
-		NTType:     22,
-		Index:      59,
+		NTType:     23,
+		Index:      64,
 		NumSymbols: 2,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.EQ, nil
@@ -624,8 +674,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Cmp : neq	<< ast.NEQ, nil >>`,
 		Id:         "Cmp",
-		NTType:     22,
-		Index:      60,
+		NTType:     23,
+		Index:      65,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NEQ, nil
@@ -634,8 +684,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Cmp : geq	<< ast.GEQ, nil >>`,
 		Id:         "Cmp",
-		NTType:     22,
-		Index:      61,
+		NTType:     23,
+		Index:      66,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.GEQ, nil
@@ -644,8 +694,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Cmp : leq	<< ast.LEQ, nil >>`,
 		Id:         "Cmp",
-		NTType:     22,
-		Index:      62,
+		NTType:     23,
+		Index:      67,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.LEQ, nil
@@ -654,8 +704,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Cmp : lt	<< ast.LT, nil >>`,
 		Id:         "Cmp",
-		NTType:     22,
-		Index:      63,
+		NTType:     23,
+		Index:      68,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.LT, nil
@@ -664,8 +714,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Cmp : gt	<< ast.GT, nil >>`,
 		Id:         "Cmp",
-		NTType:     22,
-		Index:      64,
+		NTType:     23,
+		Index:      69,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.GT, nil
@@ -674,8 +724,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `UnopExp : Unop Exp1	<< ast.NewUnOpExp(X[0], X[1]) >>`,
 		Id:         "UnopExp",
-		NTType:     23,
-		Index:      65,
+		NTType:     24,
+		Index:      70,
 		NumSymbols: 2,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewUnOpExp(X[0], X[1])
@@ -684,8 +734,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Unop : unminus	<< ast.UNARYMINUS, nil >>`,
 		Id:         "Unop",
-		NTType:     24,
-		Index:      66,
+		NTType:     25,
+		Index:      71,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.UNARYMINUS, nil
@@ -694,8 +744,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Unop : not	<< ast.NOT, nil >>`,
 		Id:         "Unop",
-		NTType:     24,
-		Index:      67,
+		NTType:     25,
+		Index:      72,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NOT, nil
@@ -704,8 +754,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `LookupExp : Lookup lident	<< ast.NewLookupExp(X[0], util.ParseId(X[1])) // lookupexp >>`,
 		Id:         "LookupExp",
-		NTType:     25,
-		Index:      68,
+		NTType:     26,
+		Index:      73,
 		NumSymbols: 2,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewLookupExp(X[0], util.ParseId(X[1])) // lookupexp
@@ -714,8 +764,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Lookup : Lookup lident dot	<< ast.AddPathElement(X[0], util.ParseId(X[1])), nil // intermediate for lookup calls >>`,
 		Id:         "Lookup",
-		NTType:     26,
-		Index:      69,
+		NTType:     27,
+		Index:      74,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.AddPathElement(X[0], util.ParseId(X[1])), nil // intermediate for lookup calls
@@ -724,8 +774,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Lookup : lident dot	<< ast.LookupPathRoot(util.ParseId(X[0])), nil // see above >>`,
 		Id:         "Lookup",
-		NTType:     26,
-		Index:      70,
+		NTType:     27,
+		Index:      75,
 		NumSymbols: 2,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.LookupPathRoot(util.ParseId(X[0])), nil // see above
@@ -734,8 +784,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Pattern : lparen rparen	<< ast.NewEmptyPattern() // >>`,
 		Id:         "Pattern",
-		NTType:     27,
-		Index:      71,
+		NTType:     28,
+		Index:      76,
 		NumSymbols: 2,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewEmptyPattern() //
@@ -744,8 +794,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Pattern : lparen Paramlist rparen	<< ast.NewPattern(X[1]) >>`,
 		Id:         "Pattern",
-		NTType:     27,
-		Index:      72,
+		NTType:     28,
+		Index:      77,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewPattern(X[1])
@@ -754,8 +804,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Pattern : Param	<< ast.NewPattern(X[0]) >>`,
 		Id:         "Pattern",
-		NTType:     27,
-		Index:      73,
+		NTType:     28,
+		Index:      78,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewPattern(X[0])
@@ -764,8 +814,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Param : lident	<< ast.NewParam(util.ParseId(X[0])) // param >>`,
 		Id:         "Param",
-		NTType:     28,
-		Index:      74,
+		NTType:     29,
+		Index:      79,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewParam(util.ParseId(X[0])) // param
@@ -774,8 +824,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Param : lparen lident colon Type rparen	<< ast.NewAnnoParam(util.ParseId(X[1]), X[3]) // typeanno param >>`,
 		Id:         "Param",
-		NTType:     28,
-		Index:      75,
+		NTType:     29,
+		Index:      80,
 		NumSymbols: 5,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewAnnoParam(util.ParseId(X[1]), X[3]) // typeanno param
@@ -784,8 +834,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Paramlist : Param comma Paramlist	<< ast.AppendParams(X[0], X[2]) >>`,
 		Id:         "Paramlist",
-		NTType:     29,
-		Index:      76,
+		NTType:     30,
+		Index:      81,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.AppendParams(X[0], X[2])
@@ -794,8 +844,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Paramlist : Param	<< ast.NewParamList(X[0]) >>`,
 		Id:         "Paramlist",
-		NTType:     29,
-		Index:      77,
+		NTType:     30,
+		Index:      82,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewParamList(X[0])
@@ -804,8 +854,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type : Tupletype	<< ast.NewTupleType(X[0]), nil >>`,
 		Id:         "Type",
-		NTType:     30,
-		Index:      78,
+		NTType:     31,
+		Index:      83,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewTupleType(X[0]), nil
@@ -814,8 +864,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type : Type1	<< X[0], nil >>`,
 		Id:         "Type",
-		NTType:     30,
-		Index:      79,
+		NTType:     31,
+		Index:      84,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[0], nil
@@ -824,8 +874,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type1 : lparen Type rparen	<< X[1], nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      80,
+		NTType:     32,
+		Index:      85,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[1], nil
@@ -834,8 +884,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type1 : bool	<< ast.NewBoolType(), nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      81,
+		NTType:     32,
+		Index:      86,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewBoolType(), nil
@@ -844,8 +894,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type1 : int	<< ast.NewIntType(), nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      82,
+		NTType:     32,
+		Index:      87,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewIntType(), nil
@@ -854,8 +904,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type1 : nat	<< ast.NewNatType(), nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      83,
+		NTType:     32,
+		Index:      88,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewNatType(), nil
@@ -864,8 +914,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type1 : unit	<< ast.NewUnitType(), nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      84,
+		NTType:     32,
+		Index:      89,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewUnitType(), nil
@@ -874,8 +924,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type1 : koin	<< ast.NewKoinType(), nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      85,
+		NTType:     32,
+		Index:      90,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewKoinType(), nil
@@ -884,8 +934,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type1 : string	<< ast.NewStringType(), nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      86,
+		NTType:     32,
+		Index:      91,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewStringType(), nil
@@ -894,8 +944,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type1 : key	<< ast.NewKeyType(), nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      87,
+		NTType:     32,
+		Index:      92,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewKeyType(), nil
@@ -904,8 +954,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type1 : operation	<< ast.NewOperationType(), nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      88,
+		NTType:     32,
+		Index:      93,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewOperationType(), nil
@@ -914,8 +964,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type1 : address	<< ast.NewAddressType(), nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      89,
+		NTType:     32,
+		Index:      94,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewAddressType(), nil
@@ -924,8 +974,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type1 : Type1 option	<< ast.NewOptionType(X[0]), nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      90,
+		NTType:     32,
+		Index:      95,
 		NumSymbols: 2,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewOptionType(X[0]), nil
@@ -934,28 +984,48 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Type1 : Type1 list	<< ast.NewListType(X[0]), nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      91,
+		NTType:     32,
+		Index:      96,
 		NumSymbols: 2,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewListType(X[0]), nil
 		},
 	},
+	ProdTabEntry{
+		String: `Type1 : lparen Type comma Type rparen map	<< ast.NewMapType(X[1], X[3]), nil >>`,
+		Id:         "Type1",
+		NTType:     32,
+		Index:      97,
+		NumSymbols: 6,
+		ReduceFunc: func(X []Attrib) (Attrib, error) {
+			return ast.NewMapType(X[1], X[3]), nil
+		},
+	},
 	ProdTabEntry{
 		String: `Type1 : lident	<< ast.NewDeclaredType(util.ParseId(X[0])), nil >>`,
 		Id:         "Type1",
-		NTType:     31,
-		Index:      92,
+		NTType:     32,
+		Index:      98,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewDeclaredType(util.ParseId(X[0])), nil
 		},
 	},
+	ProdTabEntry{
+		String: `Type1 : uident dot lident	<< ast.NewDeclaredType(util.ParseModuleType(X[0], X[2])), nil >>`,
+		Id:         "Type1",
+		NTType:     32,
+		Index:      99,
+		NumSymbols: 3,
+		ReduceFunc: func(X []Attrib) (Attrib, error) {
+			return ast.NewDeclaredType(util.ParseModuleType(X[0], X[2])), nil
+		},
+	},
 	ProdTabEntry{
 		String: `Tupletype : Type1 ast Tupletype	<< ast.PrependTypeList(X[0], X[2]), nil >>`,
 		Id:         "Tupletype",
-		NTType:     32,
-		Index:      93,
+		NTType:     33,
+		Index:      100,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.PrependTypeList(X[0], X[2]), nil
@@ -964,8 +1034,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Tupletype : Type1 ast Type1	<< ast.NewTypeList(X[0], X[2]), nil >>`,
 		Id:         "Tupletype",
-		NTType:     32,
-		Index:      94,
+		NTType:     33,
+		Index:      101,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewTypeList(X[0], X[2]), nil
@@ -974,8 +1044,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : key_lit	<< ast.NewKeyLit(util.ParseKey(X[0])) >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      95,
+		NTType:     34,
+		Index:      102,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewKeyLit(util.ParseKey(X[0]))
@@ -984,8 +1054,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : address_lit	<< ast.NewAddressLit(util.ParseAddress(X[0])) >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      96,
+		NTType:     34,
+		Index:      103,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewAddressLit(util.ParseAddress(X[0]))
@@ -994,8 +1064,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : true	<< ast.NewBoolLit(true) >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      97,
+		NTType:     34,
+		Index:      104,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewBoolLit(true)
@@ -1004,8 +1074,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : false	<< ast.NewBoolLit(false) >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      98,
+		NTType:     34,
+		Index:      105,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewBoolLit(false)
@@ -1014,8 +1084,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : int_lit	<< ast.NewIntLit(util.ParseInt(X[0])) >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      99,
+		NTType:     34,
+		Index:      106,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewIntLit(util.ParseInt(X[0]))
@@ -1024,8 +1094,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : nat_lit	<< ast.NewNatLit(util.ParseNat(X[0])) >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      100,
+		NTType:     34,
+		Index:      107,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewNatLit(util.ParseNat(X[0]))
@@ -1034,8 +1104,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : koin_lit	<< ast.NewKoinLit(util.ParseKoin(X[0])) >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      101,
+		NTType:     34,
+		Index:      108,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewKoinLit(util.ParseKoin(X[0]))
@@ -1044,8 +1114,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : string_lit	<< ast.NewStringLit(util.ParseString(X[0])) >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      102,
+		NTType:     34,
+		Index:      109,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewStringLit(util.ParseString(X[0]))
@@ -1054,8 +1124,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : lparen Tuple rparen	<< X[1], nil // tupleexp >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      103,
+		NTType:     34,
+		Index:      110,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[1], nil // tupleexp
@@ -1064,8 +1134,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : lparen rparen	<< ast.NewUnitLit() >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      104,
+		NTType:     34,
+		Index:      111,
 		NumSymbols: 2,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewUnitLit()
@@ -1074,8 +1144,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : lbrack rbrack	<< ast.NewEmptyList() >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      105,
+		NTType:     34,
+		Index:      112,
 		NumSymbols: 2,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewEmptyList()
@@ -1084,8 +1154,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : lbrack Array rbrack	<< X[1], nil >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      106,
+		NTType:     34,
+		Index:      113,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[1], nil
@@ -1094,8 +1164,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Constant : lbrace StructLit rbrace	<< X[1], nil >>`,
 		Id:         "Constant",
-		NTType:     33,
-		Index:      107,
+		NTType:     34,
+		Index:      114,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return X[1], nil
@@ -1104,8 +1174,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Array : Exp1	<< ast.NewListLit(X[0]) >>`,
 		Id:         "Array",
-		NTType:     34,
-		Index:      108,
+		NTType:     35,
+		Index:      115,
 		NumSymbols: 1,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewListLit(X[0])
@@ -1114,8 +1184,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Array : Array semicolon Exp1	<< ast.AppendList(X[0], X[2]) >>`,
 		Id:         "Array",
-		NTType:     34,
-		Index:      109,
+		NTType:     35,
+		Index:      116,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.AppendList(X[0], X[2])
@@ -1124,8 +1194,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `StructLit : lident eq Exp1 semicolon	<< ast.NewStructLit(util.ParseId(X[0]), X[2]) >>`,
 		Id:         "StructLit",
-		NTType:     35,
-		Index:      110,
+		NTType:     36,
+		Index:      117,
 		NumSymbols: 4,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewStructLit(util.ParseId(X[0]), X[2])
@@ -1134,8 +1204,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `StructLit : StructLit lident eq Exp1 semicolon	<< ast.AppendStructLit(X[0], util.ParseId(X[1]), X[3]) >>`,
 		Id:         "StructLit",
-		NTType:     35,
-		Index:      111,
+		NTType:     36,
+		Index:      118,
 		NumSymbols: 5,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.AppendStructLit(X[0], util.ParseId(X[1]), X[3])
@@ -1144,8 +1214,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Tuple : Exp1 comma Exp1	<< ast.NewTupleExp(X[0], X[2]) // tuple >>`,
 		Id:         "Tuple",
-		NTType:     36,
-		Index:      112,
+		NTType:     37,
+		Index:      119,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.NewTupleExp(X[0], X[2]) // tuple
@@ -1154,8 +1224,8 @@ var productionsTable = ProdTab{
 	ProdTabEntry{
 		String: `Tuple : Exp1 comma Tuple	<< ast.AddTupleEntry(X[0], X[2]) // tuple of tuple >>`,
 		Id:         "Tuple",
-		NTType:     36,
-		Index:      113,
+		NTType:     37,
+		Index:      120,
 		NumSymbols: 3,
 		ReduceFunc: func(X []Attrib) (Attrib, error) {
 			return ast.AddTupleEntry(X[0], X[2]) // tuple of tuple
@@ -0,0 +1,239 @@
+package interpreter
+
+import (
+	"fmt"
+	"github.com/mndrix/ps"
+	. "github.com/nfk93/blockchain/smart/interpreter/ast"
+	"github.com/nfk93/blockchain/smart/interpreter/errors"
+	"github.com/nfk93/blockchain/smart/interpreter/lexer"
+	"github.com/nfk93/blockchain/smart/interpreter/parser"
+	"github.com/nfk93/blockchain/smart/interpreter/token"
+	"github.com/nfk93/blockchain/smart/interpreter/value"
+	"strings"
+)
+
+// ErrIncompleteInput is returned by Repl.Eval when the input ended in the middle of a declaration or expression
+var ErrIncompleteInput = fmt.Errorf("unexpected end of input")
+
+// ReplModule is the module that helpers declared in the REPL are added to, as helpers can only be called
+// through a module
+const ReplModule = "Repl"
+
+// Repl evaluates declarations and expressions one at a time, keeping the declared types, helpers, imports and
+// bound variables between inputs. Every input is type checked and evaluated with a budget of Gas, and the Current
+// module is mocked by Amount, Balance and Sender.
+type Repl struct {
+	Gas     uint64
+	Amount  uint64
+	Balance uint64
+	Sender  string
+	// Dir is the directory file imports are relative to
+	Dir string
+
+	venv    VarEnv
+	tenv    TypeEnv
+	senv    StructEnv
+	values  VarEnv
+	helpers []StructField
+	replEnv VarEnv
+}
+
+func NewRepl(gas uint64) *Repl {
+	venv, tenv, senv := GenInitEnvs()
+	return &Repl{Gas: gas, Dir: ".", venv: venv, tenv: tenv, senv: senv, values: ps.NewMap(), replEnv: ps.NewMap()}
+}
+
+// Eval evaluates input, which is either a sequence of type, helper and import declarations, a binding of the form
+// let x = exp, or an expression. It returns a description of what was declared or the value and type of the
+// expression.
+func (r *Repl) Eval(input string) (string, error) {
+	tokens := scanTokens([]byte(input))
+	if len(tokens) == 0 {
+		return "", nil
+	}
+	switch tokenId(tokens, 0) {
+	case "type", "importkw", "letinit", "letentry":
+		return r.evalDeclarations(input)
+	case "let":
+		if tokenId(tokens, 1) != "lident" {
+			exp, err := parseExpression(input)
+			if err != nil {
+				return "", err
+			}
+			return r.evalExpression(exp, "")
+		}
+		if tokenId(tokens, 2) != "eq" {
+			return r.evalDeclarations(input)
+		}
+		// let x = e is either a binding, or the start of the expression let x = e in e'
+		exp, err := parseExpression(input)
+		if err == nil {
+			return r.evalExpression(exp, "")
+		}
+		if len(tokens) < 4 {
+			return "", ErrIncompleteInput
+		}
+		binding, bindingErr := parseExpression(input[tokens[3].Offset:])
+		if bindingErr != nil {
+			if err == ErrIncompleteInput || bindingErr == ErrIncompleteInput {
+				return "", ErrIncompleteInput
+			}
+			return "", bindingErr
+		}
+		return r.evalExpression(binding, string(tokens[1].Lit))
+	default:
+		exp, err := parseExpression(input)
+		if err != nil {
+			return "", err
+		}
+		return r.evalExpression(exp, "")
+	}
+}
+
+// Reset forgets everything declared and bound so far
+func (r *Repl) Reset() {
+	fresh := NewRepl(r.Gas)
+	fresh.Amount, fresh.Balance, fresh.Sender, fresh.Dir = r.Amount, r.Balance, r.Sender, r.Dir
+	*r = *fresh
+}
+
+func (r *Repl) evalDeclarations(input string) (string, error) {
+	code, err := BundleImports([]byte(input), r.Dir)
+	if err != nil {
+		return "", err
+	}
+	parsed, err := parseRepl(code)
+	if err != nil {
+		return "", err
+	}
+	parsed, err = resolveImports(parsed, nil, 0)
+	if err != nil {
+		return "", err
+	}
+	output := make([]string, 0)
+	gas := r.Gas
+	for _, decl := range parsed.(TopLevel).Roots {
+		switch decl.(type) {
+		case TypeDecl, ImportDecl, HelperDecl:
+		default:
+			return strings.Join(output, "\n"), fmt.Errorf("entries and storage can't be declared in the REPL")
+		}
+		texp, venv, tenv, senv, gas_, err := AddTypesWithEnvs(decl, r.venv, r.tenv, r.senv, gas)
+		gas = gas_
+		if err != nil {
+			return strings.Join(output, "\n"), err
+		}
+		switch decl := texp.Exp.(type) {
+		case TypeDecl:
+			r.tenv, r.senv = tenv, senv
+			output = append(output, fmt.Sprintf("type %s = %s", decl.Id, decl.Typ.String()))
+		case ImportDecl:
+			r.venv, r.tenv, r.senv = venv, tenv, senv
+			r.values = r.values.Set(decl.ModId, libraryModule{libraryEnv(decl.Library.(TypedExp).Exp.(TopLevel).Roots)})
+			output = append(output, fmt.Sprintf("module %s : %s", decl.ModId, decl.Library.(TypedExp).Type.String()))
+		case HelperDecl:
+			r.addHelper(decl, texp.Type)
+			output = append(output, fmt.Sprintf("val %s.%s : %s", ReplModule, decl.Id, texp.Type.String()))
+		}
+	}
+	return strings.Join(output, "\n"), nil
+}
+
+// addHelper adds a helper to the Repl module, replacing an earlier helper of the same name
+func (r *Repl) addHelper(helper HelperDecl, typ Type) {
+	fields := make([]StructField, 0)
+	for _, field := range r.helpers {
+		if field.Id != helper.Id {
+			fields = append(fields, field)
+		}
+	}
+	r.helpers = append(fields, StructField{helper.Id, typ})
+	r.replEnv = r.replEnv.Set(helper.Id, helperVal{helper, r.values})
+	r.venv = r.venv.Set(ReplModule, StructType{r.helpers})
+	r.values = r.values.Set(ReplModule, libraryModule{r.replEnv})
+}
+
+// parseExpression parses input as an expression. The parser only accepts toplevel declarations, so the expression
+// is parsed as the body of a helper
+func parseExpression(input string) (Exp, error) {
+	parsed, err := parseRepl([]byte("let it () =\n" + input))
+	if err != nil {
+		return nil, err
+	}
+	roots := parsed.(TopLevel).Roots
+	if helper, ok := roots[0].(HelperDecl); ok && len(roots) == 1 {
+		return helper.Body, nil
+	}
+	return nil, fmt.Errorf("input is not an expression")
+}
+
+// evalExpression evaluates exp, and binds the result to id unless id is empty
+func (r *Repl) evalExpression(exp Exp, id string) (string, error) {
+	texp, _, _, _, gas, err := AddTypesWithEnvs(exp, r.venv, r.tenv, r.senv, r.Gas)
+	if err != nil {
+		return "", err
+	}
+	val, gasUsed, err := r.interpret(texp, gas)
+	if err != nil {
+		return "", err
+	}
+	name := "-"
+	if id != "" {
+		r.venv = r.venv.Set(id, texp.Type)
+		r.values = r.values.Set(id, val)
+		name = id
+	}
+	return fmt.Sprintf("%s : %s = %s\t(%d gas)", name, texp.Type.String(), value.Format(val), gasUsed), nil
+}
+
+func (r *Repl) interpret(texp TypedExp, gas uint64) (val value.Value, gasUsed uint64, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if panicked, ok := recovered.(PanicStruct); ok {
+				err = fmt.Errorf("failed: %s", panicked.message)
+			} else {
+				err = fmt.Errorf("failed: %v", recovered)
+			}
+		}
+	}()
+
+	currentAmt = r.Amount
+	currentBal = r.Balance
+	currentSender = r.Sender
+	spentsofar = 0
+	val, remaining := interpret(texp, r.values, gas)
+	return val, r.Gas - remaining, nil
+}
+
+func parseRepl(code []byte) (Exp, error) {
+	parsed, err := parser.NewParser().Parse(lexer.NewLexer(code))
+	if err != nil {
+		if parseErr, ok := err.(*errors.Error); ok && parseErr.ErrorToken.Type == token.EOF {
+			return nil, ErrIncompleteInput
+		}
+		return nil, fmt.Errorf("syntax error: %s", err.Error())
+	}
+	return parsed.(Exp), nil
+}
+
+type scannedToken struct {
+	Id     string
+	Lit    []byte
+	Offset int
+}
+
+func scanTokens(code []byte) []scannedToken {
+	tokens := make([]scannedToken, 0)
+	lex := lexer.NewLexer(code)
+	for tok := lex.Scan(); tok.Type != token.EOF; tok = lex.Scan() {
+		tokens = append(tokens, scannedToken{token.TokMap.Id(tok.Type), tok.Lit, tok.Pos.Offset})
+	}
+	return tokens
+}
+
+func tokenId(tokens []scannedToken, i int) string {
+	if i < len(tokens) {
+		return tokens[i].Id
+	}
+	return ""
+}
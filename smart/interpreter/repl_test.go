@@ -0,0 +1,87 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReplExpression(t *testing.T) {
+	repl := NewRepl(10000000)
+	checkRepl(t, repl, "1 + 2", "- : int = 3")
+	checkRepl(t, repl, "let x = 5p in x + 1p", "- : nat = 6p")
+	checkRepl(t, repl, "(1, \"a\")", "- : (int * string) = (1, \"a\")")
+}
+
+func TestReplBinding(t *testing.T) {
+	repl := NewRepl(10000000)
+	checkRepl(t, repl, "let x = 40", "x : int = 40")
+	checkRepl(t, repl, "x + 2", "- : int = 42")
+	checkRepl(t, repl, "let x = x + 1", "x : int = 41")
+	checkRepl(t, repl, "x", "- : int = 41")
+
+	repl.Reset()
+	if _, err := repl.Eval("x"); err == nil {
+		t.Errorf("x should be forgotten after a reset")
+	}
+}
+
+func TestReplDeclarations(t *testing.T) {
+	repl := NewRepl(10000000)
+	checkRepl(t, repl, "type point = {x: int; y: int;}", "type point = ")
+	checkRepl(t, repl, "let double (p : point) = {x = p.x * 2; y = p.y * 2;}", "val Repl.double : ")
+	checkRepl(t, repl, "let p = Repl.double {x = 1; y = 2;}", "p : {x : int, y : int} = {x = 2; y = 4;}")
+	checkRepl(t, repl, "p.y", "- : int = 4")
+
+	if _, err := repl.Eval("let%entry main (p : unit) (s : unit) = (([] : operation list), ())"); err == nil {
+		t.Errorf("entries should not be allowed in the REPL")
+	}
+}
+
+func TestReplImport(t *testing.T) {
+	repl := NewRepl(10000000)
+	repl.Dir = "test_cases"
+	checkRepl(t, repl, "import Geometry = \"geometry_lib\"", "module Geometry : ")
+	checkRepl(t, repl, "Geometry.scale {x = 1; y = 2;} 3", "- : {x : int, y : int} = {x = 3; y = 6;}")
+}
+
+func TestReplIncompleteInput(t *testing.T) {
+	repl := NewRepl(10000000)
+	for _, input := range []string{"let x =", "1 +", "type t = {a: int;", "let f (x : int) ="} {
+		if _, err := repl.Eval(input); err != ErrIncompleteInput {
+			t.Errorf("%s should be incomplete, but got %v", input, err)
+		}
+	}
+	if _, err := repl.Eval("1 + + 2"); err == nil || err == ErrIncompleteInput {
+		t.Errorf("1 + + 2 should be a syntax error, but got %v", err)
+	}
+	checkRepl(t, repl, "let x =\n  1 + 2", "x : int = 3")
+}
+
+func TestReplCurrent(t *testing.T) {
+	repl := NewRepl(10000000)
+	repl.Amount = 150000
+	repl.Sender = "0000000000000000000000000000000000000000000000000000000000000000"
+	checkRepl(t, repl, "Current.amount ()", "- : koin = 1.5kn")
+	checkRepl(t, repl, "Current.sender ()", "- : key = kn12wkBET2rRgE8pahuaczxKbmv7ciehqsne57F9gtzf1PVgytWuQ")
+	if _, err := repl.Eval("Current.failwith \"stop\""); err == nil || !strings.Contains(err.Error(), "stop") {
+		t.Errorf("failwith should fail with its message, but got %v", err)
+	}
+}
+
+func TestReplGas(t *testing.T) {
+	repl := NewRepl(1000)
+	if _, err := repl.Eval("1 + 2"); err == nil {
+		t.Errorf("should run out of gas")
+	}
+	repl.Gas = 10000000
+	checkRepl(t, repl, "1 + 2", "- : int = 3")
+}
+
+func checkRepl(t *testing.T, repl *Repl, input, expected string) {
+	output, err := repl.Eval(input)
+	if err != nil {
+		t.Errorf("unexpected error evaluating %s: %s", input, err.Error())
+	} else if !strings.HasPrefix(output, expected) {
+		t.Errorf("%s evaluated to %s, expected %s", input, output, expected)
+	}
+}
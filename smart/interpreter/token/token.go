@@ -65,18 +65,21 @@ var TokMap = TokenMap{
 		"lident",
 		"eq",
 		"letentry",
-		"type",
+		"let",
+		"importkw",
+		"uident",
+		"address_lit",
+		"string_lit",
 		"lbrace",
 		"rbrace",
+		"type",
 		"colon",
 		"semicolon",
 		"if",
 		"then",
 		"else",
 		"concat",
-		"let",
 		"in",
-		"uident",
 		"dot",
 		"lparen",
 		"rparen",
@@ -106,14 +109,13 @@ var TokMap = TokenMap{
 		"address",
 		"option",
 		"list",
+		"map",
 		"key_lit",
-		"address_lit",
 		"true",
 		"false",
 		"int_lit",
 		"nat_lit",
 		"koin_lit",
-		"string_lit",
 		"lbrack",
 		"rbrack",
 	},
@@ -125,56 +127,58 @@ var TokMap = TokenMap{
 		"lident":      3,
 		"eq":          4,
 		"letentry":    5,
-		"type":        6,
-		"lbrace":      7,
-		"rbrace":      8,
-		"colon":       9,
-		"semicolon":   10,
-		"if":          11,
-		"then":        12,
-		"else":        13,
-		"concat":      14,
-		"let":         15,
-		"in":          16,
-		"uident":      17,
-		"dot":         18,
-		"lparen":      19,
-		"rparen":      20,
-		"larrow":      21,
-		"or":          22,
-		"and":         23,
-		"plus":        24,
-		"minus":       25,
-		"ast":         26,
-		"slash":       27,
-		"neq":         28,
-		"geq":         29,
-		"leq":         30,
-		"lt":          31,
-		"gt":          32,
-		"unminus":     33,
-		"not":         34,
-		"comma":       35,
-		"bool":        36,
-		"int":         37,
-		"nat":         38,
-		"unit":        39,
-		"koin":        40,
-		"string":      41,
-		"key":         42,
-		"operation":   43,
-		"address":     44,
-		"option":      45,
-		"list":        46,
-		"key_lit":     47,
-		"address_lit": 48,
-		"true":        49,
-		"false":       50,
-		"int_lit":     51,
-		"nat_lit":     52,
-		"koin_lit":    53,
-		"string_lit":  54,
-		"lbrack":      55,
-		"rbrack":      56,
+		"let":         6,
+		"importkw":    7,
+		"uident":      8,
+		"address_lit": 9,
+		"string_lit":  10,
+		"lbrace":      11,
+		"rbrace":      12,
+		"type":        13,
+		"colon":       14,
+		"semicolon":   15,
+		"if":          16,
+		"then":        17,
+		"else":        18,
+		"concat":      19,
+		"in":          20,
+		"dot":         21,
+		"lparen":      22,
+		"rparen":      23,
+		"larrow":      24,
+		"or":          25,
+		"and":         26,
+		"plus":        27,
+		"minus":       28,
+		"ast":         29,
+		"slash":       30,
+		"neq":         31,
+		"geq":         32,
+		"leq":         33,
+		"lt":          34,
+		"gt":          35,
+		"unminus":     36,
+		"not":         37,
+		"comma":       38,
+		"bool":        39,
+		"int":         40,
+		"nat":         41,
+		"unit":        42,
+		"koin":        43,
+		"string":      44,
+		"key":         45,
+		"operation":   46,
+		"address":     47,
+		"option":      48,
+		"list":        49,
+		"map":         50,
+		"key_lit":     51,
+		"true":        52,
+		"false":       53,
+		"int_lit":     54,
+		"nat_lit":     55,
+		"koin_lit":    56,
+		"lbrack":      57,
+		"rbrack":      58,
 	},
 }
@@ -2,31 +2,35 @@ package util
 
 import (
 	"fmt"
+	"github.com/nfk93/blockchain/crypto"
 	"github.com/nfk93/blockchain/smart/interpreter/token"
 	"strconv"
 	"strings"
-	"unicode/utf8"
 )
 
 func ParseId(id interface{}) string {
 	return string(id.(*token.Token).Lit)
 }
 
+// ParseModuleType returns the name of a type declared in an imported module, i.e. Module.typename
+func ParseModuleType(mod, id interface{}) string {
+	return ParseId(mod) + "." + ParseId(id)
+}
+
+// ParseKey returns the key hash of an account address, rejecting addresses with a wrong checksum
 func ParseKey(key interface{}) (string, error) {
-	return removePrefixAndCheckLen(key)
+	return crypto.DecodeAccount(string(key.(*token.Token).Lit))
 }
 
+// ParseAddress returns the contract hash of a contract address, rejecting addresses with a wrong checksum
 func ParseAddress(add interface{}) (string, error) {
-	return removePrefixAndCheckLen(add)
+	return crypto.DecodeContract(string(add.(*token.Token).Lit))
 }
 
-func removePrefixAndCheckLen(str interface{}) (string, error) {
-	str = string(str.(*token.Token).Lit)[3:]
-	if utf8.RuneCountInString(str.(string)) != 64 {
-		return "", fmt.Errorf("keys and addresses must have length 64")
-	} else {
-		return str.(string), nil
-	}
+// ParseAddressImport returns the module name and library address of an import of a deployed library
+func ParseAddressImport(mod, add interface{}) (string, string, error) {
+	address, err := ParseAddress(add)
+	return ParseId(mod), address, err
 }
 
 func ParseInt(i interface{}) (int64, error) {
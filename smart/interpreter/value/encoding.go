@@ -0,0 +1,430 @@
+package value
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// EncodingVersion is written as the first byte of every encoded value. It has to be bumped whenever the
+// encoding below changes, since encoded values are hashed into the signed block state
+const EncodingVersion = byte(1)
+
+// tags identifying the kind of an encoded value. These are part of the encoding, so never renumber them
+const (
+	tagUnit byte = iota + 1
+	tagBool
+	tagInt
+	tagNat
+	tagKoin
+	tagString
+	tagKey
+	tagAddress
+	tagOption
+	tagList
+	tagTuple
+	tagStruct
+	tagMap
+	tagOperation
+	tagLambda
+)
+
+const (
+	opFailWith byte = iota + 1
+	opTransfer
+	opContractCall
+	opSchedule
+)
+
+// Encode returns the canonical binary encoding of a value. Equal values (see Equals) always have the same
+// encoding: struct fields are sorted by name and map entries by the encoding of their keys.
+//
+// Integers are written as 8 byte big endian, strings and collections are prefixed by their length as a
+// uvarint.
+func Encode(v Value) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(EncodingVersion)
+	if err := encodeValue(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode is the inverse of Encode
+func Decode(data []byte) (Value, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("can't decode empty value")
+	}
+	if data[0] != EncodingVersion {
+		return nil, fmt.Errorf("unsupported value encoding version %d", data[0])
+	}
+	r := bytes.NewReader(data[1:])
+	v, err := decodeValue(r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after encoded value", r.Len())
+	}
+	return v, nil
+}
+
+func encodeValue(buf *bytes.Buffer, v Value) error {
+	switch v := v.(type) {
+	case UnitVal:
+		buf.WriteByte(tagUnit)
+	case BoolVal:
+		buf.WriteByte(tagBool)
+		if v.Value {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case IntVal:
+		buf.WriteByte(tagInt)
+		writeUint64(buf, uint64(v.Value))
+	case NatVal:
+		buf.WriteByte(tagNat)
+		writeUint64(buf, v.Value)
+	case KoinVal:
+		buf.WriteByte(tagKoin)
+		writeUint64(buf, v.Value)
+	case StringVal:
+		buf.WriteByte(tagString)
+		writeString(buf, v.Value)
+	case KeyVal:
+		buf.WriteByte(tagKey)
+		writeString(buf, v.Value)
+	case AddressVal:
+		buf.WriteByte(tagAddress)
+		writeString(buf, v.Value)
+	case OptionVal:
+		buf.WriteByte(tagOption)
+		if !v.Opt {
+			buf.WriteByte(0)
+			return nil
+		}
+		buf.WriteByte(1)
+		return encodeValue(buf, v.Value)
+	case ListVal:
+		buf.WriteByte(tagList)
+		return encodeValues(buf, v.Values)
+	case TupleVal:
+		buf.WriteByte(tagTuple)
+		return encodeValues(buf, v.Values)
+	case StructVal:
+		buf.WriteByte(tagStruct)
+		fields := make([]string, 0, len(v.Field))
+		for k := range v.Field {
+			fields = append(fields, k)
+		}
+		sort.Strings(fields)
+		writeUvarint(buf, uint64(len(fields)))
+		for _, k := range fields {
+			writeString(buf, k)
+			if err := encodeValue(buf, v.Field[k]); err != nil {
+				return err
+			}
+		}
+	case MapVal:
+		buf.WriteByte(tagMap)
+		type entry struct {
+			key []byte
+			val Value
+		}
+		entries := make([]entry, 0, len(v.Values))
+		for k, val := range v.Values {
+			var keybuf bytes.Buffer
+			if err := encodeValue(&keybuf, k); err != nil {
+				return err
+			}
+			entries = append(entries, entry{keybuf.Bytes(), val})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i].key, entries[j].key) < 0
+		})
+		writeUvarint(buf, uint64(len(entries)))
+		for _, e := range entries {
+			buf.Write(e.key)
+			if err := encodeValue(buf, e.val); err != nil {
+				return err
+			}
+		}
+	case OperationVal:
+		buf.WriteByte(tagOperation)
+		return encodeOperation(buf, v.Value)
+	case LambdaVal:
+		buf.WriteByte(tagLambda)
+		writeUint64(buf, uint64(v.Value))
+	default:
+		return fmt.Errorf("can't encode value %v of type %T", v, v)
+	}
+	return nil
+}
+
+func encodeValues(buf *bytes.Buffer, values []Value) error {
+	writeUvarint(buf, uint64(len(values)))
+	for _, v := range values {
+		if err := encodeValue(buf, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeOperation(buf *bytes.Buffer, op interface{}) error {
+	switch op := op.(type) {
+	case FailWith:
+		buf.WriteByte(opFailWith)
+		writeString(buf, op.Msg)
+	case Transfer:
+		buf.WriteByte(opTransfer)
+		writeString(buf, op.Key)
+		writeUint64(buf, op.Amount)
+	case ContractCall:
+		buf.WriteByte(opContractCall)
+		writeString(buf, op.Address)
+		writeUint64(buf, op.Amount)
+		writeString(buf, op.Entry)
+		return encodeValue(buf, op.Params)
+	case Schedule:
+		buf.WriteByte(opSchedule)
+		writeUint64(buf, op.Slot)
+		writeString(buf, op.Address)
+		writeString(buf, op.Entry)
+		if err := encodeValue(buf, op.Params); err != nil {
+			return err
+		}
+		writeUint64(buf, op.Deposit)
+	default:
+		return fmt.Errorf("can't encode operation %v of type %T", op, op)
+	}
+	return nil
+}
+
+func writeUint64(buf *bytes.Buffer, i uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], i)
+	buf.Write(b[:])
+}
+
+func writeUvarint(buf *bytes.Buffer, i uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], i)
+	buf.Write(b[:n])
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	writeUvarint(buf, uint64(len(s)))
+	buf.WriteString(s)
+}
+
+func decodeValue(r *bytes.Reader) (Value, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("unexpected end of encoded value")
+	}
+	switch tag {
+	case tagUnit:
+		return UnitVal{}, nil
+	case tagBool:
+		b, err := r.ReadByte()
+		if err != nil || b > 1 {
+			return nil, fmt.Errorf("invalid encoding of bool")
+		}
+		return BoolVal{b == 1}, nil
+	case tagInt:
+		i, err := readUint64(r)
+		return IntVal{int64(i)}, err
+	case tagNat:
+		i, err := readUint64(r)
+		return NatVal{i}, err
+	case tagKoin:
+		i, err := readUint64(r)
+		return KoinVal{i}, err
+	case tagString:
+		s, err := readString(r)
+		return StringVal{s}, err
+	case tagKey:
+		s, err := readString(r)
+		return KeyVal{s}, err
+	case tagAddress:
+		s, err := readString(r)
+		return AddressVal{s}, err
+	case tagOption:
+		b, err := r.ReadByte()
+		if err != nil || b > 1 {
+			return nil, fmt.Errorf("invalid encoding of option")
+		}
+		if b == 0 {
+			return OptionVal{Opt: false}, nil
+		}
+		v, err := decodeValue(r)
+		return OptionVal{v, true}, err
+	case tagList:
+		values, err := decodeValues(r)
+		return ListVal{values}, err
+	case tagTuple:
+		values, err := decodeValues(r)
+		return TupleVal{values}, err
+	case tagStruct:
+		n, err := readLength(r)
+		if err != nil {
+			return nil, err
+		}
+		fields := make(map[string]Value)
+		previous := ""
+		for i := uint64(0); i < n; i++ {
+			k, err := readString(r)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 && k <= previous {
+				return nil, fmt.Errorf("struct fields aren't in canonical order")
+			}
+			previous = k
+			if fields[k], err = decodeValue(r); err != nil {
+				return nil, err
+			}
+		}
+		return StructVal{fields}, nil
+	case tagMap:
+		n, err := readLength(r)
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[Value]Value)
+		var previous []byte
+		for i := uint64(0); i < n; i++ {
+			start := r.Len()
+			k, err := decodeValue(r)
+			if err != nil {
+				return nil, err
+			}
+			switch GetTypeCode(k) {
+			case LIST, TUPLE, STRUCT, MAP, ERROR:
+				// these can't be used as keys of a go map
+				return nil, fmt.Errorf("invalid map key %v", k)
+			}
+			var keybuf bytes.Buffer
+			encodeValue(&keybuf, k)
+			if keybuf.Len() != start-r.Len() || (i > 0 && bytes.Compare(keybuf.Bytes(), previous) <= 0) {
+				return nil, fmt.Errorf("map keys aren't in canonical order")
+			}
+			previous = keybuf.Bytes()
+			if values[k], err = decodeValue(r); err != nil {
+				return nil, err
+			}
+		}
+		return MapVal{values}, nil
+	case tagOperation:
+		return decodeOperation(r)
+	case tagLambda:
+		i, err := readUint64(r)
+		return LambdaVal{ModuleLookup(i)}, err
+	default:
+		return nil, fmt.Errorf("unknown value tag %d", tag)
+	}
+}
+
+func decodeValues(r *bytes.Reader) ([]Value, error) {
+	n, err := readLength(r)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]Value, 0, n)
+	for i := uint64(0); i < n; i++ {
+		v, err := decodeValue(r)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func decodeOperation(r *bytes.Reader) (Value, error) {
+	kind, err := r.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("unexpected end of encoded operation")
+	}
+	switch kind {
+	case opFailWith:
+		msg, err := readString(r)
+		return OperationVal{FailWith{msg}}, err
+	case opTransfer:
+		key, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := readUint64(r)
+		return OperationVal{Transfer{key, amount}}, err
+	case opContractCall:
+		address, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		amount, err := readUint64(r)
+		if err != nil {
+			return nil, err
+		}
+		entry, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		params, err := decodeValue(r)
+		return OperationVal{ContractCall{address, amount, entry, params}}, err
+	case opSchedule:
+		slot, err := readUint64(r)
+		if err != nil {
+			return nil, err
+		}
+		address, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		entry, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		params, err := decodeValue(r)
+		if err != nil {
+			return nil, err
+		}
+		deposit, err := readUint64(r)
+		return OperationVal{Schedule{slot, address, entry, params, deposit}}, err
+	default:
+		return nil, fmt.Errorf("unknown operation kind %d", kind)
+	}
+}
+
+func readUint64(r *bytes.Reader) (uint64, error) {
+	var b [8]byte
+	if n, _ := r.Read(b[:]); n != 8 {
+		return 0, fmt.Errorf("unexpected end of encoded value")
+	}
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+// readLength reads a length prefix, rejecting lengths that can't possibly fit in the remaining input
+func readLength(r *bytes.Reader) (uint64, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, fmt.Errorf("invalid length prefix")
+	}
+	if n > uint64(r.Len()) {
+		return 0, fmt.Errorf("length prefix %d exceeds remaining input", n)
+	}
+	return n, nil
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	n, err := readLength(r)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, n)
+	r.Read(b)
+	return string(b), nil
+}
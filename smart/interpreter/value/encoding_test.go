@@ -0,0 +1,105 @@
+package value
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncodeGolden(t *testing.T) {
+	tests := []struct {
+		val     Value
+		encoded string
+	}{
+		{UnitVal{}, "0101"},
+		{BoolVal{true}, "010201"},
+		{IntVal{-1}, "0103ffffffffffffffff"},
+		{NatVal{1}, "01040000000000000001"},
+		{KoinVal{256}, "01050000000000000100"},
+		{StringVal{"hi"}, "0106026869"},
+		{KeyVal{"ab"}, "0107026162"},
+		{OptionVal{Opt: false}, "010900"},
+		{ListVal{[]Value{NatVal{2}}}, "010a01040000000000000002"},
+		{StructVal{map[string]Value{"b": UnitVal{}, "a": BoolVal{false}}}, "010c0201610200016201"},
+		{MapVal{map[Value]Value{StringVal{"b"}: UnitVal{}, StringVal{"a"}: UnitVal{}}}, "010d020601610106016201"},
+		{OperationVal{Transfer{"k", 1}}, "010e02016b0000000000000001"},
+	}
+	for _, test := range tests {
+		encoded, err := Encode(test.val)
+		if err != nil {
+			t.Errorf("error encoding %v: %s", test.val, err.Error())
+			continue
+		}
+		if hex.EncodeToString(encoded) != test.encoded {
+			t.Errorf("%v encoded to %x, expected %s", test.val, encoded, test.encoded)
+		}
+	}
+}
+
+func TestEncodeRoundtrip(t *testing.T) {
+	values := []Value{
+		UnitVal{},
+		IntVal{-42},
+		AddressVal{"1234567890abcdef"},
+		OptionVal{KoinVal{5}, true},
+		TupleVal{[]Value{StringVal{""}, ListVal{[]Value{}}}},
+		StructVal{map[string]Value{
+			"owner":    KeyVal{"aa"},
+			"balances": MapVal{map[Value]Value{KeyVal{"aa"}: IntVal{10}, KeyVal{"bb"}: IntVal{-3}}},
+		}},
+		OperationVal{ContractCall{"addr", 10, "main", TupleVal{[]Value{NatVal{1}, BoolVal{true}}}}},
+		OperationVal{FailWith{"error"}},
+	}
+	for _, val := range values {
+		encoded, err := Encode(val)
+		if err != nil {
+			t.Errorf("error encoding %v: %s", val, err.Error())
+			continue
+		}
+		decoded, err := Decode(encoded)
+		if err != nil {
+			t.Errorf("error decoding %v: %s", val, err.Error())
+			continue
+		}
+		reencoded, _ := Encode(decoded)
+		if !bytes.Equal(encoded, reencoded) {
+			t.Errorf("%v decoded to %v", val, decoded)
+		}
+	}
+}
+
+func TestEncodeIsCanonical(t *testing.T) {
+	m1 := make(map[Value]Value)
+	m2 := make(map[Value]Value)
+	for i := int64(0); i < 100; i++ {
+		m1[IntVal{i}] = IntVal{i}
+		m2[IntVal{99 - i}] = IntVal{99 - i}
+	}
+	encoded1, _ := Encode(MapVal{m1})
+	encoded2, _ := Encode(MapVal{m2})
+	if !bytes.Equal(encoded1, encoded2) {
+		t.Errorf("equal maps have different encodings")
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"0201",                       // unknown version
+		"01",                         // no value
+		"0111",                       // unknown tag
+		"010101",                     // trailing bytes
+		"010202",                     // bool out of range
+		"010400",                     // truncated nat
+		"010605aa",                   // string longer than input
+		"010c02016201016101",         // struct fields out of order
+		"010d0201060162010106016101", // map keys out of order
+		"010d010a000101",             // list as map key
+	}
+	for _, s := range invalid {
+		data, _ := hex.DecodeString(s)
+		if v, err := Decode(data); err == nil {
+			t.Errorf("decoding %s should fail, got %v", s, v)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package value
+
+import (
+	"fmt"
+	"github.com/nfk93/blockchain/crypto"
+	"sort"
+	"strings"
+)
+
+// Format returns v written like a literal of the contract language, where the language has literals for it.
+// Struct fields and map entries are sorted, so equal values are formatted the same way.
+func Format(v Value) string {
+	switch v := v.(type) {
+	case UnitVal:
+		return "()"
+	case BoolVal:
+		return fmt.Sprintf("%t", v.Value)
+	case IntVal:
+		return fmt.Sprintf("%d", v.Value)
+	case NatVal:
+		return fmt.Sprintf("%dp", v.Value)
+	case KoinVal:
+		return formatKoin(v.Value)
+	case StringVal:
+		return "\"" + v.Value + "\""
+	case KeyVal:
+		return formatAddress(crypto.EncodeAccount, v.Value)
+	case AddressVal:
+		return formatAddress(crypto.EncodeContract, v.Value)
+	case OptionVal:
+		if !v.Opt {
+			return "None"
+		}
+		return "Some " + Format(v.Value)
+	case ListVal:
+		return "[" + formatValues(v.Values, "; ") + "]"
+	case TupleVal:
+		return "(" + formatValues(v.Values, ", ") + ")"
+	case StructVal:
+		fields := make([]string, 0, len(v.Field))
+		for k := range v.Field {
+			fields = append(fields, k)
+		}
+		sort.Strings(fields)
+		var b strings.Builder
+		b.WriteString("{")
+		for _, k := range fields {
+			b.WriteString(fmt.Sprintf("%s = %s; ", k, Format(v.Field[k])))
+		}
+		return strings.TrimSuffix(b.String(), " ") + "}"
+	case MapVal:
+		entries := make([]string, 0, len(v.Values))
+		for k, val := range v.Values {
+			entries = append(entries, fmt.Sprintf("%s -> %s", Format(k), Format(val)))
+		}
+		sort.Strings(entries)
+		return "Map [" + strings.Join(entries, "; ") + "]"
+	case OperationVal:
+		return formatOperation(v.Value)
+	case LambdaVal:
+		return "<fun>"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func formatValues(values []Value, sep string) string {
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		formatted[i] = Format(v)
+	}
+	return strings.Join(formatted, sep)
+}
+
+// formatKoin writes an amount of koin, which is counted in units of 0.00001kn
+func formatKoin(amount uint64) string {
+	whole, fraction := amount/100000, amount%100000
+	if fraction == 0 {
+		return fmt.Sprintf("%dkn", whole)
+	}
+	return fmt.Sprintf("%d.%skn", whole, strings.TrimRight(fmt.Sprintf("%05d", fraction), "0"))
+}
+
+// formatAddress writes a key or contract hash as an address. Hashes that can't be encoded are written as they are
+func formatAddress(encode func(string) (string, error), hash string) string {
+	address, err := encode(hash)
+	if err != nil {
+		return hash
+	}
+	return address
+}
+
+func formatOperation(op Operation) string {
+	switch op := op.(type) {
+	case FailWith:
+		return fmt.Sprintf("<failwith \"%s\">", op.Msg)
+	case Transfer:
+		return fmt.Sprintf("<transfer %s to %s>", formatKoin(op.Amount), formatAddress(crypto.EncodeAccount, op.Key))
+	case ContractCall:
+		return fmt.Sprintf("<call %s.%s %s with %s>", formatAddress(crypto.EncodeContract, op.Address), op.Entry,
+			Format(op.Params), formatKoin(op.Amount))
+	case Schedule:
+		return fmt.Sprintf("<schedule %s.%s %s at slot %d>", formatAddress(crypto.EncodeContract, op.Address), op.Entry,
+			Format(op.Params), op.Slot)
+	default:
+		return fmt.Sprintf("<operation %v>", op)
+	}
+}
@@ -0,0 +1,33 @@
+package value
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		val       Value
+		formatted string
+	}{
+		{UnitVal{}, "()"},
+		{BoolVal{true}, "true"},
+		{IntVal{-3}, "-3"},
+		{NatVal{5}, "5p"},
+		{KoinVal{100000}, "1kn"},
+		{KoinVal{150000}, "1.5kn"},
+		{KoinVal{1}, "0.00001kn"},
+		{StringVal{"hi"}, "\"hi\""},
+		{KeyVal{"ab"}, "ab"},
+		{KeyVal{"0000000000000000000000000000000000000000000000000000000000000000"}, "kn12wkBET2rRgE8pahuaczxKbmv7ciehqsne57F9gtzf1PVgytWuQ"},
+		{OptionVal{Opt: false}, "None"},
+		{OptionVal{IntVal{1}, true}, "Some 1"},
+		{ListVal{[]Value{IntVal{1}, IntVal{2}}}, "[1; 2]"},
+		{TupleVal{[]Value{IntVal{1}, BoolVal{false}}}, "(1, false)"},
+		{StructVal{map[string]Value{"b": IntVal{2}, "a": IntVal{1}}}, "{a = 1; b = 2;}"},
+		{MapVal{map[Value]Value{StringVal{"b"}: UnitVal{}, StringVal{"a"}: UnitVal{}}}, "Map [\"a\" -> (); \"b\" -> ()]"},
+		{OperationVal{Transfer{"k", 100000}}, "<transfer 1kn to k>"},
+	}
+	for _, test := range tests {
+		if formatted := Format(test.val); formatted != test.formatted {
+			t.Errorf("%v formatted as %s, expected %s", test.val, formatted, test.formatted)
+		}
+	}
+}
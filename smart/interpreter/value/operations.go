@@ -17,3 +17,13 @@ type ContractCall struct {
 	Entry   string
 	Params  Value
 }
+
+// Schedule is a contract call to be made at the start of the block of slot Slot. The gas of the call is paid from
+// Deposit, which is reserved from the balance of the scheduling contract
+type Schedule struct {
+	Slot    uint64
+	Address string
+	Entry   string
+	Params  Value
+	Deposit uint64
+}
@@ -80,6 +80,9 @@ func (v OperationVal) Size() uint64 {
 	case ContractCall:
 		op := v.Value.(ContractCall)
 		return addresscost + 64*bitcost + stringSizeVal(op.Entry) + op.Params.Size()
+	case Schedule:
+		op := v.Value.(Schedule)
+		return addresscost + 2*64*bitcost + stringSizeVal(op.Entry) + op.Params.Size()
 	}
 	return 0 // TODO
 }
@@ -136,6 +139,19 @@ func (v StructVal) Size() uint64 {
 	return sum
 }
 
+// Keys are always of a comparable type (see ast.isComparableType), so they can be used directly as go map keys
+type MapVal struct {
+	Values map[Value]Value
+}
+
+func (v MapVal) Size() uint64 {
+	sum := 64 * bitcost
+	for k, v := range v.Values {
+		sum += k.Size() + v.Size()
+	}
+	return sum
+}
+
 type LambdaVal struct {
 	Value ModuleLookup
 }
@@ -158,6 +174,14 @@ const (
 	CONTRACT_CALL
 	ACCOUNT_TRANSFER
 	ACCOUNT_DEFAULT
+	CURRENT_SENDER
+	MAP_EMPTY
+	MAP_MEM
+	MAP_FIND
+	MAP_ADD
+	MAP_REMOVE
+	MAP_SIZE
+	CONTRACT_SCHEDULE
 )
 
 type Code int
@@ -174,6 +198,7 @@ const (
 	TUPLE
 	STRUCT
 	UNIT
+	MAP
 	ERROR
 )
 
@@ -201,6 +226,8 @@ func GetTypeCode(val Value) Code {
 		return STRUCT
 	case UnitVal:
 		return UNIT
+	case MapVal:
+		return MAP
 	default:
 		return ERROR
 	}
@@ -282,6 +309,21 @@ func Equals(val1, val2 Value) bool {
 			}
 		}
 		return true
+	case MAP:
+		val1 := val1.(MapVal)
+		val2 := val2.(MapVal)
+		if len(val1.Values) != len(val2.Values) {
+			return false
+		}
+		for k1, v1 := range val1.Values {
+			v2, exists := val2.Values[k1]
+			if !exists {
+				return false
+			} else if !Equals(v1, v2) {
+				return false
+			}
+		}
+		return true
 	default:
 		log.Printf("can't compare values %s and %s", val1, val2)
 		return false
@@ -334,6 +376,13 @@ func Copy(v Value) Value {
 			m[k1] = Copy(v1)
 		}
 		return StructVal{m}
+	case MapVal:
+		v := v.(MapVal)
+		m := make(map[Value]Value)
+		for k1, v1 := range v.Values {
+			m[k1] = Copy(v1)
+		}
+		return MapVal{m}
 	default:
 		log.Printf("error copying value %s", v)
 		return nil
@@ -169,11 +169,11 @@ var ActTab = ActionTable{
 		Ignore: "",
 	},
 	ActionRow{ // S37
-		Accept: 20,
+		Accept: 2,
 		Ignore: "",
 	},
 	ActionRow{ // S38
-		Accept: 20,
+		Accept: 3,
 		Ignore: "",
 	},
 	ActionRow{ // S39
@@ -184,508 +184,4 @@ var ActTab = ActionTable{
 		Accept: 5,
 		Ignore: "",
 	},
-	ActionRow{ // S41
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S42
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S43
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S44
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S45
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S46
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S47
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S48
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S49
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S50
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S51
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S52
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S53
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S54
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S55
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S56
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S57
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S58
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S59
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S60
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S61
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S62
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S63
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S64
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S65
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S66
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S67
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S68
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S69
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S70
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S71
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S72
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S73
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S74
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S75
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S76
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S77
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S78
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S79
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S80
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S81
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S82
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S83
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S84
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S85
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S86
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S87
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S88
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S89
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S90
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S91
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S92
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S93
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S94
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S95
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S96
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S97
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S98
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S99
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S100
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S101
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S102
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S103
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S104
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S105
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S106
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S107
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S108
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S109
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S110
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S111
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S112
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S113
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S114
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S115
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S116
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S117
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S118
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S119
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S120
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S121
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S122
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S123
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S124
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S125
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S126
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S127
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S128
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S129
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S130
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S131
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S132
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S133
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S134
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S135
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S136
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S137
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S138
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S139
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S140
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S141
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S142
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S143
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S144
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S145
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S146
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S147
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S148
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S149
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S150
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S151
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S152
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S153
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S154
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S155
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S156
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S157
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S158
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S159
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S160
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S161
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S162
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S163
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S164
-		Accept: 20,
-		Ignore: "",
-	},
-	ActionRow{ // S165
-		Accept: 2,
-		Ignore: "",
-	},
-	ActionRow{ // S166
-		Accept: 3,
-		Ignore: "",
-	},
 }
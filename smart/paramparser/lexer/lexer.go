@@ -11,8 +11,8 @@ import (
 
 const (
 	NoState    = -1
-	NumStates  = 167
-	NumSymbols = 51
+	NumStates  = 41
+	NumSymbols = 55
 )
 
 type Lexer struct {
@@ -2,12 +2,12 @@ package valuebuilder
 
 import (
 	"fmt"
+	"github.com/nfk93/blockchain/crypto"
 	. "github.com/nfk93/blockchain/smart/interpreter/value"
 	"github.com/nfk93/blockchain/smart/paramparser/token"
 	"log"
 	"strconv"
 	"strings"
-	"unicode/utf8"
 )
 
 func NewBoolVal(val bool) (Value, error) {
@@ -132,20 +132,11 @@ func ParseId(id interface{}) string {
 }
 
 func ParseKey(key interface{}) (string, error) {
-	return removePrefixAndCheckLen(key)
+	return crypto.DecodeAccount(string(key.(*token.Token).Lit))
 }
 
 func ParseAddress(add interface{}) (string, error) {
-	return removePrefixAndCheckLen(add)
-}
-
-func removePrefixAndCheckLen(str interface{}) (string, error) {
-	str = string(str.(*token.Token).Lit)[3:]
-	if utf8.RuneCountInString(str.(string)) != 64 {
-		return "", fmt.Errorf("keys and addresses must have length 64")
-	} else {
-		return str.(string), nil
-	}
+	return crypto.DecodeContract(string(add.(*token.Token).Lit))
 }
 
 func ParseInt(i interface{}) (int64, error) {
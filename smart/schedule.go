@@ -0,0 +1,87 @@
+package smart
+
+import (
+	"fmt"
+	"github.com/nfk93/blockchain/smart/interpreter/value"
+)
+
+// ScheduledCall is a call made with Contract.schedule. The transaction layer keeps it until the first block with a
+// slot of at least Slot, and makes it at the start of that block. The gas of the call is paid from Deposit, which
+// was reserved from the balance of the Scheduler contract when the call was scheduled. Whatever is left of the
+// deposit after the call is returned to the Scheduler.
+type ScheduledCall struct {
+	Slot      uint64
+	Scheduler string
+	Address   string
+	Entry     string
+	Params    value.Value
+	Deposit   uint64
+}
+
+func (sc ScheduledCall) String() string {
+	return fmt.Sprintf("slot %d: %s.%s %v, deposit %d, scheduled by %s", sc.Slot, sc.Address, sc.Entry, sc.Params,
+		sc.Deposit, sc.Scheduler)
+}
+
+/*
+ * Precondition: blockhash points to an existing state, i.e. _, exists := stateTree[blockhash] is always true
+ *
+ * The resulting ledger is returned even if the call fails, as the remaining deposit is returned to the scheduler
+ * in any case
+ */
+func RunScheduledCall(
+	call ScheduledCall,
+	blockhash string,
+) (resultLedger map[string]uint64, transfers []ContractTransaction, scheduled []ScheduledCall, gasUsed uint64,
+	callError error) {
+	blockstate, exists := stateTree[blockhash]
+	if !exists {
+		// should never happen, because of precondition
+		return nil, nil, nil, 0, fmt.Errorf("blockhash node does not exist for hash: %s", blockhash)
+	}
+
+	newstate, transfers, scheduled, gasUsed, err := runScheduledCall(blockstate, contracts, call)
+	stateTree[blockhash] = newstate
+	return getContractBalances(newstate.contractStates), transfers, scheduled, gasUsed, err
+}
+
+/*
+ * Precondition: newBlockState is defined
+ */
+func RunScheduledCallOnNewBlock(
+	call ScheduledCall,
+) (resultLedger map[string]uint64, transfers []ContractTransaction, scheduled []ScheduledCall, gasUsed uint64,
+	callError error) {
+	newstate, transfers, scheduled, gasUsed, err := runScheduledCall(newBlockState, newBlockAllContracts(), call)
+	newBlockState = newstate
+	return getContractBalances(newstate.contractStates), transfers, scheduled, gasUsed, err
+}
+
+func runScheduledCall(
+	blockstate state,
+	contracts_ map[string]contract,
+	call ScheduledCall,
+) (newstate state, transfers []ContractTransaction, scheduled []ScheduledCall, gasUsed uint64, err error) {
+	var remainingGas uint64
+	if call.Deposit < 10000 {
+		err = fmt.Errorf("not enough gas. calling a contract has a minimum cost of 0.1kn")
+	} else {
+		newstate, transfers, scheduled, remainingGas, err = handleDecodedContractCall(blockstate, contracts_, 0,
+			call.Deposit-10000, call.Address, call.Entry, call.Params, call.Scheduler)
+	}
+	if err != nil {
+		tempStates := make(map[string]contractState)
+		for k, v := range blockstate.contractStates {
+			tempStates[k] = copyContractState(v)
+		}
+		newstate = state{tempStates, blockstate.slot, blockstate.parenthash}
+		transfers, scheduled = nil, nil
+	}
+
+	// return what is left of the deposit, unless the scheduler has expired in the meantime
+	if scheduler, exists := newstate.contractStates[call.Scheduler]; exists {
+		scheduler.Balance += remainingGas
+		newstate.contractStates[call.Scheduler] = scheduler
+	}
+	return newstate, transfers, scheduled, call.Deposit - remainingGas, err
+}
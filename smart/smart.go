@@ -8,6 +8,8 @@ import (
 	"github.com/nfk93/blockchain/smart/interpreter/ast"
 	"github.com/nfk93/blockchain/smart/interpreter/value"
 	"github.com/nfk93/blockchain/smart/paramparser"
+	"github.com/nfk93/blockchain/store"
+	golog "log"
 )
 
 type state struct {
@@ -29,12 +31,75 @@ var stateTree = make(map[string]state)
 var newBlockState state
 var newBlockContracts = make(map[string]contract)
 var log bool
+var db *store.Store
 
-func StartSmartContractLayer(genesishash string, log_ bool) {
+// StartSmartContractLayer starts the layer at the genesis block, or the oldest block a node restores the state of if
+// it pruned the older states. The contracts deployed before the node was stopped
+// are restored from db, and the states of the contracts after the blocks are restored with RestoreBlockState
+func StartSmartContractLayer(genesishash string, log_ bool, db_ *store.Store) error {
 	log = log_
+	db = db_
 
 	contractStates := make(map[string]contractState)
 	stateTree[genesishash] = state{contractStates, 0, ""}
+	return restoreContracts()
+}
+
+// restoreContracts reads the deployed contracts from db and rebuilds their typed ASTs. Imports are resolved with the
+// code of the stored contracts, as only deployed contracts can be imported
+func restoreContracts() error {
+	stored := make(map[string]contract)
+	for _, addr := range db.Keys(store.Contracts) {
+		data, _ := db.Get(store.Contracts, addr)
+		c, err := decodeContract(data)
+		if err != nil {
+			return fmt.Errorf("can't decode stored contract %s: %s", addr, err.Error())
+		}
+		stored[addr] = c
+	}
+	resolver := func(address string) ([]byte, bool) {
+		library, exists := stored[address]
+		return []byte(library.Code), exists
+	}
+	for addr, c := range stored {
+		texp, err := interpreter.TypeContract([]byte(c.Code), resolver)
+		if err != nil {
+			return fmt.Errorf("can't restore contract %s: %s", addr, err.Error())
+		}
+		c.tabs = texp
+		contracts[addr] = c
+	}
+	return nil
+}
+
+func saveContract(addr string, c contract) {
+	if err := db.Put(store.Contracts, addr, c.encode()); err != nil {
+		golog.Println("Couldn't store contract", addr, err)
+	}
+}
+
+// RestoreBlockState rebuilds the state of the contracts after a block from the balances and storage the transaction
+// layer keeps in its state. Blocks have to be restored after their parents
+func RestoreBlockState(blockhash, parenthash string, slot uint64, balances map[string]uint64,
+	stored map[string]StoredContract) error {
+	contractStates := make(map[string]contractState)
+	for addr, c := range stored {
+		storage, err := value.Decode(c.Storage)
+		if err != nil {
+			return fmt.Errorf("can't decode storage of contract %s: %s", addr, err.Error())
+		}
+		contractStates[addr] = contractState{balances[addr], c.PrepaidStorage, storage, c.Storagecap}
+	}
+	stateTree[blockhash] = state{contractStates, slot, parenthash}
+	head = blockhash
+	return nil
+}
+
+// DropBlockState forgets the state of the contracts after a block found to be invalid, and moves the head back to its
+// parent
+func DropBlockState(blockhash, parenthash string) {
+	delete(stateTree, blockhash)
+	head = parenthash
 }
 
 /*
@@ -80,24 +145,27 @@ func CallContract(
 	params string,
 	amount uint64,
 	gas_ uint64,
+	caller string,
 	blockhash string,
-) (resultLedger map[string]uint64, transfers []ContractTransaction, remainingGas uint64, callError error) {
+) (resultLedger map[string]uint64, transfers []ContractTransaction, scheduled []ScheduledCall, remainingGas uint64,
+	callError error) {
 	blockstate, exists := stateTree[blockhash]
 	if !exists {
 		// should never happen, because of precondition
 		errstring := fmt.Sprintf("blockhash node does not exist for hash: %s", blockhash)
-		return nil, nil, 0, fmt.Errorf(errstring)
+		return nil, nil, nil, 0, fmt.Errorf(errstring)
 	}
 
-	newstate, transfers, remainingGas, err := handleContractCall(blockstate, contracts, amount, gas_, address, entry, params)
+	newstate, transfers, scheduled, remainingGas, err := handleContractCall(blockstate, contracts, amount, gas_, address, entry,
+		params, caller)
 	if log {
 		// TODO log contracts and contractstates to file
 	}
 	if err != nil {
-		return nil, nil, remainingGas, err
+		return nil, nil, nil, remainingGas, err
 	} else {
 		stateTree[blockhash] = newstate
-		return getContractBalances(newstate.contractStates), transfers, remainingGas, nil
+		return getContractBalances(newstate.contractStates), transfers, scheduled, remainingGas, nil
 	}
 }
 
@@ -125,7 +193,8 @@ func InitiateContract(
 	if _, exists := newBlockState.contractStates[address]; exists {
 		return "", gas, fmt.Errorf("contract already exists on designated address")
 	}
-	texp, newstate, remainingGas, err := initiateContract(contractCode, address, gas, prepaid, storageLimit, blockstate)
+	texp, newstate, remainingGas, err := initiateContract(contractCode, address, creator.Hash(), gas, prepaid, storageLimit,
+		blockstate, contracts)
 	if log {
 		// TODO log contracts and contractstates to file
 	}
@@ -133,6 +202,7 @@ func InitiateContract(
 		return "", remainingGas, err
 	} else {
 		contracts[address] = contract{string(contractCode), texp, blockstate.slot}
+		saveContract(address, contracts[address])
 		stateTree[blockhash] = newstate
 		return address, remainingGas, nil
 	}
@@ -144,19 +214,31 @@ func FinalizeBlock(blockHash string) {
 		if _, exists := blockstate.contractStates[k]; !exists {
 			if v.CreatedAtSlot < blockstate.slot {
 				delete(contracts, k)
+				if err := db.Delete(store.Contracts, k); err != nil {
+					golog.Println("Couldn't delete contract", k, err)
+				}
 			}
 		}
 	}
 }
 
+// PruneStates forgets the states of the contracts after the given blocks, which no new block can build on
+func PruneStates(blockhashes []string) {
+	for _, blockhash := range blockhashes {
+		if blockhash != head {
+			delete(stateTree, blockhash)
+		}
+	}
+}
+
 /*
  * Precondition: parenthash points to an existing state, i.e. _, exists := stateTree[parenthash] is always true
  */
-func SetStartingPointForNewBlock(parenthash string, slot uint64) (expiring []string, err error) {
+func SetStartingPointForNewBlock(parenthash string, slot uint64) (expiring []string, storagereward uint64, err error) {
 	newBlockContracts = make(map[string]contract)
-	expires, newstate, _ := getNewState(parenthash, slot)
+	expires, newstate, reward := getNewState(parenthash, slot)
 	newBlockState = newstate
-	return expires, nil
+	return expires, reward, nil
 }
 
 func CallContractOnNewBlock(
@@ -165,8 +247,22 @@ func CallContractOnNewBlock(
 	params string,
 	amount uint64,
 	gas_ uint64,
-) (resultLedger map[string]uint64, transfers []ContractTransaction, remainingGas uint64, callError error) {
+	caller string,
+) (resultLedger map[string]uint64, transfers []ContractTransaction, scheduled []ScheduledCall, remainingGas uint64,
+	callError error) {
+
+	newstate, transfers, scheduled, remainingGas, err := handleContractCall(newBlockState, newBlockAllContracts(), amount, gas_,
+		address, entry, params, caller)
+	if err != nil {
+		return nil, nil, nil, remainingGas, err
+	} else {
+		newBlockState = newstate
+		return getContractBalances(newstate.contractStates), transfers, scheduled, remainingGas, nil
+	}
+}
 
+// newBlockAllContracts returns the contracts in the tree together with the contracts created in the new block
+func newBlockAllContracts() map[string]contract {
 	allcontracts := make(map[string]contract)
 	for k, v := range contracts {
 		allcontracts[k] = v
@@ -174,14 +270,7 @@ func CallContractOnNewBlock(
 	for k, v := range newBlockContracts {
 		allcontracts[k] = v
 	}
-
-	newstate, transfers, remainingGas, err := handleContractCall(newBlockState, allcontracts, amount, gas_, address, entry, params)
-	if err != nil {
-		return nil, nil, remainingGas, err
-	} else {
-		newBlockState = newstate
-		return getContractBalances(newstate.contractStates), transfers, remainingGas, nil
-	}
+	return allcontracts
 }
 
 /*
@@ -199,7 +288,8 @@ func InitiateContractOnNewBlock(
 	if _, exists := newBlockState.contractStates[address]; exists {
 		return "", gas, fmt.Errorf("contract already exists on designated address")
 	}
-	texp, newstate, remainingGas, err := initiateContract(contractCode, address, gas, prepaid, storageLimit, newBlockState)
+	texp, newstate, remainingGas, err := initiateContract(contractCode, address, creator.Hash(), gas, prepaid, storageLimit,
+		newBlockState, newBlockAllContracts())
 	if err != nil {
 		return "", remainingGas, err
 	} else {
@@ -222,10 +312,12 @@ type ContractTransaction struct {
 func initiateContract(
 	contractCode []byte,
 	address string,
+	creator string,
 	gas_ uint64,
 	prepaid uint64,
 	storageLimit uint64,
 	blockstate state,
+	contracts_ map[string]contract,
 ) (texp ast.TypedExp, s state, remainingGas uint64, err error) {
 
 	gas := gas_
@@ -243,7 +335,8 @@ func initiateContract(
 		return ast.TypedExp{}, state{}, gas, fmt.Errorf("prepaid Storage is too low")
 	}
 
-	texp, initstor, remainingGas, returnErr := interpreter.InitiateContract(contractCode, gas)
+	texp, initstor, remainingGas, returnErr := interpreter.InitiateContractWithLibraries(contractCode, creator, gas,
+		libraryResolver(blockstate, contracts_))
 
 	if returnErr != nil {
 		return ast.TypedExp{}, state{}, remainingGas, returnErr
@@ -264,6 +357,17 @@ func initiateContract(
 	}
 }
 
+// libraryResolver resolves imports to the code of the contracts that exist in blockstate
+func libraryResolver(blockstate state, contracts_ map[string]contract) interpreter.LibraryResolver {
+	return func(address string) ([]byte, bool) {
+		if _, exists := blockstate.contractStates[address]; !exists {
+			return nil, false
+		}
+		library, exists := contracts_[address]
+		return []byte(library.Code), exists
+	}
+}
+
 func copyContractState(state contractState) contractState {
 	return contractState{Balance: state.Balance, PrepaidStorage: state.PrepaidStorage,
 		Storage: value.Copy(state.Storage), Storagecap: state.Storagecap}
@@ -274,12 +378,13 @@ func handleContractCall(
 	contracts_ map[string]contract,
 	amount, gas_ uint64,
 	address, entry, params string,
-) (newstate state, transfers []ContractTransaction, remainingGas uint64, err error) {
+	caller string,
+) (newstate state, transfers []ContractTransaction, scheduled []ScheduledCall, remainingGas uint64, err error) {
 	// initial cost
 	gas := gas_
 	if int64(gas)-10000 < 0 {
 		gas = 0
-		return state{}, nil, gas, fmt.Errorf("not enough gas. calling a contract has a minimum cost of 0.1kn")
+		return state{}, nil, nil, gas, fmt.Errorf("not enough gas. calling a contract has a minimum cost of 0.1kn")
 	} else {
 		gas = gas - 10000
 	}
@@ -287,20 +392,35 @@ func handleContractCall(
 	// decode parameters
 	paramval, paramErr := decodeParameters(params)
 	if paramErr != nil {
-		return state{}, nil, gas, fmt.Errorf("syntax error in parameters:, %s", paramErr.Error())
+		return state{}, nil, nil, gas, fmt.Errorf("syntax error in parameters:, %s", paramErr.Error())
 	}
+	return handleDecodedContractCall(blockstate, contracts_, amount, gas, address, entry, paramval, caller)
+}
 
+/*
+ * Same as handleContractCall, but for parameters that are already decoded and with the initial cost of the call
+ * already paid
+ */
+func handleDecodedContractCall(
+	blockstate state,
+	contracts_ map[string]contract,
+	amount, gas uint64,
+	address, entry string,
+	paramval value.Value,
+	caller string,
+) (newstate state, transfers []ContractTransaction, scheduled []ScheduledCall, remainingGas uint64, err error) {
 	tempStates := make(map[string]contractState)
 	for k, v := range blockstate.contractStates {
 		tempStates[k] = copyContractState(v)
 	}
 
-	newStates, transfers, gas, callError := interpretContract(address, entry, paramval, amount, gas, tempStates, contracts_)
+	newStates, transfers, scheduled, gas, callError := interpretContract(address, entry, paramval, amount, gas, tempStates,
+		contracts_, caller)
 	if callError != nil {
-		return state{}, nil, gas, callError
+		return state{}, nil, nil, gas, callError
 	} else {
 		newState := state{newStates, blockstate.slot, blockstate.parenthash}
-		return newState, transfers, gas, nil
+		return newState, transfers, scheduled, gas, nil
 	}
 }
 
@@ -312,18 +432,20 @@ func interpretContract(
 	gas_ uint64,
 	states map[string]contractState,
 	contracts_ map[string]contract,
-) (contractStates map[string]contractState, transfers []ContractTransaction, remainingGas uint64, callError error) {
+	sender string,
+) (contractStates map[string]contractState, transfers []ContractTransaction, scheduled []ScheduledCall,
+	remainingGas uint64, callError error) {
 	gas := gas_
 	contract, exist1 := contracts_[address]
 	state, exist2 := states[address]
 	if !exist1 || !exist2 {
-		return nil, nil, gas, fmt.Errorf("attempted to call non-existing contract at address %s", address)
+		return nil, nil, nil, gas, fmt.Errorf("attempted to call non-existing contract at address %s", address)
 	}
 
 	oplist, sto, spent, gas := interpreter.InterpretContractCall(contract.tabs, params, entry, state.Storage, amount,
-		state.Balance, gas)
+		state.Balance, sender, gas)
 	if sto.Size() > state.Storagecap {
-		return nil, nil, gas, fmt.Errorf("Storage cap exceeded")
+		return nil, nil, nil, gas, fmt.Errorf("Storage cap exceeded")
 	}
 
 	state.Storage = sto
@@ -331,11 +453,11 @@ func interpretContract(
 	states[address] = state
 
 	// handle operation list
-	transfers, err, gas := handleOpList(oplist, states, contracts_, gas)
+	transfers, scheduled, err, gas := handleOpList(oplist, states, contracts_, address, gas)
 	if err != nil {
-		return nil, nil, gas, err
+		return nil, nil, nil, gas, err
 	} else {
-		return states, transfers, gas, nil
+		return states, transfers, scheduled, gas, nil
 	}
 }
 
@@ -343,30 +465,37 @@ func handleOpList(
 	operations []value.Operation,
 	tempStates map[string]contractState,
 	contracts_ map[string]contract,
+	caller string,
 	gas uint64,
-) ([]ContractTransaction, error, uint64) {
+) ([]ContractTransaction, []ScheduledCall, error, uint64) {
 	transfers := make([]ContractTransaction, 0)
+	scheduled := make([]ScheduledCall, 0)
 	for _, op := range operations {
 		switch op.(type) {
 		case value.ContractCall:
 			callop := op.(value.ContractCall)
-			tempStates_, trans, remainingGas, callError :=
-				interpretContract(callop.Address, callop.Entry, callop.Params, callop.Amount, gas, tempStates, contracts_)
+			tempStates_, trans, sched, remainingGas, callError :=
+				interpretContract(callop.Address, callop.Entry, callop.Params, callop.Amount, gas, tempStates, contracts_, caller)
 			if callError != nil {
-				return nil, callError, remainingGas
+				return nil, nil, callError, remainingGas
 			} else {
 				tempStates = tempStates_
 				gas = remainingGas
 				transfers = append(transfers, trans...)
+				scheduled = append(scheduled, sched...)
 			}
 		case value.FailWith:
-			return nil, fmt.Errorf(op.(value.FailWith).Msg), gas
+			return nil, nil, fmt.Errorf(op.(value.FailWith).Msg), gas
 		case value.Transfer:
 			transferop := op.(value.Transfer)
 			transfers = append(transfers, ContractTransaction{transferop.Key, transferop.Amount})
+		case value.Schedule:
+			scheduleop := op.(value.Schedule)
+			scheduled = append(scheduled, ScheduledCall{scheduleop.Slot, caller, scheduleop.Address, scheduleop.Entry,
+				scheduleop.Params, scheduleop.Deposit})
 		}
 	}
-	return transfers, nil, gas
+	return transfers, scheduled, nil, gas
 }
 
 func decodeParameters(params string) (value.Value, error) {
@@ -387,6 +516,38 @@ func getContractBalances(states map[string]contractState) map[string]uint64 {
 	return result
 }
 
+// StoredContract is what the state tree of a block holds of a contract besides its balance
+type StoredContract struct {
+	PrepaidStorage uint64
+	Storagecap     uint64
+	Storage        []byte // encoded with value.Encode
+}
+
+// ContractStorage returns the prepaid storage, storage cap and storage of every contract in the state of the given
+// block. The transaction layer puts them in the state tree, so nodes that end up with different contract states
+// reject each others blocks.
+func ContractStorage(blockhash string) map[string]StoredContract {
+	return stateTree[blockhash].stored()
+}
+
+// NewBlockContractStorage is ContractStorage for the block currently being created
+func NewBlockContractStorage() map[string]StoredContract {
+	return newBlockState.stored()
+}
+
+func (s state) stored() map[string]StoredContract {
+	result := make(map[string]StoredContract)
+	for addr, cstate := range s.contractStates {
+		storage, err := value.Encode(cstate.Storage)
+		if err != nil {
+			// storage is always the result of interpreting a contract, so it can always be encoded
+			panic(fmt.Sprintf("can't encode storage of contract %s: %s", addr, err.Error()))
+		}
+		result[addr] = StoredContract{cstate.PrepaidStorage, cstate.Storagecap, storage}
+	}
+	return result
+}
+
 func GetContracts() map[string]contract {
 	return contracts
 }
@@ -3,13 +3,16 @@ package smart
 import (
 	"fmt"
 	"github.com/nfk93/blockchain/crypto"
+	"github.com/nfk93/blockchain/smart/interpreter"
 	"github.com/nfk93/blockchain/smart/interpreter/value"
+	"github.com/nfk93/blockchain/store"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"testing"
 )
 
-var _, pk = crypto.KeyGen(2048)
+var _, pk = crypto.KeyGen()
 
 func TestNewBlockTreeNode(t *testing.T) {
 	reset()
@@ -104,8 +107,8 @@ func TestCallContract(t *testing.T) {
 	_, _ = NewBlockTreeNode("1", "genesis", 5)
 	fundme := getFundMeCode(t)
 	addr, _, _ := InitiateContract(pk, "nonce", fundme, 400000, 100000, 10000, "1")
-	ledger, trans, remainingGas, err := CallContract(addr, "main", "kn1aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
-		100000, 40000, "1")
+	ledger, trans, _, remainingGas, err := CallContract(addr, "main", "kn14EudPR463TiE2yBBJNLFsLHrrhCRB58K4nWjv9Vf6LypiLjApd",
+		100000, 40000, pk.Hash(), "1")
 
 	if err != nil {
 		t.Errorf("error in contractcall: %s", err.Error())
@@ -128,8 +131,8 @@ func TestCallContract(t *testing.T) {
 	}
 
 	params := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaabaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaab"
-	ledger, trans, remainingGas, err = CallContract(addr, "main", "kn1"+params,
-		1100000, 40000, "1")
+	ledger, trans, _, remainingGas, err = CallContract(addr, "main", accountLit(params),
+		1100000, 40000, pk.Hash(), "1")
 	fundmestate, exists = stateTree["1"].contractStates[addr]
 	if !exists {
 		t.Errorf("contract state doesn't exist2")
@@ -159,8 +162,8 @@ func TestCallContract(t *testing.T) {
 		t.Errorf("")
 	}
 
-	ledger, trans, remainingGas, err = CallContract(addr, "main", "kn1"+params,
-		0, 40000, "1")
+	ledger, trans, _, remainingGas, err = CallContract(addr, "main", accountLit(params),
+		0, 40000, pk.Hash(), "1")
 	fundmestate, exists = stateTree["1"].contractStates[addr]
 	if !exists {
 		t.Errorf("contract state doesn't exist2")
@@ -199,11 +202,11 @@ func TestBranching(t *testing.T) {
 	if err != nil {
 		t.Errorf(err.Error())
 	}
-	_, _, _, err = CallContract(addr, "main", "1", 0, 20000, "1")
+	_, _, _, _, err = CallContract(addr, "main", "1", 0, 20000, pk.Hash(), "1")
 	_, _ = NewBlockTreeNode("2", "1", 8)
-	_, _, _, err = CallContract(addr, "main", "1", 0, 20000, "2")
+	_, _, _, _, err = CallContract(addr, "main", "1", 0, 20000, pk.Hash(), "2")
 	_, _ = NewBlockTreeNode("3", "1", 9)
-	_, _, _, err = CallContract(addr, "main", "4", 1, 20000, "3")
+	_, _, _, _, err = CallContract(addr, "main", "4", 1, 20000, pk.Hash(), "3")
 
 	block1state := stateTree["1"].contractStates[addr]
 	if !value.Equals(block1state.Storage, value.IntVal{1}) {
@@ -244,7 +247,7 @@ func TestStorageSizeIncrease(t *testing.T) {
 	if err != nil {
 		t.Errorf(err.Error())
 	}
-	_, _, _, err = CallContract(addr, "main", "1", 0, 20000, "1")
+	_, _, _, _, err = CallContract(addr, "main", "1", 0, 20000, pk.Hash(), "1")
 	if err == nil || err.Error() != "Storage cap exceeded" {
 		t.Errorf("")
 	}
@@ -263,7 +266,7 @@ func TestChainCalls(t *testing.T) {
 	if err != nil {
 		t.Errorf(err.Error())
 	}
-	_, transfers, _, err := CallContract(addr1, "main", fmt.Sprintf("kn2%s", addr2), 33, 100000, "1")
+	_, transfers, _, _, err := CallContract(addr1, "main", contractLit(addr2), 33, 100000, pk.Hash(), "1")
 	if err != nil {
 		t.Errorf(err.Error())
 	}
@@ -328,11 +331,11 @@ func TestExpiringContract(t *testing.T) {
 		t.Errorf("")
 	}
 
-	_, _, _, err = CallContract(addr1, "main", "1", 0, 100000, "4")
+	_, _, _, _, err = CallContract(addr1, "main", "1", 0, 100000, pk.Hash(), "4")
 	if err == nil {
 		t.Errorf("")
 	}
-	_, _, _, err = CallContract(addr2, "main", "1", 0, 100000, "4")
+	_, _, _, _, err = CallContract(addr2, "main", "1", 0, 100000, pk.Hash(), "4")
 	if err != nil {
 		t.Errorf("")
 	}
@@ -370,7 +373,7 @@ func TestFinalizeBlock2(t *testing.T) {
 	_, _ = NewBlockTreeNode("3", "2", 20)
 	addr, _, err := InitiateContract(pk, "nonce", code, 150000, 1000, 64*2, "3")
 	FinalizeBlock("2")
-	_, _, _, err = CallContract(addr, "main", "1", 0, 100000, "3")
+	_, _, _, _, err = CallContract(addr, "main", "1", 0, 100000, pk.Hash(), "3")
 	if err != nil {
 		t.Errorf(err.Error())
 	}
@@ -379,11 +382,11 @@ func TestFinalizeBlock2(t *testing.T) {
 func TestNewBlock(t *testing.T) {
 	reset()
 	_, _ = NewBlockTreeNode("1", "genesis", 5)
-	_, _ = SetStartingPointForNewBlock("1", 11)
+	_, _, _ = SetStartingPointForNewBlock("1", 11)
 	fundme := getFundMeCode(t)
 	addr, _, _ := InitiateContractOnNewBlock(pk, "nonce", fundme, 400000, 100000, 10000)
-	_, _, _, err := CallContractOnNewBlock(addr, "main", "kn1aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
-		100000, 40000)
+	_, _, _, _, err := CallContractOnNewBlock(addr, "main", "kn14EudPR463TiE2yBBJNLFsLHrrhCRB58K4nWjv9Vf6LypiLjApd",
+		100000, 40000, pk.Hash())
 	if err != nil {
 		t.Errorf("error in contractcall: %s", err.Error())
 		return
@@ -425,9 +428,9 @@ func TestNewBlock(t *testing.T) {
 	fmt.Println("old prepaid:     ", prevPre)
 	prevSto := value.Copy(previous.Storage)
 	prevCap := previous.Storagecap
-	_, _ = SetStartingPointForNewBlock("1", 12)
-	_, _, _, err = CallContractOnNewBlock(addr, "main", "kn1aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
-		100000, 40000)
+	_, _, _ = SetStartingPointForNewBlock("1", 12)
+	_, _, _, _, err = CallContractOnNewBlock(addr, "main", "kn14EudPR463TiE2yBBJNLFsLHrrhCRB58K4nWjv9Vf6LypiLjApd",
+		100000, 40000, pk.Hash())
 	if err != nil {
 		t.Errorf("error in contractcall: %s", err.Error())
 		return
@@ -455,6 +458,207 @@ func TestNewBlock(t *testing.T) {
 	}
 }
 
+func TestContractStorage(t *testing.T) {
+	reset()
+	_, _ = NewBlockTreeNode("1", "genesis", 5)
+	code := getSimpleIntStorage(t)
+	addr, _, err := InitiateContract(pk, "nonce", code, 130000, 10000, 64, "1")
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	_, _ = NewBlockTreeNode("2", "1", 8)
+	_, _ = NewBlockTreeNode("3", "1", 8)
+	if !reflect.DeepEqual(ContractStorage("2"), ContractStorage("3")) {
+		t.Errorf("equal states should have equal storage")
+	}
+	if reflect.DeepEqual(ContractStorage("1"), ContractStorage("2")) {
+		t.Errorf("prepaid storage should change when it is paid")
+	}
+
+	_, _, _, _, err = CallContract(addr, "main", "1", 0, 20000, pk.Hash(), "3")
+	if reflect.DeepEqual(ContractStorage("2"), ContractStorage("3")) {
+		t.Errorf("storage should change when the contract is called")
+	}
+
+	// creating the same block as block 3 should give the same storage
+	_, _, _ = SetStartingPointForNewBlock("1", 8)
+	_, _, _, _, err = CallContractOnNewBlock(addr, "main", "1", 0, 20000, pk.Hash())
+	if err != nil {
+		t.Errorf("error in contractcall: %s", err.Error())
+	}
+	if !reflect.DeepEqual(NewBlockContractStorage(), ContractStorage("3")) {
+		t.Errorf("new block storage doesn't match the storage of the same block in the tree")
+	}
+	DoneCreatingNewBlock()
+}
+
+func TestScheduleCall(t *testing.T) {
+	reset()
+	_, _ = NewBlockTreeNode("1", "genesis", 5)
+	code, _ := getCodeBytes(t, os.Getenv("GOPATH")+"/src/github.com/nfk93/blockchain/smart/testcases/scheduler")
+	addr, _, err := InitiateContract(pk, "nonce", code, 1000000, 100000, 10000, "1")
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	params := fmt.Sprintf("(10p, %s)", contractLit(addr))
+	_, _, _, _, err = CallContract(addr, "main", params, 0, 100000, pk.Hash(), "1")
+	if err == nil {
+		t.Errorf("scheduling a call without balance to pay the deposit should fail")
+	}
+	ledger, _, scheduled, _, err := CallContract(addr, "main", params, interpreter.ScheduleDeposit+1, 100000, pk.Hash(), "1")
+	if err != nil {
+		t.Errorf("error in contractcall: %s", err.Error())
+		return
+	}
+	if len(scheduled) != 1 {
+		t.Errorf("expected 1 scheduled call, got %d", len(scheduled))
+		return
+	}
+	expected := ScheduledCall{10, addr, addr, "add", value.IntVal{5}, interpreter.ScheduleDeposit}
+	if sc := scheduled[0]; sc.Slot != expected.Slot || sc.Scheduler != expected.Scheduler || sc.Address != expected.Address ||
+		sc.Entry != expected.Entry || !value.Equals(sc.Params, expected.Params) || sc.Deposit != expected.Deposit {
+		t.Errorf("scheduled call is %v, expected %v", sc, expected)
+	}
+	if ledger[addr] != 1 {
+		t.Errorf("deposit should be reserved from the contract balance, balance is %d", ledger[addr])
+	}
+
+	_, _ = NewBlockTreeNode("2", "1", 10)
+	ledger, _, _, gasUsed, err := RunScheduledCall(scheduled[0], "2")
+	if err != nil {
+		t.Errorf("error in scheduled call: %s", err.Error())
+	}
+	if !value.Equals(stateTree["2"].contractStates[addr].Storage, value.IntVal{5}) {
+		t.Errorf("scheduled call wasn't made, storage is %v", stateTree["2"].contractStates[addr].Storage)
+	}
+	if gasUsed == 0 || ledger[addr] != 1+interpreter.ScheduleDeposit-gasUsed {
+		t.Errorf("unused deposit should be returned, balance is %d and %d gas was used", ledger[addr], gasUsed)
+	}
+
+	// the rest of the deposit is returned even if the call fails
+	failing := scheduled[0]
+	failing.Entry = "nonexisting"
+	_, _ = NewBlockTreeNode("3", "1", 10)
+	ledger, _, _, gasUsed, err = RunScheduledCall(failing, "3")
+	if err == nil {
+		t.Errorf("call of nonexisting entry should fail")
+	}
+	if ledger[addr] != 1+interpreter.ScheduleDeposit-gasUsed {
+		t.Errorf("unused deposit should be returned, balance is %d and %d gas was used", ledger[addr], gasUsed)
+	}
+}
+
+func TestImportLibrary(t *testing.T) {
+	reset()
+	_, _ = NewBlockTreeNode("1", "genesis", 5)
+	library, _ := getCodeBytes(t, os.Getenv("GOPATH")+"/src/github.com/nfk93/blockchain/smart/testcases/counter_lib")
+	libaddr, _, err := InitiateContract(pk, "nonce", library, 1000000, 100000, 10000, "1")
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	code := []byte(fmt.Sprintf(`import Counter = %s
+type storage = Counter.counter
+let%%init storage = {count = 0; step = 3;}
+let%%entry main () s = (([]: operation list), Counter.tick s)`, contractLit(libaddr)))
+	addr, _, err := InitiateContract(pk, "nonce", code, 1000000, 100000, 10000, "1")
+	if err != nil {
+		t.Errorf(err.Error())
+		return
+	}
+	if _, _, _, _, err = CallContract(addr, "main", "()", 0, 100000, pk.Hash(), "1"); err != nil {
+		t.Errorf("error in contractcall: %s", err.Error())
+	}
+	storage := stateTree["1"].contractStates[addr].Storage.(value.StructVal)
+	if !value.Equals(storage.Field["count"], value.IntVal{3}) {
+		t.Errorf("count should be 3 but storage is %v", storage)
+	}
+
+	// libraries have to exist in the state the contract is created in
+	_, _ = NewBlockTreeNode("2", "genesis", 5)
+	if _, _, err = InitiateContract(pk, "nonce", code, 1000000, 100000, 10000, "2"); err == nil {
+		t.Errorf("import of a library that doesn't exist in the state should fail")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "smart")
+	defer os.RemoveAll(dir)
+	db, err := store.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	contracts = make(map[string]contract)
+	stateTree = make(map[string]state)
+	StartSmartContractLayer("genesis", false, db)
+	defer reset()
+
+	_, _ = NewBlockTreeNode("1", "genesis", 5)
+	library, _ := getCodeBytes(t, os.Getenv("GOPATH")+"/src/github.com/nfk93/blockchain/smart/testcases/counter_lib")
+	libaddr, _, err := InitiateContract(pk, "nonce", library, 1000000, 100000, 10000, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := []byte(fmt.Sprintf(`import Counter = %s
+type storage = Counter.counter
+let%%init storage = {count = 0; step = 3;}
+let%%entry main () s = (([]: operation list), Counter.tick s)`, contractLit(libaddr)))
+	addr, _, err := InitiateContract(pk, "nonce", code, 1000000, 100000, 10000, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	balances, _, _, _, err := CallContract(addr, "main", "()", 0, 100000, pk.Hash(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := ContractStorage("1")
+
+	// restart the layer with nothing but the store and the stored states
+	contracts = make(map[string]contract)
+	stateTree = make(map[string]state)
+	if err := StartSmartContractLayer("genesis", false, db); err != nil {
+		t.Fatalf("unexpected error restoring contracts: %s", err.Error())
+	}
+	if len(contracts) != 2 {
+		t.Fatalf("expected 2 restored contracts, got %d", len(contracts))
+	}
+	if err := RestoreBlockState("1", "genesis", 5, balances, stored); err != nil {
+		t.Fatalf("unexpected error restoring state: %s", err.Error())
+	}
+	if _, _, _, _, err = CallContract(addr, "main", "()", 0, 100000, pk.Hash(), "1"); err != nil {
+		t.Errorf("error in contractcall after restoring: %s", err.Error())
+	}
+	storage := stateTree["1"].contractStates[addr].Storage.(value.StructVal)
+	if !value.Equals(storage.Field["count"], value.IntVal{6}) {
+		t.Errorf("count should be 6 but storage is %v", storage)
+	}
+}
+
+func TestPruneStates(t *testing.T) {
+	reset()
+	defer reset()
+	NewBlockTreeNode("1", "genesis", 1)
+	NewBlockTreeNode("fork", "genesis", 2)
+	NewBlockTreeNode("2", "1", 3)
+
+	PruneStates([]string{"genesis", "fork", "2"})
+	if _, exists := stateTree["fork"]; exists {
+		t.Error("the state of fork should have been pruned")
+	}
+	if _, exists := stateTree["genesis"]; exists {
+		t.Error("the state of genesis should have been pruned")
+	}
+	if _, exists := stateTree["2"]; !exists {
+		t.Error("the state of the head should never be pruned")
+	}
+	if _, exists := stateTree["1"]; !exists {
+		t.Error("the state of 1 was not asked to be pruned")
+	}
+}
+
 func getCodeBytes(t *testing.T, filepath string) ([]byte, error) {
 	dat, err := ioutil.ReadFile(filepath)
 	if err != nil {
@@ -500,6 +704,16 @@ func getFundmeStorage(owner string, fundgoal uint64, amountrsd uint64) value.Val
 func reset() {
 	contracts = make(map[string]contract)
 	stateTree = make(map[string]state)
-	StartSmartContractLayer("genesis", false)
+	StartSmartContractLayer("genesis", false, nil)
 	DoneCreatingNewBlock()
 }
+
+func accountLit(keyhash string) string {
+	address, _ := crypto.EncodeAccount(keyhash)
+	return address
+}
+
+func contractLit(addr string) string {
+	address, _ := crypto.EncodeContract(addr)
+	return address
+}
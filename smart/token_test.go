@@ -0,0 +1,158 @@
+package smart
+
+import (
+	"github.com/nfk93/blockchain/smart/interpreter/value"
+	"github.com/nfk93/blockchain/smart/tokenstd"
+	"os"
+	"testing"
+)
+
+var alice = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+var bob = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+var carol = "cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+
+func TestTokenMint(t *testing.T) {
+	addr := initToken(t)
+	if owner, _ := tokenstd.Owner(tokenStorage(addr)); owner != pk.Hash() {
+		t.Errorf("owner should be the creator of the contract but was %s", owner)
+	}
+	params, _ := tokenstd.MintParams(alice, 1000)
+	if err := callToken(addr, tokenstd.EntryMint, params, pk.Hash()); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	checkBalance(t, addr, alice, 1000)
+	checkSupply(t, addr, 1000)
+
+	if err := callToken(addr, tokenstd.EntryMint, params, alice); err == nil {
+		t.Errorf("only the owner should be able to mint")
+	}
+	checkBalance(t, addr, alice, 1000)
+	checkSupply(t, addr, 1000)
+}
+
+func TestTokenTransfer(t *testing.T) {
+	addr := initToken(t)
+	params, _ := tokenstd.MintParams(alice, 1000)
+	callToken(addr, tokenstd.EntryMint, params, pk.Hash())
+
+	params, _ = tokenstd.TransferParams(bob, 300)
+	if err := callToken(addr, tokenstd.EntryTransfer, params, alice); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	checkBalance(t, addr, alice, 700)
+	checkBalance(t, addr, bob, 300)
+
+	params, _ = tokenstd.TransferParams(alice, 500)
+	if err := callToken(addr, tokenstd.EntryTransfer, params, bob); err == nil {
+		t.Errorf("transfer exceeding the balance should fail")
+	}
+	checkBalance(t, addr, alice, 700)
+	checkBalance(t, addr, bob, 300)
+	checkSupply(t, addr, 1000)
+
+	if err := callToken(addr, tokenstd.EntryTransfer, "("+accountLit(alice)+", -5)", bob); err == nil {
+		t.Errorf("transfer of negative amount should fail")
+	}
+	checkBalance(t, addr, bob, 300)
+}
+
+func TestTokenTransferFrom(t *testing.T) {
+	addr := initToken(t)
+	params, _ := tokenstd.MintParams(alice, 1000)
+	callToken(addr, tokenstd.EntryMint, params, pk.Hash())
+
+	params, _ = tokenstd.ApproveParams(carol, 200)
+	if err := callToken(addr, tokenstd.EntryApprove, params, alice); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	checkAllowance(t, addr, alice, carol, 200)
+
+	params, _ = tokenstd.TransferFromParams(alice, bob, 150)
+	if err := callToken(addr, tokenstd.EntryTransferFrom, params, carol); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	checkAllowance(t, addr, alice, carol, 50)
+	checkBalance(t, addr, alice, 850)
+	checkBalance(t, addr, bob, 150)
+	checkBalance(t, addr, carol, 0)
+
+	params, _ = tokenstd.TransferFromParams(alice, bob, 100)
+	if err := callToken(addr, tokenstd.EntryTransferFrom, params, carol); err == nil {
+		t.Errorf("transfer exceeding the allowance should fail")
+	}
+	if err := callToken(addr, tokenstd.EntryTransferFrom, params, bob); err == nil {
+		t.Errorf("transfer without allowance should fail")
+	}
+	checkAllowance(t, addr, alice, carol, 50)
+	checkBalance(t, addr, alice, 850)
+	checkBalance(t, addr, bob, 150)
+}
+
+func TestTokenBurn(t *testing.T) {
+	addr := initToken(t)
+	params, _ := tokenstd.MintParams(pk.Hash(), 100)
+	callToken(addr, tokenstd.EntryMint, params, pk.Hash())
+
+	params, _ = tokenstd.BurnParams(40)
+	if err := callToken(addr, tokenstd.EntryBurn, params, pk.Hash()); err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	}
+	checkBalance(t, addr, pk.Hash(), 60)
+	checkSupply(t, addr, 60)
+
+	if err := callToken(addr, tokenstd.EntryBurn, params, alice); err == nil {
+		t.Errorf("only the owner should be able to burn")
+	}
+	params, _ = tokenstd.BurnParams(61)
+	if err := callToken(addr, tokenstd.EntryBurn, params, pk.Hash()); err == nil {
+		t.Errorf("burning more than the owners balance should fail")
+	}
+	checkSupply(t, addr, 60)
+}
+
+func initToken(t *testing.T) string {
+	reset()
+	_, _ = NewBlockTreeNode("1", "genesis", 5)
+	code, _ := getCodeBytes(t, os.Getenv("GOPATH")+"/src/github.com/nfk93/blockchain/usecases/token")
+	addr, _, err := InitiateContract(pk, "nonce", code, 100000000, 1000000, 1000000, "1")
+	if err != nil {
+		t.Fatalf("error initiating token contract: %s", err.Error())
+	}
+	return addr
+}
+
+func callToken(addr, entry, params, caller string) error {
+	_, _, _, _, err := CallContract(addr, entry, params, 0, 100000000, caller, "1")
+	return err
+}
+
+func tokenStorage(addr string) value.Value {
+	return stateTree["1"].contractStates[addr].Storage
+}
+
+func checkBalance(t *testing.T, addr, holder string, expected int64) {
+	balance, err := tokenstd.Balance(tokenStorage(addr), holder)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	} else if balance != expected {
+		t.Errorf("balance of %s is %d, expected %d", holder[:10], balance, expected)
+	}
+}
+
+func checkSupply(t *testing.T, addr string, expected int64) {
+	supply, err := tokenstd.TotalSupply(tokenStorage(addr))
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	} else if supply != expected {
+		t.Errorf("total supply is %d, expected %d", supply, expected)
+	}
+}
+
+func checkAllowance(t *testing.T, addr, owner, spender string, expected int64) {
+	allowance, err := tokenstd.Allowance(tokenStorage(addr), owner, spender)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+	} else if allowance != expected {
+		t.Errorf("allowance of %s is %d, expected %d", spender[:10], allowance, expected)
+	}
+}
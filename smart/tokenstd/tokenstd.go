@@ -0,0 +1,168 @@
+// Package tokenstd describes the interface of fungible token contracts, as implemented by the reference
+// contract in usecases/token. It builds call parameters for the token entries and reads balances and
+// supply from a token contracts storage.
+package tokenstd
+
+import (
+	"fmt"
+	"github.com/nfk93/blockchain/crypto"
+	"github.com/nfk93/blockchain/smart/interpreter/value"
+	"github.com/nfk93/blockchain/smart/paramparser"
+)
+
+const (
+	EntryTransfer     = "transfer"
+	EntryApprove      = "approve"
+	EntryTransferFrom = "transferFrom"
+	EntryMint         = "mint"
+	EntryBurn         = "burn"
+)
+
+var errNonPositive = fmt.Errorf("amount must be positive")
+
+// TransferParams returns the parameters moving amount tokens from the caller to dest
+func TransferParams(dest string, amount int64) (string, error) {
+	if amount <= 0 {
+		return "", errNonPositive
+	}
+	return encode(fmt.Sprintf("(%s, %d)", keyLit(dest), amount))
+}
+
+// ApproveParams returns the parameters allowing spender to transfer amount tokens on behalf of the caller
+func ApproveParams(spender string, amount int64) (string, error) {
+	if amount < 0 {
+		return "", fmt.Errorf("allowance can't be negative")
+	}
+	return encode(fmt.Sprintf("(%s, %d)", keyLit(spender), amount))
+}
+
+// TransferFromParams returns the parameters moving amount tokens from src to dest, paid from the
+// allowance src has given the caller
+func TransferFromParams(src, dest string, amount int64) (string, error) {
+	if amount <= 0 {
+		return "", errNonPositive
+	}
+	return encode(fmt.Sprintf("(%s, %s, %d)", keyLit(src), keyLit(dest), amount))
+}
+
+// MintParams returns the parameters creating amount new tokens on the account of dest
+func MintParams(dest string, amount int64) (string, error) {
+	if amount <= 0 {
+		return "", errNonPositive
+	}
+	return encode(fmt.Sprintf("(%s, %d)", keyLit(dest), amount))
+}
+
+// BurnParams returns the parameters destroying amount tokens from the owners account
+func BurnParams(amount int64) (string, error) {
+	if amount <= 0 {
+		return "", errNonPositive
+	}
+	return encode(fmt.Sprintf("%d", amount))
+}
+
+func keyLit(keyhash string) string {
+	address, err := crypto.EncodeAccount(keyhash)
+	if err != nil {
+		// not a key hash. The parser rejects the unchecked literal in encode
+		return "kn1" + keyhash
+	}
+	return address
+}
+
+// encode checks that params is parsed by the same parser as the contract layer uses, so malformed keys
+// are caught before a call is sent
+func encode(params string) (string, error) {
+	if _, err := paramparser.ParseParams(params); err != nil {
+		return "", fmt.Errorf("invalid token parameters %s: %s", params, err.Error())
+	}
+	return params, nil
+}
+
+// Balance returns the amount of tokens held by the account with the given key hash
+func Balance(storage value.Value, holder string) (int64, error) {
+	balances, err := mapField(storage, "balances")
+	if err != nil {
+		return 0, err
+	}
+	return intEntry(balances, holder)
+}
+
+// Allowance returns how many tokens spender may still transfer on behalf of owner
+func Allowance(storage value.Value, owner, spender string) (int64, error) {
+	allowances, err := mapField(storage, "allowances")
+	if err != nil {
+		return 0, err
+	}
+	allowed, exists := allowances.Values[value.KeyVal{owner}]
+	if !exists {
+		return 0, nil
+	}
+	allowedMap, ok := allowed.(value.MapVal)
+	if !ok {
+		return 0, fmt.Errorf("storage is not a token storage")
+	}
+	return intEntry(allowedMap, spender)
+}
+
+// TotalSupply returns the amount of tokens in circulation
+func TotalSupply(storage value.Value) (int64, error) {
+	supply, err := field(storage, "total_supply")
+	if err != nil {
+		return 0, err
+	}
+	supplyInt, ok := supply.(value.IntVal)
+	if !ok {
+		return 0, fmt.Errorf("storage is not a token storage")
+	}
+	return supplyInt.Value, nil
+}
+
+// Owner returns the key hash of the account allowed to mint and burn tokens
+func Owner(storage value.Value) (string, error) {
+	owner, err := field(storage, "owner")
+	if err != nil {
+		return "", err
+	}
+	ownerKey, ok := owner.(value.KeyVal)
+	if !ok {
+		return "", fmt.Errorf("storage is not a token storage")
+	}
+	return ownerKey.Value, nil
+}
+
+func field(storage value.Value, name string) (value.Value, error) {
+	structVal, ok := storage.(value.StructVal)
+	if !ok {
+		return nil, fmt.Errorf("storage is not a token storage")
+	}
+	val, exists := structVal.Field[name]
+	if !exists {
+		return nil, fmt.Errorf("storage is not a token storage, it has no field %s", name)
+	}
+	return val, nil
+}
+
+func mapField(storage value.Value, name string) (value.MapVal, error) {
+	val, err := field(storage, name)
+	if err != nil {
+		return value.MapVal{}, err
+	}
+	mapVal, ok := val.(value.MapVal)
+	if !ok {
+		return value.MapVal{}, fmt.Errorf("storage is not a token storage, %s is not a map", name)
+	}
+	return mapVal, nil
+}
+
+func intEntry(m value.MapVal, keyhash string) (int64, error) {
+	val, exists := m.Values[value.KeyVal{keyhash}]
+	if !exists {
+		return 0, nil
+	}
+	intVal, ok := val.(value.IntVal)
+	if !ok {
+		return 0, fmt.Errorf("storage is not a token storage")
+	}
+	return intVal.Value, nil
+}
@@ -0,0 +1,62 @@
+package tokenstd
+
+import (
+	"github.com/nfk93/blockchain/smart/interpreter/value"
+	"github.com/nfk93/blockchain/smart/paramparser"
+	"testing"
+)
+
+var holder = "1234567890abcdef1234567890abcdef1234567890abcdef1234567890abcdef"
+
+func TestTransferParams(t *testing.T) {
+	params, err := TransferParams(holder, 42)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err.Error())
+		return
+	}
+	parsed, _ := paramparser.ParseParams(params)
+	expected := value.TupleVal{[]value.Value{value.KeyVal{holder}, value.IntVal{42}}}
+	if !value.Equals(parsed, expected) {
+		t.Errorf("params parsed to %s, expected %s", parsed, expected)
+	}
+}
+
+func TestInvalidParams(t *testing.T) {
+	if _, err := TransferParams("not a key", 42); err == nil {
+		t.Errorf("malformed key should be rejected")
+	}
+	if _, err := TransferParams(holder[:10], 42); err == nil {
+		t.Errorf("key prefix should be rejected")
+	}
+	if _, err := TransferParams(holder, 0); err == nil {
+		t.Errorf("zero amount should be rejected")
+	}
+	if _, err := BurnParams(-1); err == nil {
+		t.Errorf("negative amount should be rejected")
+	}
+	if _, err := ApproveParams(holder, 0); err != nil {
+		t.Errorf("revoking an allowance should be allowed")
+	}
+}
+
+func TestReadStorage(t *testing.T) {
+	balances := map[value.Value]value.Value{value.KeyVal{holder}: value.IntVal{10}}
+	storage := value.StructVal{map[string]value.Value{
+		"owner":        value.KeyVal{holder},
+		"total_supply": value.IntVal{10},
+		"balances":     value.MapVal{balances},
+		"allowances":   value.MapVal{make(map[value.Value]value.Value)},
+	}}
+	if balance, err := Balance(storage, holder); err != nil || balance != 10 {
+		t.Errorf("wrong balance %d", balance)
+	}
+	if balance, err := Balance(storage, "ff"); err != nil || balance != 0 {
+		t.Errorf("unknown holders should have a balance of 0, had %d", balance)
+	}
+	if supply, err := TotalSupply(storage); err != nil || supply != 10 {
+		t.Errorf("wrong total supply %d", supply)
+	}
+	if _, err := Balance(value.IntVal{1}, holder); err == nil {
+		t.Errorf("reading a non token storage should fail")
+	}
+}
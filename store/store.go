@@ -0,0 +1,309 @@
+package store
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Store is an embedded key/value store on disk, so a node keeps its chain when it restarts. Every change is appended
+// to a log file and synced before Put or Delete returns, and the log is replayed when the store is opened. Only the
+// offsets of the values are kept in memory, the values themselves are read from the log.
+// Keys live in buckets, one for every kind of data a layer of the node keeps. A nil Store keeps nothing, which is
+// what a node running without a data directory uses
+type Store struct {
+	file  *os.File
+	size  int64
+	live  int64 // size of the records holding the current values, the rest of the log can be compacted away
+	index map[string]map[string]entry
+	lock  sync.RWMutex
+}
+
+// Buckets of the store
+const (
+	Blocks    = "blocks"    // blocks by their hash, in the encoding of objects
+	States    = "states"    // state of the transaction layer after every block, by the hash of the block
+	Contracts = "contracts" // code of the deployed contracts, by address
+	Final     = "final"     // block and leadership nonce finalized in every epoch, by epoch
+	Meta      = "meta"      // the head of the chain and the last slot the node ran
+)
+
+const logFile = "chain.log"
+
+// A record is its length and checksum followed by the operation, bucket, key and value, each with its length
+const (
+	opPut    = byte(1)
+	opDelete = byte(2)
+)
+
+type entry struct {
+	offset int64
+	length int
+	size   int64 // size of the whole record
+}
+
+// Open opens the store in dir, creating it if it doesn't exist. A record that was only partly written, because the
+// node stopped in the middle of writing it, is cut off
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(dir, logFile), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{file: file, index: make(map[string]map[string]entry)}
+	if err := s.replay(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) replay() error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	r := bufio.NewReader(io.NewSectionReader(s.file, 0, info.Size()))
+	offset := int64(0)
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			break
+		}
+		record := make([]byte, binary.BigEndian.Uint32(header[:4]))
+		if _, err := io.ReadFull(r, record); err != nil {
+			break
+		}
+		if crc32.ChecksumIEEE(record) != binary.BigEndian.Uint32(header[4:]) {
+			break
+		}
+		op, bucket, key, valueOffset, ok := parseRecord(record)
+		if !ok {
+			break
+		}
+		switch op {
+		case opPut:
+			s.set(bucket, key, entry{offset + 8 + int64(valueOffset), len(record) - valueOffset, 8 + int64(len(record))})
+		case opDelete:
+			s.remove(bucket, key)
+		}
+		offset += 8 + int64(len(record))
+	}
+	if err := s.file.Truncate(offset); err != nil {
+		return err
+	}
+	s.size = offset
+	return nil
+}
+
+// parseRecord returns the operation, bucket and key of a record, and where its value starts
+func parseRecord(record []byte) (op byte, bucket string, key string, valueOffset int, ok bool) {
+	if len(record) == 0 {
+		return 0, "", "", 0, false
+	}
+	op = record[0]
+	i := 1
+	var fields [2]string
+	for f := range fields {
+		length, n := binary.Uvarint(record[i:])
+		if n <= 0 || uint64(len(record)-i-n) < length {
+			return 0, "", "", 0, false
+		}
+		i += n
+		fields[f] = string(record[i : i+int(length)])
+		i += int(length)
+	}
+	return op, fields[0], fields[1], i, true
+}
+
+// Put sets the value of key in bucket
+func (s *Store) Put(bucket, key string, value []byte) error {
+	if s == nil {
+		return nil
+	}
+	buf, valueOffset := encodeRecord(opPut, bucket, key, value)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := s.append(buf); err != nil {
+		return err
+	}
+	s.set(bucket, key, entry{s.size + int64(valueOffset), len(value), int64(len(buf))})
+	s.size += int64(len(buf))
+	return nil
+}
+
+// Delete removes keys from bucket. The log is only synced once, however many keys are removed
+func (s *Store) Delete(bucket string, keys ...string) error {
+	if s == nil || len(keys) == 0 {
+		return nil
+	}
+	var buf []byte
+	for _, key := range keys {
+		record, _ := encodeRecord(opDelete, bucket, key, nil)
+		buf = append(buf, record...)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := s.append(buf); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		s.remove(bucket, key)
+	}
+	s.size += int64(len(buf))
+	return nil
+}
+
+// encodeRecord returns the record with its header, and where its value starts
+func encodeRecord(op byte, bucket, key string, value []byte) ([]byte, int) {
+	record := []byte{op}
+	for _, field := range []string{bucket, key} {
+		record = binary.AppendUvarint(record, uint64(len(field)))
+		record = append(record, field...)
+	}
+	valueOffset := 8 + len(record)
+	record = append(record, value...)
+
+	buf := make([]byte, 8, 8+len(record))
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(record)))
+	binary.BigEndian.PutUint32(buf[4:], crc32.ChecksumIEEE(record))
+	return append(buf, record...), valueOffset
+}
+
+// append writes records to the end of the log and syncs it. PRECONDITION: s is locked
+func (s *Store) append(records []byte) error {
+	if _, err := s.file.WriteAt(records, s.size); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *Store) set(bucket, key string, e entry) {
+	if s.index[bucket] == nil {
+		s.index[bucket] = make(map[string]entry)
+	}
+	s.remove(bucket, key)
+	s.index[bucket][key] = e
+	s.live += e.size
+}
+
+func (s *Store) remove(bucket, key string) {
+	if e, exists := s.index[bucket][key]; exists {
+		s.live -= e.size
+		delete(s.index[bucket], key)
+	}
+}
+
+// Get returns the value of key in bucket, and whether it exists
+func (s *Store) Get(bucket, key string) ([]byte, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	e, exists := s.index[bucket][key]
+	if !exists {
+		return nil, false
+	}
+	value := make([]byte, e.length)
+	if _, err := s.file.ReadAt(value, e.offset); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Keys returns the keys in bucket, sorted
+func (s *Store) Keys(bucket string) []string {
+	if s == nil {
+		return nil
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	keys := make([]string, 0, len(s.index[bucket]))
+	for k := range s.index[bucket] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Compact rewrites the log with only the records holding the current values, if more than half of it is values that
+// were overwritten or deleted since. The new log replaces the old one in a single rename, so a node stopped in the
+// middle of compacting keeps the old log
+func (s *Store) Compact() error {
+	if s == nil {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.size-s.live <= s.live {
+		return nil
+	}
+	path := s.file.Name()
+	file, err := os.OpenFile(path+".compact", os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	index, size, err := s.copyLive(file)
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if dir, err := os.Open(filepath.Dir(path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	s.file.Close()
+	s.file = file
+	s.index = index
+	s.size = size
+	s.live = size
+	return nil
+}
+
+// copyLive writes the current values to file, and returns their index in it and its size. PRECONDITION: s is locked
+func (s *Store) copyLive(file *os.File) (map[string]map[string]entry, int64, error) {
+	index := make(map[string]map[string]entry)
+	w := bufio.NewWriter(file)
+	size := int64(0)
+	for bucket, keys := range s.index {
+		index[bucket] = make(map[string]entry)
+		for key, e := range keys {
+			value := make([]byte, e.length)
+			if _, err := s.file.ReadAt(value, e.offset); err != nil {
+				return nil, 0, err
+			}
+			buf, valueOffset := encodeRecord(opPut, bucket, key, value)
+			if _, err := w.Write(buf); err != nil {
+				return nil, 0, err
+			}
+			index[bucket][key] = entry{size + int64(valueOffset), e.length, int64(len(buf))}
+			size += int64(len(buf))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return nil, 0, err
+	}
+	return index, size, file.Sync()
+}
+
+// Close closes the log file of the store
+func (s *Store) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.file.Close()
+}
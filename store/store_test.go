@@ -0,0 +1,160 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func tempStore(t *testing.T) (*Store, string) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, dir
+}
+
+func TestStore_PutGetDelete(t *testing.T) {
+	s, dir := tempStore(t)
+	defer os.RemoveAll(dir)
+	defer s.Close()
+
+	if err := s.Put(Blocks, "a", []byte("block a")); err != nil {
+		t.Fatal(err)
+	}
+	s.Put(Blocks, "b", []byte("block b"))
+	s.Put(States, "a", []byte("state a"))
+	s.Put(Blocks, "a", []byte("block a again"))
+	s.Delete(Blocks, "b")
+
+	if value, ok := s.Get(Blocks, "a"); !ok || string(value) != "block a again" {
+		t.Errorf("Expected the last value of a, got %q", value)
+	}
+	if _, ok := s.Get(Blocks, "b"); ok {
+		t.Error("b should have been deleted")
+	}
+	if value, _ := s.Get(States, "a"); string(value) != "state a" {
+		t.Errorf("Buckets should be separate, got %q", value)
+	}
+	if keys := s.Keys(Blocks); !reflect.DeepEqual(keys, []string{"a"}) {
+		t.Errorf("Expected keys [a], got %v", keys)
+	}
+}
+
+func TestStore_Reopen(t *testing.T) {
+	s, dir := tempStore(t)
+	defer os.RemoveAll(dir)
+	s.Put(Blocks, "a", []byte("block a"))
+	s.Put(Blocks, "b", []byte("block b"))
+	s.Put(Meta, "empty", nil)
+	s.Delete(Blocks, "a")
+	s.Close()
+
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if _, ok := s.Get(Blocks, "a"); ok {
+		t.Error("The deletion of a was lost")
+	}
+	if value, ok := s.Get(Blocks, "b"); !ok || string(value) != "block b" {
+		t.Errorf("Expected the value of b after reopening, got %q", value)
+	}
+	if value, ok := s.Get(Meta, "empty"); !ok || len(value) != 0 {
+		t.Error("An empty value should be kept")
+	}
+}
+
+func TestStore_TornWrite(t *testing.T) {
+	s, dir := tempStore(t)
+	defer os.RemoveAll(dir)
+	s.Put(Blocks, "a", []byte("block a"))
+	s.Put(Blocks, "b", []byte("block b"))
+	s.Close()
+
+	// cut the last record in half, as if the node stopped while writing it
+	path := filepath.Join(dir, logFile)
+	info, _ := os.Stat(path)
+	if err := os.Truncate(path, info.Size()-5); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get(Blocks, "b"); ok {
+		t.Error("The torn record should have been dropped")
+	}
+	if value, _ := s.Get(Blocks, "a"); string(value) != "block a" {
+		t.Errorf("Expected the value of a, got %q", value)
+	}
+	s.Put(Blocks, "c", []byte("block c"))
+	s.Close()
+
+	s, _ = Open(dir)
+	defer s.Close()
+	if value, _ := s.Get(Blocks, "c"); string(value) != "block c" {
+		t.Errorf("A record written after a torn one should be kept, got %q", value)
+	}
+}
+
+func TestStore_Compact(t *testing.T) {
+	s, dir := tempStore(t)
+	defer os.RemoveAll(dir)
+	s.Put(Blocks, "a", []byte("block a"))
+	s.Put(Blocks, "b", []byte("block b"))
+	s.Put(States, "b", []byte("state b"))
+	s.Delete(Blocks, "a", "b")
+	s.Put(States, "b", []byte("state b again"))
+
+	logPath := filepath.Join(dir, logFile)
+	before, _ := os.Stat(logPath)
+	if err := s.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.Stat(logPath)
+	if after.Size() >= before.Size() {
+		t.Errorf("Compacting should shrink the log, from %d bytes to %d", before.Size(), after.Size())
+	}
+	if value, _ := s.Get(States, "b"); string(value) != "state b again" {
+		t.Errorf("Expected the last value of b after compacting, got %q", value)
+	}
+	s.Put(Blocks, "c", []byte("block c"))
+	s.Close()
+
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if keys := s.Keys(Blocks); !reflect.DeepEqual(keys, []string{"c"}) {
+		t.Errorf("Expected keys [c] after reopening the compacted log, got %v", keys)
+	}
+	if value, _ := s.Get(States, "b"); string(value) != "state b again" {
+		t.Errorf("Expected the last value of b after reopening, got %q", value)
+	}
+
+	// a log that is mostly current values is left as it is
+	before, _ = os.Stat(logPath)
+	s.Compact()
+	if after, _ = os.Stat(logPath); after.Size() != before.Size() {
+		t.Error("A log without much to compact away should not be rewritten")
+	}
+}
+
+func TestStore_Nil(t *testing.T) {
+	var s *Store
+	if err := s.Put(Blocks, "a", []byte("block a")); err != nil {
+		t.Error("A nil store should ignore writes")
+	}
+	if _, ok := s.Get(Blocks, "a"); ok {
+		t.Error("A nil store should be empty")
+	}
+}
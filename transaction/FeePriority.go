@@ -0,0 +1,162 @@
+package transaction
+
+import (
+	"container/heap"
+	. "github.com/nfk93/blockchain/objects"
+	"sort"
+)
+
+// Number of blocks back from the head that EstimateFees looks at
+const feeEstimateBlocks = 20
+
+// FeeEstimate summarizes the gas prices paid by the transactions in the latest blocks
+type FeeEstimate struct {
+	Blocks       int
+	Transactions int
+	Lowest       uint64
+	Median       uint64
+	Highest      uint64
+}
+
+// Returns the fee a transaction has to pay to buy its gas at gasPrice
+func TransferFee(gasPrice uint64) uint64 {
+	return transactionGas * gasPrice
+}
+
+// Returns the gas prices paid in the blocks leading up to the current head
+func EstimateFees() FeeEstimate {
+	tLock.RLock()
+	defer tLock.RUnlock()
+	var prices []uint64
+	blocks := 0
+	for node, exists := tree.treeMap[tree.head]; exists && blocks < feeEstimateBlocks; node, exists = tree.treeMap[node.block.ParentPointer] {
+		for _, td := range node.block.BlockData.Trans {
+			prices = append(prices, gasPrice(td))
+		}
+		blocks++
+	}
+	estimate := FeeEstimate{Blocks: blocks, Transactions: len(prices)}
+	if len(prices) == 0 {
+		return estimate
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i] < prices[j] })
+	estimate.Lowest = prices[0]
+	estimate.Median = prices[len(prices)/2]
+	estimate.Highest = prices[len(prices)-1]
+	return estimate
+}
+
+// Returns the most gas td can use
+func maxGas(td TransData) uint64 {
+	switch td.GetType() {
+	case TRANSACTION, BAKERREGISTRATION, DELEGATION:
+		return transactionGas
+	case CONTRACTCALL:
+		return td.ContractCall.Gas
+	case CONTRACTINIT:
+		return td.ContractInit.Gas
+	default:
+		return 0
+	}
+}
+
+// Returns the price td pays for each unit of gas. Contract initializations pay the minimum gas price
+func gasPrice(td TransData) uint64 {
+	switch td.GetType() {
+	case TRANSACTION:
+		return td.Transaction.Fee / transactionGas
+	case BAKERREGISTRATION:
+		return td.Registration.Fee / transactionGas
+	case DELEGATION:
+		return td.Delegation.Fee / transactionGas
+	case CONTRACTCALL:
+		return td.ContractCall.GasPrice
+	default:
+		return MinGasPrice
+	}
+}
+
+// Returns the fee paid by td when it used gasUsed gas
+func fee(td TransData, gasUsed uint64) uint64 {
+	switch td.GetType() {
+	case TRANSACTION:
+		return td.Transaction.Fee
+	case BAKERREGISTRATION:
+		return td.Registration.Fee
+	case DELEGATION:
+		return td.Delegation.Fee
+	case CONTRACTCALL:
+		return gasUsed * td.ContractCall.GasPrice
+	default:
+		return gasUsed
+	}
+}
+
+// feeQueue picks transactions by their gas price, highest first. Each account is a queue of its transactions in the
+// order of their nonces, and only the first transaction of every account competes, since the others can't be
+// included before it
+type feeQueue struct {
+	accounts [][]TransData
+}
+
+func newFeeQueue(transList []TransData) *feeQueue {
+	q := &feeQueue{}
+	var current []TransData
+	for _, td := range orderByNonce(transList) {
+		if len(current) > 0 && current[0].Sender() != td.Sender() {
+			q.accounts = append(q.accounts, current)
+			current = nil
+		}
+		current = append(current, td)
+	}
+	if len(current) > 0 {
+		q.accounts = append(q.accounts, current)
+	}
+	heap.Init(q)
+	return q
+}
+
+// Returns the transaction with the highest gas price, or false if there are no transactions left
+func (q *feeQueue) next() (TransData, bool) {
+	if len(q.accounts) == 0 {
+		return TransData{}, false
+	}
+	return q.accounts[0][0], true
+}
+
+// Moves on to the next transaction of the account of the transaction returned by next
+func (q *feeQueue) advance() {
+	q.accounts[0] = q.accounts[0][1:]
+	if len(q.accounts[0]) == 0 {
+		heap.Pop(q)
+	} else {
+		heap.Fix(q, 0)
+	}
+}
+
+// Drops the remaining transactions of the account of the transaction returned by next, as none of them can be
+// included in the block
+func (q *feeQueue) dropAccount() {
+	heap.Pop(q)
+}
+
+func (q feeQueue) Len() int { return len(q.accounts) }
+
+func (q feeQueue) Less(i, j int) bool {
+	pi, pj := gasPrice(q.accounts[i][0]), gasPrice(q.accounts[j][0])
+	if pi != pj {
+		return pi > pj
+	}
+	// Same price, so the order is only made deterministic
+	return q.accounts[i][0].Sender().Hash() < q.accounts[j][0].Sender().Hash()
+}
+
+func (q feeQueue) Swap(i, j int) { q.accounts[i], q.accounts[j] = q.accounts[j], q.accounts[i] }
+
+func (q *feeQueue) Push(x interface{}) { q.accounts = append(q.accounts, x.([]TransData)) }
+
+func (q *feeQueue) Pop() interface{} {
+	last := q.accounts[len(q.accounts)-1]
+	q.accounts = q.accounts[:len(q.accounts)-1]
+	return last
+}
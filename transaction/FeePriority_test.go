@@ -0,0 +1,92 @@
+package transaction
+
+import (
+	. "github.com/nfk93/blockchain/crypto"
+	. "github.com/nfk93/blockchain/objects"
+	"testing"
+	"time"
+)
+
+func TestFeeQueue(t *testing.T) {
+	skA, pkA := KeyGen()
+	skB, pkB := KeyGen()
+	skC, pkC := KeyGen()
+	_, to := KeyGen()
+
+	transfer := func(sk SecretKey, pk PublicKey, gasPrice, nonce uint64) TransData {
+		return TransData{Transaction: CreateTransaction(pk, to.Hash(), 100, TransferFee(gasPrice), nonce, 0, sk)}
+	}
+	a1 := transfer(skA, pkA, 3, 1)
+	a0 := transfer(skA, pkA, 3, 0)
+	b0 := transfer(skB, pkB, 2, 0)
+	c0 := transfer(skC, pkC, 5, 0)
+	c1 := transfer(skC, pkC, 1, 1)
+
+	// The cheap second transaction of C waits until last, while A keeps the order of its nonces
+	queue := newFeeQueue([]TransData{a1, b0, c1, a0, c0})
+	expected := []TransData{c0, a0, a1, b0, c1}
+	for i, want := range expected {
+		td, ok := queue.next()
+		if !ok {
+			t.Fatalf("Queue ran out after %d transactions", i)
+		}
+		if td.Hash() != want.Hash() {
+			t.Errorf("Transaction %d has nonce %d and gas price %d, expected nonce %d and gas price %d",
+				i, td.GetNonce(), gasPrice(td), want.GetNonce(), gasPrice(want))
+		}
+		queue.advance()
+	}
+	if _, ok := queue.next(); ok {
+		t.Error("Queue should be empty")
+	}
+}
+
+func TestFeeQueueDropAccount(t *testing.T) {
+	skA, pkA := KeyGen()
+	skB, pkB := KeyGen()
+	_, to := KeyGen()
+
+	a0 := TransData{Transaction: CreateTransaction(pkA, to.Hash(), 100, TransferFee(4), 0, 0, skA)}
+	a1 := TransData{Transaction: CreateTransaction(pkA, to.Hash(), 100, TransferFee(4), 1, 0, skA)}
+	b0 := TransData{Transaction: CreateTransaction(pkB, to.Hash(), 100, TransferFee(2), 0, 0, skB)}
+
+	queue := newFeeQueue([]TransData{a0, a1, b0})
+	if td, _ := queue.next(); td.Hash() != a0.Hash() {
+		t.Fatal("Expected the transaction with the highest gas price first")
+	}
+	queue.dropAccount()
+	if td, ok := queue.next(); !ok || td.Hash() != b0.Hash() {
+		t.Error("Dropping an account should drop all of its transactions")
+	}
+}
+
+func TestCreateNewBlockFeeOrder(t *testing.T) {
+	sk1, pk1 := KeyGen()
+	sk2, pk2 := KeyGen()
+	_, pk3 := KeyGen()
+	channels := CreateChannelStruct()
+	go StartTransactionLayer(channels, false, nil)
+
+	genBlock := CreateTestGenesis(pk1)
+	channels.BlockToTrans <- genBlock
+	time.Sleep(time.Millisecond * 300)
+
+	// Give pk2 something to pay with
+	fund := TransData{Transaction: CreateTransaction(pk1, pk2.Hash(), 1000000, TransferFee(MinGasPrice), 0, 0, sk1)}
+	channels.TransToTrans <- CreateBlockData{[]TransData{fund}, sk1, pk1, 1, "", BlockNonce{}, "", genBlock.CalculateBlockHash()}
+	block1 := <-channels.BlockFromTrans
+	channels.BlockToTrans <- block1
+	time.Sleep(time.Millisecond * 300)
+
+	cheap := TransData{Transaction: CreateTransaction(pk1, pk3.Hash(), 100, TransferFee(MinGasPrice), 1, 0, sk1)}
+	expensive := TransData{Transaction: CreateTransaction(pk2, pk3.Hash(), 100, TransferFee(10), 0, 0, sk2)}
+	channels.TransToTrans <- CreateBlockData{[]TransData{cheap, expensive}, sk1, pk1, 2, "", BlockNonce{}, "", block1.CalculateBlockHash()}
+	block2 := <-channels.BlockFromTrans
+	trans := block2.BlockData.Trans
+	if len(trans) != 2 || trans[0].Hash() != expensive.Hash() || trans[1].Hash() != cheap.Hash() {
+		t.Error("Transactions paying a higher gas price should be included first")
+	}
+	if !block2.ValidateBlock() {
+		t.Error("Block validation failed")
+	}
+}
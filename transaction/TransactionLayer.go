@@ -5,7 +5,9 @@ import (
 	"github.com/nfk93/blockchain/crypto"
 	. "github.com/nfk93/blockchain/objects"
 	"github.com/nfk93/blockchain/smart"
+	"github.com/nfk93/blockchain/store"
 	"log"
+	"sort"
 	"sync"
 )
 
@@ -23,28 +25,49 @@ var tree Tree
 var tLock sync.RWMutex
 var logToFile bool
 var verbose bool
+var db *store.Store
 
 const transactionGas = uint64(10000)   // 1 Transaction costs 0.1 koin
 const blockReward = uint64(1000000000) // block reward is 2 times the max gas reward = 10.000 koins
 const gasLimit = uint64(100000000)     // 1 block can contain 10.000 transactions = 1.000 gas koins(Gas limit for blocks) TODO What is good numbers?
 
-func StartTransactionLayer(channels ChannelStruct, log_ bool) {
+// StartTransactionLayer processes the blocks and requests of the consensus layer. The states stored in db are
+// restored first, so a node that is restarted continues from the blocks it had
+func StartTransactionLayer(channels ChannelStruct, log_ bool, db_ *store.Store) {
 	tree = Tree{make(map[string]TreeNode), ""}
 	verbose = false
 	logToFile = log_
+	db = db_
+	if err := tree.restore(); err != nil {
+		log.Fatal("Couldn't restore the states of the transaction layer: ", err)
+	}
 	// Process a Block coming from the consensus layer
+	// States are pruned on the same thread, so no state is forgotten while a block is built on it
 	go func() {
 		for {
-			b := <-channels.BlockToTrans
-			if len(tree.treeMap) == 0 && b.Slot == 0 && b.ParentPointer == "" {
-				tree.createNewNode(b, b.BlockData.GenesisData.InitialState)
-				smart.StartSmartContractLayer(tree.head, log_)
-			} else if len(tree.treeMap) > 0 {
-				if _, exist := tree.treeMap[b.CalculateBlockHash()]; !exist {
-					tree.processBlock(b)
+			select {
+			case b := <-channels.BlockToTrans:
+				if len(tree.treeMap) == 0 && b.Slot == 0 && b.ParentPointer == "" {
+					tree.createNewNode(b, b.BlockData.GenesisData.InitialState)
+					if err := smart.StartSmartContractLayer(tree.head, log_, db); err != nil {
+						log.Println(err)
+					}
+				} else if len(tree.treeMap) > 0 {
+					hash := b.CalculateBlockHash()
+					if _, exist := tree.treeMap[hash]; exist {
+						// the consensus layer moved its head back to a block we have the state of
+						tree.setHead(hash)
+					} else if !tree.processBlock(b) {
+						// consensus may be sending us the next block, so it is told on another thread
+						go func() {
+							channels.InvalidFromTrans <- hash
+						}()
+					}
+				} else {
+					log.Println("Tree not initialized. Please send Genesis Node!! ")
 				}
-			} else {
-				log.Println("Tree not initialized. Please send Genesis Node!! ")
+			case hashes := <-channels.PruneToTrans:
+				channels.PrunedFromTrans <- tree.prune(hashes)
 			}
 		}
 	}()
@@ -64,61 +87,82 @@ func StartTransactionLayer(channels ChannelStruct, log_ bool) {
 	}
 }
 
-func (t *Tree) processBlock(b Block) {
+// processBlock computes the state after b and adds it to the tree. Returns false if b is invalid: its parent is
+// invalid, it uses more gas than a block can, or its state root isn't the root of the computed state. No state is
+// added for an invalid block, so no block is built on it
+func (t *Tree) processBlock(b Block) bool {
 
 	blockHash := b.CalculateBlockHash()
-	s := State{}
-	s.ParentHash = b.ParentPointer
 
 	tLock.RLock()
-	s.Ledger = copyMap(t.treeMap[s.ParentHash].state.Ledger)
-	s.ConStake = copyMap(t.treeMap[s.ParentHash].state.ConStake)
-	s.ConOwners = copyContMap(t.treeMap[s.ParentHash].state.ConOwners)
-	s.TotalStake = t.treeMap[s.ParentHash].state.TotalStake
+	parent, parentExists := t.treeMap[b.ParentPointer]
 	tLock.RUnlock()
+	if !parentExists {
+		log.Println(fmt.Sprintf("block %s is invalid, there is no state of its parent", blockHash))
+		return false
+	}
+	s := copyState(parent.state)
+	s.ParentHash = b.ParentPointer
 
 	// Remove expired contracts from ledger in TL and from ConLayer
 	// Collection storageCosts
 	expiring, storageReward := smart.NewBlockTreeNode(blockHash, b.ParentPointer, b.Slot)
 	s.CleanExpiredContract(expiring)
 
+	// Make the contract calls scheduled for this slot
+	scheduledGas, errs := s.RunScheduledCalls(b.Slot, blockHash)
+	for _, err := range errs {
+		if verbose {
+			log.Println(err)
+		}
+	}
+
 	// Update state
 	accumulatedGas := uint64(0)
-	if len(b.BlockData.Trans) != 0 {
-		for _, td := range b.BlockData.Trans {
-			switch td.GetType() {
-			case CONTRACTCALL:
-				accGas, err := s.HandleContractCall(td.ContractCall, blockHash, b.ParentPointer, b.Slot)
-				accumulatedGas += accGas
-				if err != nil && verbose {
-					log.Println(err)
-				}
-			case CONTRACTINIT:
-				accGas, err := s.HandleContractInit(td.ContractInit, blockHash, b.ParentPointer, b.Slot)
-				accumulatedGas += accGas
-				if err != nil && verbose {
-					log.Println(err)
-				}
-			case TRANSACTION:
-				accGas, err := s.AddTransaction(td.Transaction, transactionGas)
-				accumulatedGas += accGas
-				if err != nil && verbose {
-					log.Println(err)
-				}
-			}
+	accumulatedFees := uint64(0)
+	for _, td := range b.BlockData.Trans {
+		var gasUsed, feePaid uint64
+		var err error
+		switch td.GetType() {
+		case CONTRACTCALL:
+			gasUsed, err = s.HandleContractCall(td.ContractCall, blockHash, b.ParentPointer, b.Slot)
+			feePaid = fee(td, gasUsed)
+		case CONTRACTINIT:
+			gasUsed, err = s.HandleContractInit(td.ContractInit, blockHash, b.ParentPointer, b.Slot)
+			feePaid = fee(td, gasUsed)
+		case TRANSACTION:
+			feePaid, err = s.AddTransaction(td.Transaction, transactionGas)
+			gasUsed = transactionGas
+		case BAKERREGISTRATION:
+			feePaid, err = s.RegisterBaker(td.Registration, transactionGas)
+			gasUsed = transactionGas
+		case DELEGATION:
+			feePaid, err = s.Delegate(td.Delegation, transactionGas)
+			gasUsed = transactionGas
+		}
+		accumulatedGas += gasUsed
+		accumulatedFees += feePaid
+		if err != nil && verbose {
+			log.Println(err)
 		}
 	}
 
+	s.SetContractStorage(smart.ContractStorage(blockHash))
+
+	if accumulatedGas > gasLimit {
+		log.Println(fmt.Sprintf("block %s is invalid, it exceeds maximum gas capacity", blockHash))
+		smart.DropBlockState(blockHash, b.ParentPointer)
+		return false
+	}
+	// Pay the block creator, or the account it bakes for. The root of the block is of the state after the reward
+	totalReward := accumulatedFees + scheduledGas + storageReward + blockReward
+	s.PayBaker(b.BakerID, totalReward)
+
 	// Verify our new state matches the state of the block creator to ensure he has also done the same work
-	if !s.VerifyHashedState(b.StateHash, b.BakerID) {
-		log.Println(fmt.Sprintf("State hash in block %s didn't match hash of computed state", b.CalculateBlockHash()))
-		//fmt.Println(s)
-	} else if accumulatedGas > gasLimit {
-		log.Println(fmt.Sprintf("block %s exceeds maximum gas capacity", b.CalculateBlockHash()))
-	} else {
-		// Pay the block creator
-		totalReward := accumulatedGas + storageReward + blockReward
-		s.AddAmountToAccount(b.BakerID, totalReward)
+	if s.Root() != b.StateRoot {
+		log.Println(fmt.Sprintf("block %s is invalid, its state root didn't match root of computed state", blockHash))
+		smart.DropBlockState(blockHash, b.ParentPointer)
+		return false
 	}
 
 	// Create new node in the tree
@@ -127,7 +171,7 @@ func (t *Tree) processBlock(b Block) {
 	if logToFile {
 		// TODO: logToFile the ledger state to filepath out/slotno_blockhash[:6]
 	}
-
+	return true
 }
 
 func (t *Tree) finalize(blockHash string) State {
@@ -147,77 +191,167 @@ func (t *Tree) createNewNode(b Block, s State) {
 	defer tLock.Unlock()
 	blockHash := b.CalculateBlockHash()
 	t.treeMap[blockHash] = TreeNode{b, s}
+	if err := db.Put(store.States, blockHash, s.Encode()); err != nil {
+		log.Println("Couldn't store the state of block", blockHash, err)
+	}
 
 	// Update head
 	t.head = blockHash
 }
 
-func (t *Tree) createNewBlock(blockData CreateBlockData) Block {
-	s := State{}
+func (t *Tree) setHead(blockHash string) {
+	tLock.Lock()
+	defer tLock.Unlock()
+	t.head = blockHash
+}
+
+// prune forgets the states of the given blocks, here and in the smart contract layer, and deletes them from db.
+// The state of the head is always kept. Returns the number of states forgotten
+func (t *Tree) prune(hashes []string) int {
+	tLock.Lock()
+	defer tLock.Unlock()
+	var pruned []string
+	for _, hash := range hashes {
+		if _, exists := t.treeMap[hash]; exists && hash != t.head {
+			delete(t.treeMap, hash)
+			pruned = append(pruned, hash)
+		}
+	}
+	smart.PruneStates(pruned)
+	if err := db.Delete(store.States, pruned...); err != nil {
+		log.Println("Couldn't delete the pruned states", err)
+	}
+	return len(pruned)
+}
+
+// restore rebuilds the tree from the blocks and states in db, together with the states of the smart contract layer.
+// Blocks are restored in the order of their slots, so parents come before their children. A node that prunes its
+// states starts from the oldest state it kept instead of the genesis block
+func (t *Tree) restore() error {
+	var nodes []TreeNode
+	for _, hash := range db.Keys(store.States) {
+		blockData, ok := db.Get(store.Blocks, hash)
+		if !ok {
+			continue
+		}
+		b, err := DecodeBlock(blockData)
+		if err != nil {
+			return err
+		}
+		stateData, _ := db.Get(store.States, hash)
+		s, err := DecodeState(stateData)
+		if err != nil {
+			return err
+		}
+		nodes = append(nodes, TreeNode{b, s})
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].block.Slot < nodes[j].block.Slot
+	})
+	if err := smart.StartSmartContractLayer(nodes[0].block.CalculateBlockHash(), logToFile, db); err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		hash := node.block.CalculateBlockHash()
+		err := smart.RestoreBlockState(hash, node.block.ParentPointer, node.block.Slot,
+			node.state.ContractBalances(), node.state.ContractStorage())
+		if err != nil {
+			return err
+		}
+		t.treeMap[hash] = node
+	}
+	if head, ok := db.Get(store.Meta, "head"); ok {
+		if _, exists := t.treeMap[string(head)]; exists {
+			t.head = string(head)
+		}
+	}
+	if t.head == "" {
+		t.head = nodes[len(nodes)-1].block.CalculateBlockHash()
+	}
+	return nil
+}
 
+func (t *Tree) createNewBlock(blockData CreateBlockData) Block {
 	tLock.RLock()
-	s.ParentHash = blockData.ParentHash
-	s.Ledger = copyMap(t.treeMap[blockData.ParentHash].state.Ledger)
-	s.ConStake = copyMap(t.treeMap[blockData.ParentHash].state.ConStake)
-	s.ConOwners = copyContMap(t.treeMap[blockData.ParentHash].state.ConOwners)
-	s.TotalStake = t.treeMap[s.ParentHash].state.TotalStake
+	s := copyState(t.treeMap[blockData.ParentHash].state)
 	tLock.RUnlock()
+	s.ParentHash = blockData.ParentHash
 
 	var addedTransactions []TransData
 
-	expiring, err := smart.SetStartingPointForNewBlock(blockData.ParentHash, blockData.SlotNo)
+	expiring, storageReward, err := smart.SetStartingPointForNewBlock(blockData.ParentHash, blockData.SlotNo)
 	if err != nil {
 		// this should not happen
 		log.Fatal("trying to create a new block which parent doesn't exist")
 	}
 	// Remove expired contracts from ledger in TL and from ConLayer
 	s.CleanExpiredContract(expiring)
+
+	// Make the contract calls scheduled for this slot
+	scheduledGas, errs := s.RunScheduledCalls(blockData.SlotNo, "")
+	for _, err := range errs {
+		if verbose {
+			log.Println(err)
+		}
+	}
 	print := false
 	accumulatedGasUse := uint64(0)
-	for _, td := range blockData.TransList {
-		if accumulatedGasUse+transactionGas > gasLimit {
-			break
+	accumulatedFees := uint64(0)
+	// Transactions paying the highest gas price are included first, as long as they fit within the gas limit
+	queue := newFeeQueue(blockData.TransList)
+	for td, ok := queue.next(); ok; td, ok = queue.next() {
+		// Transactions with a later nonce are left out, so they stay in the pool of unused transactions until
+		// the transactions before them are included. Replayed nonces and expired transactions are skipped, and the
+		// consensus layer evicts them from the pool
+		next := s.NextNonce(td.Sender().Hash())
+		if td.GetNonce() < next || td.Expired(blockData.SlotNo) {
+			queue.advance()
+			continue
+		}
+		if td.GetNonce() > next || accumulatedGasUse+maxGas(td) > gasLimit {
+			queue.dropAccount()
+			continue
 		}
+		queue.advance()
+
+		var gasUsed, feePaid uint64
+		var err error
 		switch td.GetType() {
 		case CONTRACTCALL:
-
-			oldState := copyState(s)
-			gasUsed, err := s.HandleContractCall(td.ContractCall, "", blockData.ParentHash, blockData.SlotNo)
-			if err != nil && verbose {
-				log.Println(err)
-			}
-
-			if accumulatedGasUse+gasUsed > gasLimit {
-				s = oldState
-			} else {
-				accumulatedGasUse += gasUsed
-				addedTransactions = append(addedTransactions, td)
-			}
+			gasUsed, err = s.HandleContractCall(td.ContractCall, "", blockData.ParentHash, blockData.SlotNo)
+			feePaid = fee(td, gasUsed)
 		case CONTRACTINIT:
-			oldState := copyState(s)
-			gasUsed, err := s.HandleContractInit(td.ContractInit, "", blockData.ParentHash, blockData.SlotNo)
-			if err != nil && verbose {
-				log.Println(err)
-			}
-			if accumulatedGasUse+gasUsed > gasLimit {
-				s = oldState
-			} else {
-				accumulatedGasUse += gasUsed
-				addedTransactions = append(addedTransactions, td)
-			}
+			gasUsed, err = s.HandleContractInit(td.ContractInit, "", blockData.ParentHash, blockData.SlotNo)
+			feePaid = fee(td, gasUsed)
 		case TRANSACTION:
-			gasUsed, err := s.AddTransaction(td.Transaction, transactionGas)
-			if err != nil && verbose {
-				log.Println(err)
-			}
-			accumulatedGasUse += gasUsed
-			addedTransactions = append(addedTransactions, td)
+			feePaid, err = s.AddTransaction(td.Transaction, transactionGas)
+			gasUsed = transactionGas
+		case BAKERREGISTRATION:
+			feePaid, err = s.RegisterBaker(td.Registration, transactionGas)
+			gasUsed = transactionGas
+		case DELEGATION:
+			feePaid, err = s.Delegate(td.Delegation, transactionGas)
+			gasUsed = transactionGas
+		default:
+			continue
+		}
+		if err != nil && verbose {
+			log.Println(err)
 		}
+		accumulatedGasUse += gasUsed
+		accumulatedFees += feePaid
+		addedTransactions = append(addedTransactions, td)
 	}
 
 	if print {
 		//fmt.Println(s)
 	}
+	s.SetContractStorage(smart.NewBlockContractStorage())
+	// The state root covers the reward of the baker, so it matches the state other nodes store for the block
+	s.PayBaker(blockData.Pk, accumulatedFees+scheduledGas+storageReward+blockReward)
 
 	b := Block{blockData.SlotNo,
 		blockData.ParentHash,
@@ -226,44 +360,108 @@ func (t *Tree) createNewBlock(blockData CreateBlockData) Block {
 		blockData.BlockNonce,
 		blockData.LastFinalized,
 		BlockData{addedTransactions, GenesisData{}},
-		s.SignHashedState(blockData.Sk),
+		"",
+		s.Root(),
 		""}
 
 	b.SignBlock(blockData.Sk)
 	smart.DoneCreatingNewBlock()
 	return b
 }
+
+// orderByNonce sorts the transactions of each account by their nonces, so they can be included in a block in the
+// order they were sent
+func orderByNonce(transList []TransData) []TransData {
+	ordered := append([]TransData(nil), transList...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		si, sj := ordered[i].Sender().Hash(), ordered[j].Sender().Hash()
+		if si != sj {
+			return si < sj
+		}
+		return ordered[i].GetNonce() < ordered[j].GetNonce()
+	})
+	return ordered
+}
+
+// copyState is cheap, as the state tree is shared
 func copyState(s State) State {
-	return State{copyMap(s.Ledger), copyMap(s.ConStake), copyContMap(s.ConOwners), s.ParentHash, s.TotalStake}
+	s.Scheduled = copyScheduled(s.Scheduled)
+	return s
 }
 
 // Helpers
-func copyMap(originalMap map[string]uint64) map[string]uint64 {
-	if originalMap == nil {
-		return make(map[string]uint64)
-	}
-	newMap := make(map[string]uint64)
-	for key, value := range originalMap {
-		newMap[key] = value
-	}
-	return newMap
+func copyScheduled(original []smart.ScheduledCall) []smart.ScheduledCall {
+	return append([]smart.ScheduledCall(nil), original...)
+}
+
+// Returns the nonce the next transaction from the account with the given key hash must have in the current head
+func GetNextNonce(account string) uint64 {
+	tLock.RLock()
+	defer tLock.RUnlock()
+	return tree.treeMap[tree.head].state.NextNonce(account)
 }
 
-func copyContMap(originalMap map[string]crypto.PublicKey) map[string]crypto.PublicKey {
-	if originalMap == nil {
-		return make(map[string]crypto.PublicKey)
+// GetNextNonceAt returns the next nonce of account in the state after the given block, or 0 if the block hasn't been
+// processed yet
+func GetNextNonceAt(blockHash, account string) uint64 {
+	tLock.RLock()
+	defer tLock.RUnlock()
+	node, exists := tree.treeMap[blockHash]
+	if !exists {
+		return 0
 	}
-	newMap := make(map[string]crypto.PublicKey)
-	for key, value := range originalMap {
-		newMap[key] = value
+	return node.state.NextNonce(account)
+}
+
+// Finds the block on the current chain that includes the transaction with the given hash, and returns the block and
+// the Merkle proof that the transaction is in it
+func GetInclusionProof(transHash string) (Block, MerkleProof, bool) {
+	tLock.RLock()
+	defer tLock.RUnlock()
+	for node, exists := tree.treeMap[tree.head]; exists; node, exists = tree.treeMap[node.block.ParentPointer] {
+		if proof, err := node.block.BlockData.MerkleProof(transHash); err == nil {
+			return node.block, proof, true
+		}
 	}
-	return newMap
+	return Block{}, MerkleProof{}, false
+}
+
+// Returns the baking key registered by the account with the given key hash in the current head
+func GetBakingKey(account string) (crypto.PublicKey, bool) {
+	tLock.RLock()
+	defer tLock.RUnlock()
+	return tree.treeMap[tree.head].state.BakingKey(account)
+}
+
+// Returns the baker the account with the given key hash delegates to, and the accounts delegating to it, in the
+// current head
+func GetDelegation(account string) (string, []string) {
+	tLock.RLock()
+	defer tLock.RUnlock()
+	state := tree.treeMap[tree.head].state
+	return state.DelegatedTo(account), state.Delegators(account)
 }
 
 func GetCurrentLedger() map[string]uint64 {
 	tLock.RLock()
 	defer tLock.RUnlock()
-	return tree.treeMap[tree.head].state.Ledger
+	return tree.treeMap[tree.head].state.Ledger()
+}
+
+// Returns the value of key in the state of the current head with the proof of it, and the state root of the head
+func GetStateProof(key string) ([]byte, StateProof, string) {
+	tLock.RLock()
+	defer tLock.RUnlock()
+	state := tree.treeMap[tree.head].state
+	value, proof := state.Prove(key)
+	return value, proof, state.Root()
+}
+
+// Returns the pending scheduled calls to the contract at addr in the current head
+func GetScheduledCalls(addr string) []smart.ScheduledCall {
+	tLock.RLock()
+	defer tLock.RUnlock()
+	return tree.treeMap[tree.head].state.ScheduledTo(addr)
 }
 
 func SetVerbose(b bool) {
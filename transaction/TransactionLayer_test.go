@@ -1,27 +1,27 @@
 package transaction
 
 import (
-	"fmt"
 	. "github.com/nfk93/blockchain/crypto"
 	. "github.com/nfk93/blockchain/objects"
-	"strconv"
 	"testing"
 	"time"
 )
 
 func TestReceiveAndFinalizeBlock(t *testing.T) {
-	sk1, p1 := KeyGen(2048)
-	_, p2 := KeyGen(2048)
+	sk1, p1 := KeyGen()
+	_, p2 := KeyGen()
 
 	channels := CreateChannelStruct()
-	go StartTransactionLayer(channels)
+	go StartTransactionLayer(channels, false, nil)
 
 	GenBlock := CreateTestGenesis(p1)
 	channels.BlockToTrans <- GenBlock
+	time.Sleep(time.Millisecond * 300)
 
-	t1 := CreateTransaction(p1, p2, 200, "ID112", sk1)
-	t2 := CreateTransaction(p1, p2, 300, "ID222", sk1)
-	channels.TransToTrans <- CreateBlockData{[]TransData{{Transaction: t1}, {Transaction: t2}}, sk1, p1, 1, "", BlockNonce{}, ""}
+	t1 := CreateTransaction(p1, p2.Hash(), 200, 10000, 0, 0, sk1)
+	t2 := CreateTransaction(p1, p2.Hash(), 300, 10000, 1, 0, sk1)
+	channels.TransToTrans <- CreateBlockData{[]TransData{{Transaction: t1}, {Transaction: t2}}, sk1, p1, 1, "", BlockNonce{}, "",
+		GenBlock.CalculateBlockHash()}
 	b := <-channels.BlockFromTrans
 
 	channels.BlockToTrans <- b
@@ -31,8 +31,9 @@ func TestReceiveAndFinalizeBlock(t *testing.T) {
 
 	for {
 		state := <-channels.StateFromTrans
-		//we get 100 in block reward -> 999500+100 =999600
-		if state.Ledger[p1.Hash()] != 999600 || state.Ledger[p2.Hash()] != 500 {
+		// p1 bakes the block, so it gets the fees back together with the block reward
+		initialStake := GenBlock.BlockData.GenesisData.InitialState.Balance(p1.Hash())
+		if state.Balance(p1.Hash()) != initialStake-500+blockReward || state.Balance(p2.Hash()) != 500 {
 			t.Error("Something went wrong! Not the right state..")
 		}
 		return
@@ -41,54 +42,44 @@ func TestReceiveAndFinalizeBlock(t *testing.T) {
 
 func TestForking(t *testing.T) {
 
-	sk1, p1 := KeyGen(2048)
-	sk2, p2 := KeyGen(2048)
-	_, p3 := KeyGen(2048)
-	_, p4 := KeyGen(2048)
+	sk1, p1 := KeyGen()
+	sk2, p2 := KeyGen()
+	_, p3 := KeyGen()
+	_, p4 := KeyGen()
 
 	channels := CreateChannelStruct()
-	go StartTransactionLayer(channels)
-
-	go func() {
-		for {
-			// we finalize block 4, p1 = 999400, p2=150, p4=450
-			state := <-channels.StateFromTrans
-			for _, account := range state.Ledger {
-				fmt.Println(account)
-			}
-			fmt.Println("Total system stake: ", state.TotalStake)
-			//if state.Ledger[p1] != 999400 || state.Ledger[p2] != 150 || state.Ledger[p4] != 450 {
-			//	t.Error("Bad luck! Branching did not succeed")
-			//}
-			return
-		}
-	}()
+	go StartTransactionLayer(channels, false, nil)
 
 	GenBlock := CreateTestGenesis(p1)
 	channels.BlockToTrans <- GenBlock
+	time.Sleep(time.Millisecond * 300)
 
 	// Block 1, Grow from Genesis
-	t1 := CreateTransaction(p1, p4, 400, strconv.Itoa(1), sk1)
-	channels.TransToTrans <- CreateBlockData{[]TransData{{Transaction: t1}}, sk1, p1, 1, "", BlockNonce{}, ""}
+	t1 := CreateTransaction(p1, p4.Hash(), 400, 10000, 0, 0, sk1)
+	channels.TransToTrans <- CreateBlockData{[]TransData{{Transaction: t1}}, sk1, p1, 1, "", BlockNonce{}, "",
+		GenBlock.CalculateBlockHash()}
 	block1 := <-channels.BlockFromTrans
 	channels.BlockToTrans <- block1
 	time.Sleep(time.Millisecond * 300)
 
 	// Block 2 - grow from block 1
-	t2 := CreateTransaction(p1, p2, 200, strconv.Itoa(2), sk1)
-	channels.TransToTrans <- CreateBlockData{[]TransData{{Transaction: t2}}, sk2, p2, 2, "", BlockNonce{}, ""}
+	t2 := CreateTransaction(p1, p2.Hash(), 200, 10000, 1, 0, sk1)
+	channels.TransToTrans <- CreateBlockData{[]TransData{{Transaction: t2}}, sk2, p2, 2, "", BlockNonce{}, "",
+		block1.CalculateBlockHash()}
 	block2 := <-channels.BlockFromTrans
 	channels.BlockToTrans <- block2
 	time.Sleep(time.Millisecond * 100)
 
 	// Block 3 - grow from block 1
-	t3 := CreateTransaction(p1, p3, 300, strconv.Itoa(3), sk1)
-	channels.TransToTrans <- CreateBlockData{[]TransData{{Transaction: t3}}, sk2, p2, 3, "", BlockNonce{}, ""}
+	t3 := CreateTransaction(p1, p3.Hash(), 300, 10000, 1, 0, sk1)
+	channels.TransToTrans <- CreateBlockData{[]TransData{{Transaction: t3}}, sk2, p2, 3, "", BlockNonce{}, "",
+		block1.CalculateBlockHash()}
 	block3 := <-channels.BlockFromTrans
 
 	// Block 4 - grow from block 2
-	t4 := CreateTransaction(p2, p4, 50, strconv.Itoa(4), sk2)
-	channels.TransToTrans <- CreateBlockData{[]TransData{{Transaction: t4}}, sk2, p2, 4, "", BlockNonce{}, ""}
+	t4 := CreateTransaction(p2, p4.Hash(), 50, 10000, 0, 0, sk2)
+	channels.TransToTrans <- CreateBlockData{[]TransData{{Transaction: t4}}, sk2, p2, 4, "", BlockNonce{}, "",
+		block2.CalculateBlockHash()}
 	block4 := <-channels.BlockFromTrans
 
 	channels.BlockToTrans <- block3
@@ -98,16 +89,21 @@ func TestForking(t *testing.T) {
 	// Needs a bit of time for processing the block before finalizing it
 	time.Sleep(time.Millisecond * 1000)
 	channels.FinalizeToTrans <- block4.CalculateBlockHash()
-	time.Sleep(time.Millisecond * 1000)
 
+	// p2 bakes block 2 and block 4, and gets the fee of t2. Block 3 is on another branch than block 4, so its
+	// transaction to p3 isn't in the finalized state
+	state := <-channels.StateFromTrans
+	if state.Balance(p2.Hash()) != 2*blockReward+150+t2.Fee || state.Balance(p3.Hash()) != 0 || state.Balance(p4.Hash()) != 450 {
+		t.Error("Bad luck! Branching did not succeed")
+	}
 }
 
 func TestCreateNewBlock(t *testing.T) {
 
-	sk1, pk1 := KeyGen(2048)
-	_, pk2 := KeyGen(2048)
+	sk1, pk1 := KeyGen()
+	_, pk2 := KeyGen()
 	channels := CreateChannelStruct()
-	go StartTransactionLayer(channels)
+	go StartTransactionLayer(channels, false, nil)
 
 	genBlock := CreateTestGenesis(pk1)
 	channels.BlockToTrans <- genBlock
@@ -115,10 +111,10 @@ func TestCreateNewBlock(t *testing.T) {
 
 	var transList []TransData
 	for i := 0; i < 20; i++ {
-		t1 := TransData{Transaction: CreateTransaction(pk1, pk2, uint64(100+(i*100)), "ID"+strconv.Itoa(i), sk1)}
+		t1 := TransData{Transaction: CreateTransaction(pk1, pk2.Hash(), uint64(100+(i*100)), 10000, uint64(i), 0, sk1)}
 		transList = append(transList, t1)
 	}
-	newBlockData := CreateBlockData{transList, sk1, pk1, 2, "", BlockNonce{}, ""}
+	newBlockData := CreateBlockData{transList, sk1, pk1, 2, "", BlockNonce{}, "", genBlock.CalculateBlockHash()}
 
 	channels.TransToTrans <- newBlockData
 	newBlock := <-channels.BlockFromTrans
@@ -128,12 +124,104 @@ func TestCreateNewBlock(t *testing.T) {
 
 }
 
+func TestCreateNewBlockNonceChain(t *testing.T) {
+	sk1, pk1 := KeyGen()
+	_, pk2 := KeyGen()
+	channels := CreateChannelStruct()
+	go StartTransactionLayer(channels, false, nil)
+
+	genBlock := CreateTestGenesis(pk1)
+	channels.BlockToTrans <- genBlock
+	time.Sleep(time.Millisecond * 300)
+
+	nonces := func(b Block) []uint64 {
+		var result []uint64
+		for _, td := range b.BlockData.Trans {
+			result = append(result, td.GetNonce())
+		}
+		return result
+	}
+
+	// Nonce 3 has to wait for nonce 2, and the second transaction with nonce 0 replays the first
+	transList := []TransData{
+		{Transaction: CreateTransaction(pk1, pk2.Hash(), 100, 10000, 3, 0, sk1)},
+		{Transaction: CreateTransaction(pk1, pk2.Hash(), 100, 10000, 1, 0, sk1)},
+		{Transaction: CreateTransaction(pk1, pk2.Hash(), 100, 10000, 0, 0, sk1)},
+		{Transaction: CreateTransaction(pk1, pk2.Hash(), 200, 10000, 0, 0, sk1)},
+	}
+	channels.TransToTrans <- CreateBlockData{transList, sk1, pk1, 1, "", BlockNonce{}, "", genBlock.CalculateBlockHash()}
+	block1 := <-channels.BlockFromTrans
+	if got := nonces(block1); len(got) != 2 || got[0] != 0 || got[1] != 1 {
+		t.Errorf("Expected nonces [0 1] in block 1, got %v", got)
+	}
+	channels.BlockToTrans <- block1
+	time.Sleep(time.Millisecond * 300)
+
+	// Nonce 1 is replayed now, and nonce 2 lets nonce 3 in as well, unless nonce 2 expired
+	transList = []TransData{
+		{Transaction: CreateTransaction(pk1, pk2.Hash(), 100, 10000, 1, 0, sk1)},
+		{Transaction: CreateTransaction(pk1, pk2.Hash(), 100, 10000, 3, 0, sk1)},
+		{Transaction: CreateTransaction(pk1, pk2.Hash(), 100, 10000, 2, 0, sk1)},
+	}
+	channels.TransToTrans <- CreateBlockData{transList, sk1, pk1, 2, "", BlockNonce{}, "", block1.CalculateBlockHash()}
+	block2 := <-channels.BlockFromTrans
+	if got := nonces(block2); len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("Expected nonces [2 3] in block 2, got %v", got)
+	}
+
+	transList[2] = TransData{Transaction: CreateTransaction(pk1, pk2.Hash(), 100, 10000, 2, 1, sk1)}
+	channels.TransToTrans <- CreateBlockData{transList, sk1, pk1, 2, "", BlockNonce{}, "", block1.CalculateBlockHash()}
+	if got := nonces(<-channels.BlockFromTrans); len(got) != 0 {
+		t.Errorf("Expected no transactions after an expired nonce, got %v", got)
+	}
+}
+
+func TestProcessInvalidBlock(t *testing.T) {
+	sk1, pk1 := KeyGen()
+	_, pk2 := KeyGen()
+	channels := CreateChannelStruct()
+	go StartTransactionLayer(channels, false, nil)
+
+	genBlock := CreateTestGenesis(pk1)
+	channels.BlockToTrans <- genBlock
+	time.Sleep(time.Millisecond * 300)
+
+	trans := []TransData{{Transaction: CreateTransaction(pk1, pk2.Hash(), 100, 10000, 0, 0, sk1)}}
+	channels.TransToTrans <- CreateBlockData{trans, sk1, pk1, 1, "", BlockNonce{}, "", genBlock.CalculateBlockHash()}
+	block1 := <-channels.BlockFromTrans
+	channels.TransToTrans <- CreateBlockData{nil, sk1, pk1, 2, "", BlockNonce{}, "", genBlock.CalculateBlockHash()}
+	child := <-channels.BlockFromTrans
+
+	// a block with another state root than the state after it is invalid, and so is a block building on it
+	forged := block1
+	forged.StateRoot = "forged"
+	child.ParentPointer = forged.CalculateBlockHash()
+	for _, b := range []Block{forged, child} {
+		channels.BlockToTrans <- b
+		select {
+		case hash := <-channels.InvalidFromTrans:
+			if hash != b.CalculateBlockHash() {
+				t.Errorf("Expected block %s to be invalid, got %s", b.CalculateBlockHash(), hash)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Block %s should be reported as invalid", b.CalculateBlockHash())
+		}
+	}
+
+	channels.BlockToTrans <- block1
+	time.Sleep(time.Millisecond * 300)
+	channels.FinalizeToTrans <- block1.CalculateBlockHash()
+	if state := <-channels.StateFromTrans; state.Balance(pk2.Hash()) != 100 {
+		t.Error("The valid block should still be processed after the invalid one")
+	}
+}
+
 //func TestRuns(t *testing.T) { //Does not really test anything, but runs a lot of blocks that you can debug on the transactionLayer
-//	sk1, pk1 := KeyGen(2048)
-//	_, pk2 := KeyGen(2048)
+//	sk1, pk1 := KeyGen()
+//	_, pk2 := KeyGen()
 //
 //	channels := CreateChannelStruct()
-//	go StartTransactionLayer(channels)
+//	go StartTransactionLayer(channels, false, nil)
 //
 //	go func() {
 //		for {
@@ -147,7 +235,7 @@ func TestCreateNewBlock(t *testing.T) {
 //
 //	var transList []TransData
 //	for i := 0; i < 2; i++ {
-//		t1 := CreateTransaction(pk1, pk2, 100+(i*100), "ID"+strconv.Itoa(i), sk1)
+//		t1 := CreateTransaction(pk1, pk2.Hash(), 100+(i*100), "ID"+strconv.Itoa(i), sk1)
 //		transList = append(transList, t1)
 //	}
 //